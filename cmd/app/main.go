@@ -2,57 +2,83 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 
-	"github.com/Fivegen-LLC/sdwan-lib/pkg/logger"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/Fivegen-LLC/sdwan-agent/infrastructure"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/cli"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/logging"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/environment"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
 )
 
 var (
 	env            environment.Environment
 	serviceVersion = "0.0.1"
+
+	// loops writing to badger, database is closed only after all of them returned
+	badgerWriters sync.WaitGroup
 )
 
-func init() {
+func main() {
+	// subcommands of the local CLI talk to the running agent
+	if cli.IsCommand(os.Args[1:]) {
+		if err := cli.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr); err != nil {
+			if !errors.Is(err, cli.ErrUsage) && !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	var err error
 	if env, err = environment.New(); err != nil {
 		log.Fatal().Err(err).Msg("error loading environment")
 	}
-}
 
-func main() {
-	logWriter, err := setupRollingLogFile(env.Agent.LogfilePath)
+	rollingWriter, err := setupRollingLogFile(env.Agent.LogfilePath, env.Agent.LogRotation)
 	if err != nil {
 		log.Fatal().Err(err).Msg("main")
 	}
 
-	log.Output(logWriter)
-	if err = logger.SetLogLevel(env.Agent.LogLevel); err != nil {
+	logLevel, err := zerolog.ParseLevel(env.Agent.LogLevel)
+	if err != nil {
 		log.Fatal().Err(err).Msg("main")
 	}
 
+	// level filtering is done by global level and log writer (allows runtime level changes per component)
+	// secrets are masked in every written line
+	logWriter := logging.NewLevelWriter(redact.NewWriter(rollingWriter), logLevel)
+	log.Logger = log.Output(logWriter).Level(zerolog.TraceLevel)
+
 	log.Info().
-		Any("app", env).
+		Any("app", redact.Value(env)).
 		Str("agent version", serviceVersion).
 		Str("log path", env.Agent.LogfilePath).
 		Str("log level", env.Agent.LogLevel).
 		Str("device type", env.DeviceType).
+		Str("config file", env.Agent.ConfigFile).
 		Msg("main: app started")
 
 	cancelCtx, cancelFunc := signal.NotifyContext(context.Background(), os.Kill, os.Interrupt, syscall.SIGTERM)
 	defer cancelFunc()
 
-	kernel, err := infrastructure.Inject(env)
+	kernel, err := infrastructure.Inject(env, logWriter)
 	if err != nil {
 		log.Fatal().Err(err).Msg("main")
 	}
@@ -63,6 +89,8 @@ func main() {
 	}
 	log.Info().Msg("main: app services initialized")
 
+	go reloadOnSignal(cancelCtx, kernel)
+
 	<-cancelCtx.Done()
 
 	log.Info().Msg("main: stopping app...")
@@ -77,7 +105,7 @@ func initServices(ctx context.Context, kernel *infrastructure.Kernel) (err error
 	// connect to message broker
 	log.Info().Msg("initServices: connecting to MQ broker...")
 	mqService := kernel.InjectMQService()
-	mqService.RegisterHandlers(getMQRoutes(kernel))
+	mqService.RegisterHandlers(trackMQRoutes(kernel.InjectRequestTracker(), getMQRoutes(kernel)))
 	if err = mqService.Connect(); err != nil {
 		return fmt.Errorf("initServices: connection to message broker failed")
 	}
@@ -104,39 +132,101 @@ func initServices(ctx context.Context, kernel *infrastructure.Kernel) (err error
 	}
 
 	// activate always available handlers
-	if err = mqService.ActivateHandler(constants.MQAgentGetConfig); err != nil {
-		return fmt.Errorf("initServices: %w", err)
+	for _, subject := range []string{
+		constants.MQAgentGetConfig,
+		constants.MQAgentHubListPorts,
+		constants.MQAgentDebugDumpHeap,
+		constants.MQAgentDebugProfile,
+		constants.MQAgentDebugChunk,
+		constants.MQAgentDebugStatus,
+		constants.MQAgentDebugStats,
+		constants.MQAgentLogSetLevel,
+		constants.MQAgentLogResetLevel,
+		constants.MQAgentLogGetLevels,
+		constants.MQAgentLogTail,
+		constants.MQAgentHealthPing,
+		constants.MQAgentUpdateProgress,
+	} {
+		if err = mqService.ActivateHandler(subject); err != nil {
+			return fmt.Errorf("initServices: %w", err)
+		}
 	}
 
-	if err = mqService.ActivateHandler(constants.MQAgentHubListPorts); err != nil {
-		return fmt.Errorf("initServices: %w", err)
-	}
+	go kernel.InjectCommandBufferService().Start(ctx)
 
-	if err = mqService.ActivateHandler(constants.MQAgentDebugDumpHeap); err != nil {
-		return fmt.Errorf("initServices: %w", err)
-	}
+	log.Info().Msg("initServices: starting local server...")
+	localServer := kernel.InjectLocalServer()
+	localServer.SetRoutes(getHTTPRoutes(kernel))
+	goBadgerWriter(ctx, localServer.Start) // health probes
 
-	go kernel.InjectCommandBufferService().Start(ctx)
+	log.Info().Msg("initServices: starting admin server...")
+	adminServer := kernel.InjectAdminServer()
+	adminServer.SetRoutes(trackHTTPRoutes(kernel.InjectRequestTracker(), getAdminRoutes(kernel)))
+	goBadgerWriter(ctx, adminServer.Start)
 
 	log.Info().Msg("initServices: starting monitoring service...")
 	go kernel.InjectPonyService().Start(ctx)
 	go kernel.InjectPonyEventService().StartListenEvents(ctx)
+	go kernel.InjectEventService().Start(ctx)
+	goBadgerWriter(ctx, kernel.InjectLTEMonitor().Start)
+	go kernel.InjectPortStatsService().Start(ctx)
 	log.Info().Msg("initServices: monitoring service started")
 
 	// start agent main logic controller
 	log.Info().Msg("initServices: starting app state controller...")
 	kernel.BuildAppStateService()
 	go kernel.InjectAppStateService().Run(ctx)
+	goBadgerWriter(ctx, kernel.InjectDataUsageService().Start)
+	goBadgerWriter(ctx, kernel.InjectDriftService().Start)
+	goBadgerWriter(ctx, kernel.InjectOfflineService().Start)
 	log.Info().Msg("initServices: app state controller started")
 
 	log.Info().Msg("initServices: starting discovery service...")
 	go kernel.InjectDiscoveryMonitoringService().Start(ctx)
 	log.Info().Msg("initServices: discovery service started")
 
+	// readiness is reported once services are started, boot transitions are reported in status
+	go kernel.InjectSDNotifyService().Start(ctx)
+
 	return nil
 }
 
+// shutdownServices waits for running handlers and state transition (up to shutdown timeout)
+// before closing connections, badger is closed last so that transactions are finished or rolled back.
 func shutdownServices(kernel *infrastructure.Kernel) {
+	kernel.InjectSDNotifyService().Stopping()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), kernel.Settings.Tunables().ShutdownTimeout)
+	defer cancel()
+
+	// handlers may wait for transition result, both are drained concurrently
+	var (
+		wg     sync.WaitGroup
+		dbBusy atomic.Bool
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := kernel.InjectRequestTracker().Drain(shutdownCtx); err != nil {
+			dbBusy.Store(true)
+			log.Error().Err(err).Msg("shutdownServices: in-flight handlers are not finished")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := kernel.InjectAppStateService().Wait(shutdownCtx); err != nil {
+			dbBusy.Store(true)
+			log.Error().Err(err).Msg("shutdownServices: state transition is not finished")
+		}
+	}()
+	wg.Wait()
+
+	// background loops are stopped by canceled app context, wait for their last iteration
+	if err := waitBadgerWriters(shutdownCtx); err != nil {
+		dbBusy.Store(true)
+		log.Error().Err(err).Msg("shutdownServices: background services are not stopped")
+	}
+
 	if err := kernel.InjectWebsocketService().Stop(); err != nil {
 		log.Error().Err(err).Msg("shutdownServices: websocket service shutdown error")
 	}
@@ -145,12 +235,84 @@ func shutdownServices(kernel *infrastructure.Kernel) {
 		log.Error().Err(err).Msg("shutdownServices: close MQ error")
 	}
 
+	// closing badger under running writer panics or loses the write, process exit releases database instead
+	if dbBusy.Load() {
+		log.Error().Msg("shutdownServices: badger is still in use, close skipped")
+		return
+	}
+
 	if err := kernel.DB.Close(); err != nil {
 		log.Error().Err(err).Msg("shutdownServices: close badger error")
 	}
 }
 
-func setupRollingLogFile(filename string) (logWriter *lumberjack.Logger, err error) {
+// goBadgerWriter starts service loop tracked by shutdown, loop must return when context is done.
+func goBadgerWriter(ctx context.Context, start func(ctx context.Context)) {
+	badgerWriters.Add(1)
+	go func() {
+		defer badgerWriters.Done()
+		start(ctx)
+	}()
+}
+
+func waitBadgerWriters(ctx context.Context) (err error) {
+	done := make(chan struct{})
+	go func() {
+		badgerWriters.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waitBadgerWriters: %w", ctx.Err())
+	}
+}
+
+// reloadOnSignal reloads settings on SIGHUP (systemctl reload sdwan-agent).
+func reloadOnSignal(ctx context.Context, kernel *infrastructure.Kernel) {
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	defer signal.Stop(reloadChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reloadChan:
+			reloadSettings(kernel)
+		}
+	}
+}
+
+// reloadSettings applies log level and tunables from config file, invalid file keeps current settings.
+func reloadSettings(kernel *infrastructure.Kernel) {
+	newEnv, err := environment.New()
+	if err != nil {
+		log.Error().Err(err).Msg("reloadSettings: load settings error, current settings are kept")
+		return
+	}
+
+	if settings := env.Agent.RestartRequired(newEnv.Agent); len(settings) > 0 {
+		log.Warn().
+			Strs("settings", settings).
+			Msg("reloadSettings: changed settings are applied after agent restart")
+	}
+
+	if err = kernel.InjectLoggingService().SetDefaultLevel(newEnv.Agent.LogLevel); err != nil {
+		log.Error().Err(err).Msg("reloadSettings: set log level error")
+	}
+	kernel.Settings.Store(newEnv.Agent.Tunables)
+
+	log.Info().
+		Str("config file", newEnv.Agent.ConfigFile).
+		Str("log level", newEnv.Agent.LogLevel).
+		Any("tunables", newEnv.Agent.Tunables).
+		Msg("reloadSettings: settings reloaded")
+}
+
+func setupRollingLogFile(filename string, rotation environment.LogRotation) (logWriter *lumberjack.Logger, err error) {
 	// create log dir if not exists
 	if err = os.MkdirAll(filepath.Dir(filename), constants.LogFilePerm); err != nil {
 		return logWriter, fmt.Errorf("setupRollingLogFile: %w", err)
@@ -171,9 +333,9 @@ func setupRollingLogFile(filename string) (logWriter *lumberjack.Logger, err err
 
 	return &lumberjack.Logger{
 		Filename:   filename,
-		MaxSize:    15,   // 100 megabytes per log file
-		MaxAge:     30,   // store retained log files for 30 days
-		MaxBackups: 10,   // store maximum 10 retained log files
-		Compress:   true, // compress files wia gzip
+		MaxSize:    rotation.MaxSizeMB,  // megabytes per log file
+		MaxAge:     rotation.MaxAgeDays, // days to store retained log files
+		MaxBackups: rotation.MaxBackups, // maximum number of retained log files
+		Compress:   rotation.Compress,   // compress files via gzip
 	}, nil
 }
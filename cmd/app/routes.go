@@ -1,11 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
 	"github.com/nats-io/nats.go"
 
 	"github.com/Fivegen-LLC/sdwan-agent/infrastructure"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/websocket"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/inflight"
 )
 
 func getWebsocketRoutes(injector infrastructure.IInjector) map[string]websocket.WsHandler {
@@ -25,11 +32,20 @@ func getWebsocketRoutes(injector infrastructure.IInjector) map[string]websocket.
 	appStateHandler := injector.InjectAppStateWSHandler()
 	updateManagerHandler := injector.InjectUpdateManagerHandler()
 	lteHandler := injector.InjectLTEHandler()
+	dataUsageHandler := injector.InjectDataUsageHandler()
+	driftHandler := injector.InjectDriftHandler()
+	loggingHandler := injector.InjectLoggingHandler()
+	reachabilityHandler := injector.InjectReachabilityHandler()
+	captureHandler := injector.InjectCaptureHandler()
+	debugHandler := injector.InjectDebugHandler()
 
 	return map[string]websocket.WsHandler{
 		constants.MethodCommand:                cmdHandler.ExecCommand,
 		constants.MethodUpdateAllConfigs:       configHandler.UpdateAllConfigs,
 		constants.MethodUpdateWgPeer:           configHandler.UpdateWgPeer,
+		constants.MethodPatchWgPeers:           configHandler.PatchWgPeers,
+		constants.MethodExportConfig:           configHandler.ExportConfig,
+		constants.MethodImportConfig:           configHandler.ImportConfig,
 		constants.MethodFetchPorts:             portHandler.FetchPorts,
 		constants.MethodFetchPortConfigs:       portHandler.FetchPortConfigs,
 		constants.MethodFetchTunnelStates:      ponyHandler.FetchTunnelStates,
@@ -52,8 +68,33 @@ func getWebsocketRoutes(injector infrastructure.IInjector) map[string]websocket.
 		constants.MethodDownloadDevicePackages: updateManagerHandler.DownloadDevicePackages,
 		constants.MethodInstallDevicePackages:  updateManagerHandler.InstallDevicePackages,
 		constants.MethodGetPackagesVersions:    updateManagerHandler.GetPackagesVersions,
+		constants.MethodCancelDownloadPackages: updateManagerHandler.CancelDownloadPackages,
+		constants.MethodResumeDownloadPackages: updateManagerHandler.ResumeDownloadPackages,
+		constants.MethodGetDownloadJobs:        updateManagerHandler.GetDownloadJobs,
 		constants.MethodLTEFetchStats:          lteHandler.FetchStats,
 		constants.MethodLTEResetModem:          lteHandler.ResetModem,
+		constants.MethodLTEFetchHistory:        lteHandler.FetchHistory,
+		constants.MethodLTEGetSIMSettings:      lteHandler.GetSIMSettings,
+		constants.MethodLTEUnlockSIM:           lteHandler.UnlockSIM,
+		constants.MethodLTESelectSIMSlot:       lteHandler.SelectSIMSlot,
+		constants.MethodLTESetAPNProfiles:      lteHandler.SetAPNProfiles,
+		constants.MethodLTESetSIMFailover:      lteHandler.SetSIMFailover,
+		constants.MethodGetDataUsage:           dataUsageHandler.GetDataUsage,
+		constants.MethodSetDataUsagePolicies:   dataUsageHandler.SetDataUsagePolicies,
+		constants.MethodGetConfigDrift:         driftHandler.GetConfigDrift,
+		constants.MethodGetDriftSettings:       driftHandler.GetDriftSettings,
+		constants.MethodSetDriftSettings:       driftHandler.SetDriftSettings,
+		constants.MethodSetLogLevel:            loggingHandler.SetLogLevel,
+		constants.MethodResetLogLevel:          loggingHandler.ResetLogLevel,
+		constants.MethodGetLogLevels:           loggingHandler.GetLogLevels,
+		constants.MethodTailLog:                loggingHandler.TailLog,
+		constants.MethodPing:                   reachabilityHandler.Ping,
+		constants.MethodTraceroute:             reachabilityHandler.Traceroute,
+		constants.MethodDiscoverPathMTU:        reachabilityHandler.DiscoverPathMTU,
+		constants.MethodStartPacketCapture:     captureHandler.StartPacketCapture,
+		constants.MethodStopPacketCapture:      captureHandler.StopPacketCapture,
+		constants.MethodDebugProfile:           debugHandler.Profile,
+		constants.MethodDebugRuntimeStats:      debugHandler.GetRuntimeStats,
 	}
 }
 
@@ -63,6 +104,9 @@ func getMQRoutes(injector infrastructure.IInjector) map[string]func(m *nats.Msg)
 	deviceActionMQHandler := injector.InjectDeviceActionMQHandler()
 	hubMQHandler := injector.InjectHubMQHandler()
 	debugMQHandler := injector.InjectDebugMQHandler()
+	loggingMQHandler := injector.InjectLoggingMQHandler()
+	healthMQHandler := injector.InjectHealthMQHandler()
+	updateManagerMQHandler := injector.InjectUpdateManagerMQHandler()
 
 	return map[string]func(m *nats.Msg) (resp any){
 		constants.MQAgentZTPFirstSetup:   ztpMQHandler.RunFirstSetup,
@@ -76,5 +120,77 @@ func getMQRoutes(injector infrastructure.IInjector) map[string]func(m *nats.Msg)
 		constants.MQAgentHubListPorts:    hubMQHandler.ListPorts,
 		constants.MQAgentHubInit:         hubMQHandler.Init,
 		constants.MQAgentDebugDumpHeap:   debugMQHandler.DumpHeap,
+		constants.MQAgentDebugProfile:    debugMQHandler.Profile,
+		constants.MQAgentDebugChunk:      debugMQHandler.GetProfileChunk,
+		constants.MQAgentDebugStatus:     debugMQHandler.GetProfile,
+		constants.MQAgentDebugStats:      debugMQHandler.GetRuntimeStats,
+		constants.MQAgentLogSetLevel:     loggingMQHandler.SetLogLevel,
+		constants.MQAgentLogResetLevel:   loggingMQHandler.ResetLogLevel,
+		constants.MQAgentLogGetLevels:    loggingMQHandler.GetLogLevels,
+		constants.MQAgentLogTail:         loggingMQHandler.TailLog,
+		constants.MQAgentHealthPing:      healthMQHandler.Ping,
+		constants.MQAgentUpdateProgress:  updateManagerMQHandler.DownloadProgress,
+	}
+}
+
+// trackMQRoutes registers running MQ handlers in tracker, so shutdown waits for them.
+func trackMQRoutes(tracker *inflight.Tracker, routes map[string]func(m *nats.Msg) (resp any)) map[string]func(m *nats.Msg) (resp any) {
+	tracked := make(map[string]func(m *nats.Msg) (resp any), len(routes))
+	for subject, handler := range routes {
+		tracked[subject] = func(m *nats.Msg) (resp any) {
+			if !tracker.Acquire() {
+				return mq.NewErrorResponse(http.StatusServiceUnavailable, errs.ErrAgentShuttingDown.Error())
+			}
+			defer tracker.Release()
+
+			return handler(m)
+		}
+	}
+
+	return tracked
+}
+
+// trackHTTPRoutes registers running HTTP handlers in tracker, so shutdown waits for them.
+func trackHTTPRoutes(tracker *inflight.Tracker, routes map[string]http.HandlerFunc) map[string]http.HandlerFunc {
+	tracked := make(map[string]http.HandlerFunc, len(routes))
+	for pattern, handler := range routes {
+		tracked[pattern] = func(w http.ResponseWriter, r *http.Request) {
+			if !tracker.Acquire() {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_ = json.NewEncoder(w).Encode(entities.AdminError{Error: errs.ErrAgentShuttingDown.Error()})
+				return
+			}
+			defer tracker.Release()
+
+			handler(w, r)
+		}
+	}
+
+	return tracked
+}
+
+func getHTTPRoutes(injector infrastructure.IInjector) map[string]http.HandlerFunc {
+	metricsHandler := injector.InjectMetricsHandler()
+	healthHandler := injector.InjectHealthHandler()
+
+	return map[string]http.HandlerFunc{
+		"GET " + constants.MetricsPath: metricsHandler.ServeMetrics,
+		"GET " + constants.HealthPath:  healthHandler.ServeHealth,
+	}
+}
+
+func getAdminRoutes(injector infrastructure.IInjector) map[string]http.HandlerFunc {
+	adminHandler := injector.InjectAdminHandler()
+
+	return map[string]http.HandlerFunc{
+		"GET " + constants.AdminStatusPath:       adminHandler.ServeStatus,
+		"GET " + constants.AdminStateHistoryPath: adminHandler.ServeStateHistory,
+		"GET " + constants.AdminConfigPath:       adminHandler.ServeConfig,
+		"GET " + constants.AdminTxPath:           adminHandler.ServeTransactions,
+		"POST " + constants.AdminTxRollbackPath:  adminHandler.ServeRollbackTransactions,
+		"GET " + constants.AdminDiagBundlePath:   adminHandler.ServeDiagBundle,
+		"POST " + constants.AdminLogLevelPath:    adminHandler.ServeSetLogLevel,
+		"POST " + constants.AdminReconnectPath:   adminHandler.ServeReconnect,
 	}
 }
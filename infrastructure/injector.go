@@ -7,21 +7,29 @@ import (
 	"github.com/dgraph-io/badger/v4"
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/admin"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/capture"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/cmd"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/config"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/datausage"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/debug"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/deviceaction"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/deviceinit"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/dhcp"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/drift"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/fw"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/health"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/hub"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/isb"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/l3"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/logging"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/metrics"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/ovs"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/pony"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/port"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/reachability"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/service"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/trunk"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/updatemanager"
@@ -47,6 +55,12 @@ type IInjector interface {
 	InjectAppStateWSHandler() *appstate.WSHandler
 	InjectUpdateManagerHandler() *updatemanager.Handler
 	InjectLTEHandler() *lte.Handler
+	InjectDataUsageHandler() *datausage.Handler
+	InjectDriftHandler() *drift.Handler
+	InjectLoggingHandler() *logging.Handler
+	InjectReachabilityHandler() *reachability.Handler
+	InjectCaptureHandler() *capture.Handler
+	InjectDebugHandler() *debug.Handler
 
 	// MQ handlers.
 
@@ -55,22 +69,36 @@ type IInjector interface {
 	InjectDeviceActionMQHandler() *deviceaction.MQHandler
 	InjectHubMQHandler() *hub.MQHandler
 	InjectDebugMQHandler() *debug.MQHandler
+	InjectLoggingMQHandler() *logging.MQHandler
+	InjectHealthMQHandler() *health.MQHandler
+	InjectUpdateManagerMQHandler() *updatemanager.MQHandler
+
+	// HTTP handlers.
+
+	InjectMetricsHandler() *metrics.Handler
+	InjectHealthHandler() *health.Handler
+	InjectAdminHandler() *admin.Handler
 }
 
 type Kernel struct {
-	env environment.Environment
+	env       environment.Environment
+	logWriter *logging.LevelWriter
 
-	DB *badger.DB
+	DB       *badger.DB
+	Settings *environment.Settings // reloadable settings
 }
 
-func Inject(env environment.Environment) (k *Kernel, err error) {
+func Inject(env environment.Environment, logWriter *logging.LevelWriter) (k *Kernel, err error) {
 	k = &Kernel{
-		env: env,
+		env:       env,
+		logWriter: logWriter,
+
+		Settings: environment.NewSettings(env.Agent.Tunables),
 	}
 
-	options := badger.DefaultOptions(constants.AgentConfigPath).
+	options := badger.DefaultOptions(env.Agent.BadgerDir).
 		WithLogger(badgerutils.NewLogger()).
-		WithMemTableSize(64 << 17) // ~8MB
+		WithMemTableSize(env.Agent.BadgerMemTableSize)
 
 	if k.DB, err = badger.Open(options); err != nil {
 		return k, fmt.Errorf("Inject: %w", err)
@@ -111,6 +139,7 @@ func (k *Kernel) InjectConfigHandler() *config.Handler {
 		k.InjectMessagePublisher(),
 		k.InjectAppStateService(),
 		k.InjectConfigService(),
+		k.env.Agent.DeviceType,
 	)
 }
 
@@ -211,6 +240,7 @@ func (k *Kernel) InjectUpdateManagerHandler() *updatemanager.Handler {
 	return updatemanager.NewHandler(
 		k.InjectUpdateManagerService(),
 		k.InjectMessagePublisher(),
+		k.InjectPackageVerifier(),
 	)
 }
 
@@ -224,9 +254,93 @@ func (k *Kernel) InjectLTEHandler() *lte.Handler {
 	return lte.NewHandler(
 		k.InjectMessagePublisher(),
 		k.InjectLTEService(),
+		k.InjectLTEHistoryStore(),
+		k.InjectLTESettingsStore(),
+	)
+}
+
+func (k *Kernel) InjectDataUsageHandler() *datausage.Handler {
+	return datausage.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectDataUsageService(),
+	)
+}
+
+func (k *Kernel) InjectDriftHandler() *drift.Handler {
+	return drift.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectDriftService(),
 	)
 }
 
 func (k *Kernel) InjectDebugMQHandler() *debug.MQHandler {
-	return debug.NewMQHandler()
+	return debug.NewMQHandler(
+		k.InjectDebugService(),
+	)
+}
+
+func (k *Kernel) InjectLoggingHandler() *logging.Handler {
+	return logging.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectLoggingService(),
+	)
+}
+
+func (k *Kernel) InjectReachabilityHandler() *reachability.Handler {
+	return reachability.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectReachabilityService(),
+	)
+}
+
+func (k *Kernel) InjectDebugHandler() *debug.Handler {
+	return debug.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectDebugService(),
+		k.InjectTransferService(),
+		k.env.Agent.DebugWS,
+	)
+}
+
+func (k *Kernel) InjectCaptureHandler() *capture.Handler {
+	return capture.NewHandler(
+		k.InjectMessagePublisher(),
+		k.InjectCaptureService(),
+	)
+}
+
+func (k *Kernel) InjectLoggingMQHandler() *logging.MQHandler {
+	return logging.NewMQHandler(
+		k.InjectLoggingService(),
+	)
+}
+
+func (k *Kernel) InjectHealthMQHandler() *health.MQHandler {
+	return health.NewMQHandler()
+}
+
+func (k *Kernel) InjectUpdateManagerMQHandler() *updatemanager.MQHandler {
+	return updatemanager.NewMQHandler(
+		k.InjectUpdateManagerService(),
+	)
+}
+
+// HTTP handlers.
+
+func (k *Kernel) InjectMetricsHandler() *metrics.Handler {
+	return metrics.NewHandler(
+		k.InjectMetricsService(),
+	)
+}
+
+func (k *Kernel) InjectHealthHandler() *health.Handler {
+	return health.NewHandler(
+		k.InjectHealthService(),
+	)
+}
+
+func (k *Kernel) InjectAdminHandler() *admin.Handler {
+	return admin.NewHandler(
+		k.InjectAdminService(),
+	)
 }
@@ -41,29 +41,46 @@ import (
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/systemd"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat/wsclient"
-	"github.com/nats-io/nats.go"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/admin"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/handlers"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/capture"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/connection"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/datausage"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/debug"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/deviceinit"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/discovery"
 	dMonitoring "github.com/Fivegen-LLC/sdwan-agent/internal/domains/discovery/monitoring"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/drift"
 
 	dClient "github.com/Fivegen-LLC/sdwan-agent/internal/domains/discovery/httpclient"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/dumpstat"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/event"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/firstport"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/grafana"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/health"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/hostname"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/hub"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/localserver"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/logging"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/metrics"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/nslookup"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/offline"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/ovs"
+	agentPony "github.com/Fivegen-LLC/sdwan-agent/internal/domains/pony"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/pony/ponyevent"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/port"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/portstats"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/reachability"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/sdnotify"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/transfer"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/updatemanager"
 	ws "github.com/Fivegen-LLC/sdwan-agent/internal/domains/websocket"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/inflight"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/netstats"
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 )
@@ -112,6 +129,7 @@ func (k *Kernel) InjectPortService() *port.Service {
 			k.InjectPonyService(),
 			k.InjectShellService(),
 			k.InjectAppStateService(),
+			k.InjectPortStatsService(),
 		)
 	})
 
@@ -170,7 +188,7 @@ var (
 func (k *Kernel) InjectPonyService() *pony.Service {
 	ponyServiceOnce.Do(func() {
 		ponyService = pony.NewService(
-			k.InjectConfigService(),
+			k.InjectPonyProgressService(),
 			k.InjectConnectionService(),
 			k.InjectPonyRouteService(),
 		)
@@ -217,7 +235,7 @@ var (
 
 func (k *Kernel) InjectOVSService() *ovs.Service {
 	ovsServiceOnce.Do(func() {
-		ovsService = ovs.NewService()
+		ovsService = ovs.NewService(k.DB)
 	})
 
 	return ovsService
@@ -285,14 +303,69 @@ func (k *Kernel) InjectGrafanaService() *grafana.Service {
 	grafanaServiceOnce.Do(func() {
 		grafanaService = grafana.NewService(
 			constants.GrafanaConfigPath,
+			constants.GrafanaAgentConfigPath,
+			constants.GrafanaScrapeConfigPath,
 			constants.GrafanaMimirPort,
 			constants.GrafanaLokiPort,
+			constants.LocalServerAddr,
+			constants.MetricsPath,
 		)
 	})
 
 	return grafanaService
 }
 
+var (
+	wireguardGenerator     *wireguard.Service
+	wireguardGeneratorOnce sync.Once
+)
+
+func (k *Kernel) InjectWireguardGenerator() *wireguard.Service {
+	wireguardGeneratorOnce.Do(func() {
+		wireguardGenerator = wireguard.NewService(
+			k.InjectCmdService(),
+			k.InjectWGConfigService(),
+			k.InjectActivityService(),
+		)
+	})
+
+	return wireguardGenerator
+}
+
+var (
+	loopbackGenerator     *loopback.Service
+	loopbackGeneratorOnce sync.Once
+)
+
+func (k *Kernel) InjectLoopbackGenerator() *loopback.Service {
+	loopbackGeneratorOnce.Do(func() {
+		loopbackGenerator = loopback.NewService(
+			k.InjectCmdService(),
+			k.InjectNetInitService(),
+			k.InjectActivityService(),
+		)
+	})
+
+	return loopbackGenerator
+}
+
+var (
+	ipRuleGenerator     *iprule.Service
+	ipRuleGeneratorOnce sync.Once
+)
+
+func (k *Kernel) InjectIPRuleGenerator() *iprule.Service {
+	ipRuleGeneratorOnce.Do(func() {
+		ipRuleGenerator = iprule.NewService(
+			k.InjectCmdService(),
+			k.InjectNetInitService(),
+			k.InjectActivityService(),
+		)
+	})
+
+	return ipRuleGenerator
+}
+
 var (
 	configService     *config.Service
 	configServiceOnce sync.Once
@@ -369,21 +442,9 @@ func (k *Kernel) InjectConfigService() *config.Service {
 		configService = config.NewService(
 			k.DB,
 			[]config.IRuleGenerator{
-				wireguard.NewService(
-					k.InjectCmdService(),
-					k.InjectWGConfigService(),
-					k.InjectActivityService(),
-				),
-				loopback.NewService(
-					k.InjectCmdService(),
-					k.InjectNetInitService(),
-					k.InjectActivityService(),
-				),
-				iprule.NewService(
-					k.InjectCmdService(),
-					k.InjectNetInitService(),
-					k.InjectActivityService(),
-				),
+				k.InjectWireguardGenerator(),
+				k.InjectLoopbackGenerator(),
+				k.InjectIPRuleGenerator(),
 				wanprotection.NewService(
 					k.InjectCmdService(),
 					k.InjectNetInitService(),
@@ -509,6 +570,7 @@ func (k *Kernel) InjectUpdateManagerService() *updatemanager.Service {
 		updateManagerService = updatemanager.NewService(
 			k.InjectAppStateService(),
 			k.InjectMQService(),
+			k.InjectMessagePublisher(),
 			constants.CLIExtExecutable,
 		)
 	})
@@ -552,6 +614,7 @@ func (k *Kernel) BuildAppStateService() {
 				k.InjectFirstPortService(),
 				k.InjectDeviceInitService(),
 				k.InjectActivityService(),
+				k.Settings,
 				k.env.Agent.DeviceType,
 			),
 			handlers.NewUpdateConfigStateHandler(
@@ -560,6 +623,11 @@ func (k *Kernel) BuildAppStateService() {
 				k.InjectPingService(),
 				k.InjectNSLookupService(),
 				k.InjectWebsocketService(),
+				map[string]handlers.ISectionGenerator{
+					entities.DriftSectionIPRule:    k.InjectIPRuleGenerator(),
+					entities.DriftSectionLoopback:  k.InjectLoopbackGenerator(),
+					entities.DriftSectionWireguard: k.InjectWireguardGenerator(),
+				},
 				k.env.Agent.DeviceType,
 			),
 			handlers.NewMaintenanceStateHandler(
@@ -568,6 +636,14 @@ func (k *Kernel) BuildAppStateService() {
 				k.InjectMessagePublisher(),
 				k.InjectWebsocketService(),
 				k.InjectActivityService(),
+				k.InjectHealthService(),
+				k.InjectShellService(),
+				k.InjectUpgradeStore(),
+				k.InjectEventService(),
+				k.InjectPackageVerifier(),
+				k.InjectUpdateManagerUpgrader(),
+				k.InjectAgentRollbackGuard(),
+				k.Settings,
 			),
 			handlers.NewZTPSetupHandler(
 				k.InjectConfigService(),
@@ -613,7 +689,7 @@ var (
 
 func (k *Kernel) InjectMQService() *mq.Service {
 	mqServiceOnce.Do(func() {
-		mqService = mq.NewService(nats.DefaultURL)
+		mqService = mq.NewService(k.env.Agent.NATSURL)
 	})
 
 	return mqService
@@ -629,7 +705,8 @@ func (k *Kernel) InjectWebsocketService() *ws.Service {
 		websocketService = ws.NewService(
 			k.InjectMessagePublisher(),
 			k.InjectDumpStatService(),
-			constants.WSPingPeriod,
+			k.InjectRequestTracker(),
+			k.env.Agent.WSPingPeriod,
 		)
 	})
 
@@ -770,6 +847,57 @@ func (k *Kernel) InjectLTEService() *lte.Service {
 	return lteService
 }
 
+var (
+	lteHistoryStore     *lte.HistoryStore
+	lteHistoryStoreOnce sync.Once
+)
+
+func (k *Kernel) InjectLTEHistoryStore() *lte.HistoryStore {
+	lteHistoryStoreOnce.Do(func() {
+		lteHistoryStore = lte.NewHistoryStore(
+			k.DB,
+		)
+	})
+
+	return lteHistoryStore
+}
+
+var (
+	lteSettingsStore     *lte.SettingsStore
+	lteSettingsStoreOnce sync.Once
+)
+
+func (k *Kernel) InjectLTESettingsStore() *lte.SettingsStore {
+	lteSettingsStoreOnce.Do(func() {
+		lteSettingsStore = lte.NewSettingsStore(
+			k.DB,
+			k.env.Agent.LTEPINKeyFile,
+		)
+	})
+
+	return lteSettingsStore
+}
+
+var (
+	lteMonitor     *lte.Monitor
+	lteMonitorOnce sync.Once
+)
+
+func (k *Kernel) InjectLTEMonitor() *lte.Monitor {
+	lteMonitorOnce.Do(func() {
+		lteMonitor = lte.NewMonitor(
+			k.InjectLTEService(),
+			k.InjectLTEHistoryStore(),
+			k.InjectEventService(),
+			k.InjectLTESettingsStore(),
+			lte.DefaultMonitorInterval,
+			lte.DefaultStallTimeout,
+		)
+	})
+
+	return lteMonitor
+}
+
 var (
 	dumpStatService     *dumpstat.Service
 	dumpStatServiceOnce sync.Once
@@ -780,6 +908,7 @@ func (k *Kernel) InjectDumpStatService() *dumpstat.Service {
 		dumpStatService = dumpstat.NewService(
 			k.InjectConfigService(),
 			k.InjectShellService(),
+			k.InjectPortStatsService(),
 		)
 	})
 
@@ -828,6 +957,7 @@ func (k *Kernel) InjectDiscoveryMonitoringService() *dMonitoring.Service {
 			k.InjectMessagePublisher(),
 			k.InjectConfigService(),
 			k.InjectDiscoveryService(),
+			k.Settings,
 		)
 	})
 
@@ -863,3 +993,425 @@ func (k *Kernel) InjectNSLookupService() *nslookup.Service {
 
 	return nsLookupService
 }
+
+var (
+	loggingService     *logging.Service
+	loggingServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectLoggingService() *logging.Service {
+	loggingServiceOnce.Do(func() {
+		loggingService = logging.NewService(
+			k.logWriter,
+			k.env.Agent.LogfilePath,
+		)
+	})
+
+	return loggingService
+}
+
+var (
+	metricsService     *metrics.Service
+	metricsServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectMetricsService() *metrics.Service {
+	metricsServiceOnce.Do(func() {
+		metricsService = metrics.NewService(
+			k.InjectAppStateService(),
+			k.InjectWebsocketService(),
+			k.InjectDiscoveryService(),
+			k.InjectConfigService(),
+			k.DB,
+			k.InjectStorageAdapter(),
+			k.InjectLTEMonitor(),
+			k.InjectPortStatsService(),
+		)
+	})
+
+	return metricsService
+}
+
+var (
+	localServer     *localserver.Service
+	localServerOnce sync.Once
+)
+
+func (k *Kernel) InjectLocalServer() *localserver.Service {
+	localServerOnce.Do(func() {
+		localServer = localserver.NewService(
+			constants.LocalServerAddr,
+		)
+	})
+
+	return localServer
+}
+
+var (
+	adminServer     *localserver.Service
+	adminServerOnce sync.Once
+)
+
+func (k *Kernel) InjectAdminServer() *localserver.Service {
+	adminServerOnce.Do(func() {
+		adminServer = localserver.NewUnixService(
+			constants.AdminSocketPath,
+		)
+	})
+
+	return adminServer
+}
+
+var (
+	reachabilityService     *reachability.Service
+	reachabilityServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectReachabilityService() *reachability.Service {
+	reachabilityServiceOnce.Do(func() {
+		reachabilityService = reachability.NewService(
+			k.InjectShellService(),
+			k.InjectConfigService(),
+		)
+	})
+
+	return reachabilityService
+}
+
+var (
+	transferService     *transfer.Service
+	transferServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectTransferService() *transfer.Service {
+	transferServiceOnce.Do(func() {
+		transferService = transfer.NewService(
+			k.InjectMessagePublisher(),
+			transfer.DefaultChunkSize,
+		)
+	})
+
+	return transferService
+}
+
+var (
+	captureService     *capture.Service
+	captureServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectCaptureService() *capture.Service {
+	captureServiceOnce.Do(func() {
+		captureService = capture.NewService(
+			k.InjectConfigService(),
+			k.InjectTransferService(),
+			k.InjectMessagePublisher(),
+			k.env.Agent.CaptureDir,
+		)
+	})
+
+	return captureService
+}
+
+var (
+	debugService     *debug.Service
+	debugServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectDebugService() *debug.Service {
+	debugServiceOnce.Do(func() {
+		debugService = debug.NewService(
+			k.DB,
+			debug.MQChunkSize,
+		)
+	})
+
+	return debugService
+}
+
+var (
+	healthService     *health.Service
+	healthServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectHealthService() *health.Service {
+	healthServiceOnce.Do(func() {
+		healthService = health.NewService(
+			k.InjectAppStateService(),
+			k.InjectConnectionService(),
+			k.InjectMQService(),
+			k.DB,
+			k.InjectStorageAdapter(),
+			k.InjectConfigService(),
+		)
+	})
+
+	return healthService
+}
+
+var (
+	eventService     *event.Service
+	eventServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectEventService() *event.Service {
+	eventServiceOnce.Do(func() {
+		eventService = event.NewService(
+			k.InjectMessagePublisher(),
+		)
+	})
+
+	return eventService
+}
+
+var (
+	dataUsageStore     *datausage.Store
+	dataUsageStoreOnce sync.Once
+)
+
+func (k *Kernel) InjectDataUsageStore() *datausage.Store {
+	dataUsageStoreOnce.Do(func() {
+		dataUsageStore = datausage.NewStore(
+			k.DB,
+		)
+	})
+
+	return dataUsageStore
+}
+
+var (
+	dataUsageService     *datausage.Service
+	dataUsageServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectDataUsageService() *datausage.Service {
+	dataUsageServiceOnce.Do(func() {
+		dataUsageService = datausage.NewService(
+			k.InjectConfigService(),
+			k.InjectAppStateService(),
+			k.InjectEventService(),
+			k.InjectLTEMonitor(),
+			k.InjectDataUsageStore(),
+			netstats.SysClassNetPath,
+			datausage.BootIDPath,
+			datausage.DefaultInterval,
+		)
+	})
+
+	return dataUsageService
+}
+
+var (
+	upgradeStore     *updatemanager.UpgradeStore
+	upgradeStoreOnce sync.Once
+)
+
+func (k *Kernel) InjectUpgradeStore() *updatemanager.UpgradeStore {
+	upgradeStoreOnce.Do(func() {
+		upgradeStore = updatemanager.NewUpgradeStore(
+			k.DB,
+		)
+	})
+
+	return upgradeStore
+}
+
+var (
+	packageVerifier     *updatemanager.Verifier
+	packageVerifierOnce sync.Once
+)
+
+func (k *Kernel) InjectPackageVerifier() *updatemanager.Verifier {
+	packageVerifierOnce.Do(func() {
+		packageVerifier = updatemanager.NewVerifier(
+			constants.AptArchivesDirectory,
+			constants.TrustedKeysDirectory,
+			k.env.Agent.RequirePackageDigest,
+		)
+	})
+
+	return packageVerifier
+}
+
+var (
+	updateManagerUpgrader     *updatemanager.SelfUpgrader
+	updateManagerUpgraderOnce sync.Once
+)
+
+func (k *Kernel) InjectUpdateManagerUpgrader() *updatemanager.SelfUpgrader {
+	updateManagerUpgraderOnce.Do(func() {
+		updateManagerUpgrader = updatemanager.NewSelfUpgrader(
+			constants.AptArchivesDirectory,
+			constants.UpdateManagerServiceName,
+			updatemanager.DefaultUnitStartTimeout,
+		)
+	})
+
+	return updateManagerUpgrader
+}
+
+var (
+	agentRollbackGuard     *updatemanager.RollbackGuard
+	agentRollbackGuardOnce sync.Once
+)
+
+func (k *Kernel) InjectAgentRollbackGuard() *updatemanager.RollbackGuard {
+	agentRollbackGuardOnce.Do(func() {
+		agentRollbackGuard = updatemanager.NewRollbackGuard(
+			k.InjectShellService(),
+			constants.AptArchivesDirectory,
+			updatemanager.AgentRollbackUnitName,
+		)
+	})
+
+	return agentRollbackGuard
+}
+
+var (
+	driftStore     *drift.Store
+	driftStoreOnce sync.Once
+)
+
+func (k *Kernel) InjectDriftStore() *drift.Store {
+	driftStoreOnce.Do(func() {
+		driftStore = drift.NewStore(
+			k.DB,
+		)
+	})
+
+	return driftStore
+}
+
+var (
+	driftService     *drift.Service
+	driftServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectDriftService() *drift.Service {
+	driftServiceOnce.Do(func() {
+		driftService = drift.NewService(
+			k.InjectConfigService(),
+			k.InjectCmdService(),
+			k.InjectOVSService(),
+			k.InjectAppStateService(),
+			k.InjectEventService(),
+			k.InjectDriftStore(),
+			drift.DefaultInterval,
+		)
+	})
+
+	return driftService
+}
+
+var (
+	offlineService     *offline.Service
+	offlineServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectOfflineService() *offline.Service {
+	offlineServiceOnce.Do(func() {
+		offlineService = offline.NewService(
+			k.InjectConfigService(),
+			k.InjectAppStateService(),
+			k.InjectDeviceInitService(),
+			k.InjectEventService(),
+			offline.NewStore(k.DB),
+			k.env.Agent.IncomingDirs,
+			k.env.Agent.OfflineKeysDir,
+			offline.DefaultInterval,
+		)
+	})
+
+	return offlineService
+}
+
+var (
+	adminService     *admin.Service
+	adminServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectAdminService() *admin.Service {
+	adminServiceOnce.Do(func() {
+		adminService = admin.NewService(
+			k.InjectHealthService(),
+			k.InjectAppStateService(),
+			k.InjectConfigService(),
+			k.InjectStorageAdapter(),
+			k.InjectActivityService(),
+			k.InjectLoggingService(),
+			k.InjectDebugService(),
+			k.InjectMessagePublisher(),
+			k.env.Agent.DeviceType,
+			k.env.Agent.LogfilePath,
+		)
+	})
+
+	return adminService
+}
+
+var (
+	requestTracker     *inflight.Tracker
+	requestTrackerOnce sync.Once
+)
+
+func (k *Kernel) InjectRequestTracker() *inflight.Tracker {
+	requestTrackerOnce.Do(func() {
+		requestTracker = inflight.NewTracker()
+	})
+
+	return requestTracker
+}
+
+var (
+	ponyProgressService     *agentPony.ProgressConfigService
+	ponyProgressServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectPonyProgressService() *agentPony.ProgressConfigService {
+	ponyProgressServiceOnce.Do(func() {
+		ponyProgressService = agentPony.NewProgressConfigService(
+			k.InjectConfigService(),
+			k.InjectAppStateService(),
+		)
+	})
+
+	return ponyProgressService
+}
+
+var (
+	sdNotifyService     *sdnotify.Service
+	sdNotifyServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectSDNotifyService() *sdnotify.Service {
+	sdNotifyServiceOnce.Do(func() {
+		sdNotifyService = sdnotify.NewService(
+			k.InjectAppStateService(),
+			k.InjectAppStateService(),
+			map[string]sdnotify.IProgressProbe{
+				"state loop":     k.InjectAppStateService(),
+				"websocket loop": k.InjectWebsocketService(),
+				"pony loop":      k.InjectPonyProgressService(),
+			},
+		)
+	})
+
+	return sdNotifyService
+}
+
+var (
+	portStatsService     *portstats.Service
+	portStatsServiceOnce sync.Once
+)
+
+func (k *Kernel) InjectPortStatsService() *portstats.Service {
+	portStatsServiceOnce.Do(func() {
+		portStatsService = portstats.NewService(
+			k.InjectConfigService(),
+			netstats.SysClassNetPath,
+			portstats.DefaultInterval,
+			portstats.DefaultWindow,
+		)
+	})
+
+	return portStatsService
+}
@@ -0,0 +1,257 @@
+// Package cli implements subcommands of the agent binary which talk to the running agent over admin socket.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const usage = `Usage: sdwan-agent [--json] [--socket path] <command>
+
+Without command the agent daemon is started.
+
+Commands:
+  status                                   agent state, orchestrator and tunnels
+  state history                            last state transitions
+  config show [section]                    config with masked secrets (section example: port)
+  tx list                                  not finished activity transactions
+  tx rollback                              roll back dangling transactions
+  diag bundle [-o file]                    save diagnostic bundle (tar.gz)
+  log-level set <level> [--component name] [--ttl duration]
+  reconnect                                reconnect orchestrator websocket
+`
+
+var ErrUsage = errors.New("invalid command")
+
+type runner struct {
+	client   *Client
+	stdout   io.Writer
+	jsonMode bool
+}
+
+// IsCommand reports whether arguments select CLI subcommand instead of the daemon.
+func IsCommand(args []string) bool {
+	return len(args) > 0
+}
+
+// Run executes subcommand, args do not include program name.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) (err error) {
+	flags := flag.NewFlagSet("sdwan-agent", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+
+	jsonMode := flags.Bool("json", false, "print JSON instead of tables")
+	socketPath := flags.String("socket", constants.AdminSocketPath, "admin socket of the running agent")
+	if err = flags.Parse(args); err != nil {
+		return fmt.Errorf("Run: %w", err)
+	}
+
+	r := runner{
+		client:   NewClient(*socketPath),
+		stdout:   stdout,
+		jsonMode: *jsonMode,
+	}
+
+	command := flags.Args()
+	if err = r.run(ctx, command); err != nil {
+		if errors.Is(err, ErrUsage) {
+			fmt.Fprint(stderr, usage)
+		}
+
+		return fmt.Errorf("Run: %w", err)
+	}
+
+	return nil
+}
+
+func (r runner) run(ctx context.Context, command []string) (err error) {
+	if len(command) == 0 {
+		return ErrUsage
+	}
+
+	switch name, args := command[0], command[1:]; {
+	case name == "status" && len(args) == 0:
+		return r.status(ctx)
+	case name == "state" && len(args) == 1 && args[0] == "history":
+		return r.stateHistory(ctx)
+	case name == "config" && len(args) >= 1 && len(args) <= 2 && args[0] == "show":
+		return r.configShow(ctx, args[1:])
+	case name == "tx" && len(args) == 1 && args[0] == "list":
+		return r.txList(ctx)
+	case name == "tx" && len(args) == 1 && args[0] == "rollback":
+		return r.txRollback(ctx)
+	case name == "diag" && len(args) >= 1 && args[0] == "bundle":
+		return r.diagBundle(ctx, args[1:])
+	case name == "log-level" && len(args) >= 1 && args[0] == "set":
+		return r.setLogLevel(ctx, args[1:])
+	case name == "reconnect" && len(args) == 0:
+		return r.reconnect(ctx)
+	case name == "help":
+		return ErrUsage
+	}
+
+	return fmt.Errorf("%w: %v", ErrUsage, command)
+}
+
+func (r runner) status(ctx context.Context) (err error) {
+	data, err := r.client.Get(ctx, constants.AdminStatusPath, nil)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	if r.jsonMode {
+		return r.printJSON(data)
+	}
+
+	var status entities.AgentStatus
+	if err = json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	fmt.Fprintln(r.stdout, renderStatus(status))
+	return nil
+}
+
+func (r runner) stateHistory(ctx context.Context) (err error) {
+	data, err := r.client.Get(ctx, constants.AdminStateHistoryPath, nil)
+	if err != nil {
+		return fmt.Errorf("stateHistory: %w", err)
+	}
+
+	if r.jsonMode {
+		return r.printJSON(data)
+	}
+
+	var history []entities.TransitionRecord
+	if err = json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("stateHistory: %w", err)
+	}
+
+	fmt.Fprintln(r.stdout, renderStateHistory(history))
+	return nil
+}
+
+// configShow always prints JSON, config sections are too nested for tables.
+func (r runner) configShow(ctx context.Context, args []string) (err error) {
+	var query url.Values
+	if len(args) == 1 {
+		query = url.Values{"section": {args[0]}}
+	}
+
+	data, err := r.client.Get(ctx, constants.AdminConfigPath, query)
+	if err != nil {
+		return fmt.Errorf("configShow: %w", err)
+	}
+
+	return r.printJSON(data)
+}
+
+func (r runner) txList(ctx context.Context) (err error) {
+	data, err := r.client.Get(ctx, constants.AdminTxPath, nil)
+	if err != nil {
+		return fmt.Errorf("txList: %w", err)
+	}
+
+	if r.jsonMode {
+		return r.printJSON(data)
+	}
+
+	var transactions []entities.TransactionInfo
+	if err = json.Unmarshal(data, &transactions); err != nil {
+		return fmt.Errorf("txList: %w", err)
+	}
+
+	fmt.Fprintln(r.stdout, renderTransactions(transactions))
+	return nil
+}
+
+func (r runner) txRollback(ctx context.Context) (err error) {
+	if err = r.client.Post(ctx, constants.AdminTxRollbackPath, nil); err != nil {
+		return fmt.Errorf("txRollback: %w", err)
+	}
+
+	return r.printDone("dangling transactions rolled back")
+}
+
+func (r runner) diagBundle(ctx context.Context, args []string) (err error) {
+	flags := flag.NewFlagSet("diag bundle", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	output := flags.String("o", fmt.Sprintf("sdwan-diag-%s.tar.gz", time.Now().Format("20060102-150405")), "output file")
+	if err = flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return fmt.Errorf("diagBundle: %w", ErrUsage)
+	}
+
+	file, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("diagBundle: %w", err)
+	}
+	defer file.Close()
+
+	if err = r.client.Download(ctx, constants.AdminDiagBundlePath, file); err != nil {
+		return fmt.Errorf("diagBundle: %w", err)
+	}
+
+	return r.printDone(fmt.Sprintf("diagnostic bundle saved to %s", *output))
+}
+
+func (r runner) setLogLevel(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("setLogLevel: %w", ErrUsage)
+	}
+
+	flags := flag.NewFlagSet("log-level set", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	component := flags.String("component", "", "component (package) name, global level if empty")
+	ttl := flags.Duration("ttl", 0, "level is reverted after ttl, never if zero")
+	if err = flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return fmt.Errorf("setLogLevel: %w", ErrUsage)
+	}
+
+	if err = r.client.Post(ctx, constants.AdminLogLevelPath, entities.SetLogLevelRequest{
+		Level:      args[0],
+		Component:  *component,
+		TTLSeconds: int(ttl.Seconds()),
+	}); err != nil {
+		return fmt.Errorf("setLogLevel: %w", err)
+	}
+
+	return r.printDone("log level changed")
+}
+
+func (r runner) reconnect(ctx context.Context) (err error) {
+	if err = r.client.Post(ctx, constants.AdminReconnectPath, nil); err != nil {
+		return fmt.Errorf("reconnect: %w", err)
+	}
+
+	return r.printDone("reconnect requested")
+}
+
+func (r runner) printJSON(data []byte) (err error) {
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("printJSON: %w", err)
+	}
+
+	fmt.Fprintln(r.stdout, buf.String())
+	return nil
+}
+
+func (r runner) printDone(message string) (err error) {
+	if r.jsonMode {
+		return r.printJSON([]byte(`{"ok":true}`))
+	}
+
+	fmt.Fprintln(r.stdout, message)
+	return nil
+}
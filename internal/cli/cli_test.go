@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func TestRun(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	var logLevelRequest entities.SetLogLevelRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+constants.AdminStatusPath, func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(entities.AgentStatus{
+			AgentHealth:  entities.AgentHealth{AppState: entities.AppStateActive, WebsocketConnected: true},
+			SerialNumber: "CPE-0001",
+		})
+	})
+	mux.HandleFunc("GET "+constants.AdminConfigPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(entities.AdminError{Error: "config section not found: " + r.URL.Query().Get("section")})
+	})
+	mux.HandleFunc("POST "+constants.AdminLogLevelPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&logLevelRequest)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := &http.Server{Handler: mux} //nolint:gosec // test server
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		err := Run(context.Background(), append([]string{"--socket", socketPath}, args...), &stdout, io.Discard)
+		return stdout.String(), err
+	}
+
+	output, err := run("status")
+	require.NoError(t, err)
+	require.Contains(t, output, "CPE-0001")
+	require.Contains(t, output, "connected")
+
+	output, err = run("--json", "status")
+	require.NoError(t, err)
+	require.Contains(t, output, `"serialNumber": "CPE-0001"`)
+
+	_, err = run("config", "show", "ports")
+	require.ErrorContains(t, err, "config section not found: ports")
+
+	_, err = run("log-level", "set", "debug", "--component", "pony", "--ttl", "10m")
+	require.NoError(t, err)
+	require.Equal(t, entities.SetLogLevelRequest{Level: "debug", Component: "pony", TTLSeconds: 600}, logLevelRequest)
+
+	_, err = run("tx", "drop")
+	require.ErrorIs(t, err, ErrUsage)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	baseURL        = "http://sdwan-agent" // host is ignored by unix socket transport
+	requestTimeout = 30 * time.Second
+	bundleTimeout  = 2 * time.Minute
+)
+
+// Client sends requests to admin socket of the running agent.
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(socketPath string) *Client {
+	var dialer net.Dialer
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Get returns response body of the admin endpoint.
+func (c *Client) Get(ctx context.Context, path string, query url.Values) (data []byte, err error) {
+	var buf bytes.Buffer
+	if err = c.do(ctx, http.MethodGet, path, query, nil, &buf, requestTimeout); err != nil {
+		return nil, fmt.Errorf("Get: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Post sends command to the admin endpoint.
+func (c *Client) Post(ctx context.Context, path string, body any) (err error) {
+	if err = c.do(ctx, http.MethodPost, path, nil, body, io.Discard, requestTimeout); err != nil {
+		return fmt.Errorf("Post: %w", err)
+	}
+
+	return nil
+}
+
+// Download writes response body of the admin endpoint to w.
+func (c *Client) Download(ctx context.Context, path string, w io.Writer) (err error) {
+	if err = c.do(ctx, http.MethodGet, path, nil, nil, w, bundleTimeout); err != nil {
+		return fmt.Errorf("Download: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, w io.Writer, timeout time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var reqBody io.Reader
+	if body != nil {
+		data, mErr := json.Marshal(body)
+		if mErr != nil {
+			return fmt.Errorf("do: %w", mErr)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	reqURL := baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("do: agent is not running or admin socket is not available: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		var adminErr entities.AdminError
+		if dErr := json.NewDecoder(response.Body).Decode(&adminErr); dErr != nil || adminErr.Error == "" {
+			return fmt.Errorf("do: agent responded with status %d", response.StatusCode)
+		}
+
+		return fmt.Errorf("do: %s", adminErr.Error)
+	}
+
+	if _, err = io.Copy(w, response.Body); err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func renderStatus(status entities.AgentStatus) string {
+	t := table.NewWriter()
+	t.AppendRows([]table.Row{
+		{"STATE", status.AppState},
+		{"STATE SINCE", formatTime(status.StateSince)},
+		{"DEVICE", status.DeviceType},
+		{"SERIAL", status.SerialNumber},
+		{"ORCHESTRATOR", status.Orchestrator},
+		{"WEBSOCKET", connected(status.WebsocketConnected)},
+		{"NATS", connected(status.NATSConnected)},
+		{"DANGLING TX", status.DanglingTransactions},
+		{"HEALTHY", status.Healthy},
+	})
+	if len(status.Problems) > 0 {
+		t.AppendRow(table.Row{"PROBLEMS", strings.Join(status.Problems, "\n")})
+	}
+	if status.LastApply.Error != "" {
+		t.AppendRow(table.Row{"LAST APPLY ERROR", status.LastApply.ErrorStep + ": " + status.LastApply.Error})
+	}
+
+	if len(status.Tunnels) == 0 {
+		return t.Render()
+	}
+
+	tunnels := table.NewWriter()
+	tunnels.AppendHeader(table.Row{"CLUSTER", "TUNNEL", "UP", "REMOTE UP", "ACTIVE"})
+	for _, tunnel := range status.Tunnels {
+		tunnels.AppendRow(table.Row{tunnel.Network, tunnel.Tunnel, tunnel.Up, tunnel.RemoteUp, tunnel.Active})
+	}
+
+	return t.Render() + "\n" + tunnels.Render()
+}
+
+func renderStateHistory(history []entities.TransitionRecord) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"STARTED", "FROM", "TO", "DURATION", "ERROR"})
+	for _, record := range history {
+		t.AppendRow(table.Row{
+			formatTime(record.StartedAt),
+			record.From,
+			record.To,
+			record.Duration.Round(time.Millisecond),
+			record.Error,
+		})
+	}
+
+	return t.Render()
+}
+
+func renderTransactions(transactions []entities.TransactionInfo) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"UUID", "NAME", "STATUS", "ACTIVITIES", "ERROR"})
+	for _, tx := range transactions {
+		t.AppendRow(table.Row{tx.UUID, tx.Name, tx.Status, tx.Activities, tx.Error})
+	}
+
+	return t.Render()
+}
+
+func connected(isConnected bool) string {
+	if isConnected {
+		return "connected"
+	}
+
+	return "disconnected"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,188 @@
+// Package configcheck validates references between config sections. Struct tags validate every section
+// alone, so an update may refer to a port removed from the port section or reuse a routing table of another port.
+package configcheck
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	portTypeWAN = "wan"
+
+	ofPortTypeSystem = "system"
+	l3LinkModeDel    = "del"
+)
+
+// Validate checks the current config merged with the update. Violations already present in the current config
+// are logged only, so a config saved by older agent does not block unrelated updates.
+func Validate(current, update config.Config) (err error) {
+	existing := lo.SliceToMap(Check(current), func(violation entities.ConfigViolation) (entities.ConfigViolation, struct{}) {
+		return violation, struct{}{}
+	})
+
+	var violations entities.ConfigViolations
+	for _, violation := range Check(Merge(current, update)) {
+		if _, found := existing[violation]; found {
+			log.Warn().
+				Str("field", violation.Field).
+				Str("violation", violation.Message).
+				Msg("Validate: violation found in current config")
+
+			continue
+		}
+
+		violations = append(violations, violation)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("Validate: %w: %w", errs.ErrConfigValidation, violations)
+	}
+
+	return nil
+}
+
+// Merge replaces sections of the config with non-empty sections of the update.
+func Merge(cfg, update config.Config) (merged config.Config) {
+	merged = cfg
+	var (
+		mergedValue = reflect.ValueOf(&merged).Elem()
+		updateValue = reflect.ValueOf(update)
+	)
+	for i := range updateValue.NumField() {
+		field := updateValue.Field(i)
+		if field.Kind() == reflect.Pointer && !field.IsNil() {
+			mergedValue.Field(i).Set(field)
+		}
+	}
+
+	return merged
+}
+
+// Check returns all violations of the config.
+func Check(cfg config.Config) (violations entities.ConfigViolations) {
+	var (
+		ports    = make(map[string]config.PortConfig)
+		wanPorts = make(map[string]bool)
+		wanTable = make(map[int]string) // table id -> wan port name
+	)
+	add := func(field, format string, args ...any) {
+		violations = append(violations, entities.ConfigViolation{
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	checkPort := func(field, portName string) {
+		if _, found := ports[portName]; !found {
+			add(field, "port %s not found in port section", portName)
+		}
+	}
+
+	if cfg.Port != nil {
+		for i, port := range cfg.Port.PortConfigs {
+			field := fmt.Sprintf("port.portConfigs[%d]", i)
+			if _, found := ports[port.Name]; found {
+				add(field+".name", "port %s is duplicated", port.Name)
+				continue
+			}
+
+			ports[port.Name] = port
+			if port.Type != portTypeWAN {
+				continue
+			}
+
+			wanPorts[port.Name] = true
+			for j, tableID := range port.TableIDs {
+				if owner, found := wanTable[tableID]; found {
+					add(fmt.Sprintf("%s.tableIds[%d]", field, j), "table %d is already used by wan port %s", tableID, owner)
+					continue
+				}
+
+				wanTable[tableID] = port.Name
+			}
+		}
+
+		for i, port := range cfg.Port.PortConfigs {
+			if port.IsTag && port.Tag != nil {
+				checkPort(fmt.Sprintf("port.portConfigs[%d].tag.parentPort", i), port.Tag.ParentPort)
+			}
+		}
+	}
+
+	if cfg.WANProtection != nil {
+		checkWANPort := func(field, portName string) {
+			if !wanPorts[portName] {
+				add(field, "port %s is not a wan port", portName)
+			}
+		}
+		for i, portName := range cfg.WANProtection.PortNames {
+			checkWANPort(fmt.Sprintf("wanProtection.portNames[%d]", i), portName)
+		}
+
+		for i, allowed := range cfg.WANProtection.AllowedPorts {
+			if allowed.PortName != "" {
+				checkWANPort(fmt.Sprintf("wanProtection.allowedPorts[%d].portName", i), allowed.PortName)
+			}
+		}
+	}
+
+	if cfg.Pony != nil {
+		for i, cluster := range cfg.Pony.Clusters {
+			for j, uplink := range cluster.Uplinks {
+				if _, found := wanTable[uplink.TableID]; !found {
+					add(fmt.Sprintf("pony.clusters[%d].uplinks[%d].tableId", i, j),
+						"table %d does not belong to any wan port", uplink.TableID)
+				}
+			}
+		}
+	}
+
+	if cfg.AdminState != nil {
+		for i, statePort := range cfg.AdminState.AdminStatePorts {
+			checkPort(fmt.Sprintf("adminState.adminStatePorts[%d].portName", i), statePort.PortName)
+		}
+	}
+
+	if cfg.Trunk != nil {
+		for i, trunk := range cfg.Trunk.Trunks {
+			checkPort(fmt.Sprintf("trunk.trunks[%d].portName", i), trunk.PortName)
+		}
+	}
+
+	checkOFPorts := func(field string, ofPorts config.OFPorts) {
+		for i, ofPort := range ofPorts {
+			// patch and tunnel ports are created by the service itself
+			if ofPort.PortType == ofPortTypeSystem {
+				checkPort(fmt.Sprintf("%s.ports[%d].portName", field, i), ofPort.PortName)
+			}
+		}
+	}
+	if cfg.P2P != nil {
+		for i, service := range cfg.P2P.P2PServices {
+			checkOFPorts(fmt.Sprintf("p2p.p2pServices[%d]", i), service.Ports)
+		}
+	}
+
+	if cfg.Bridge != nil {
+		for i, service := range cfg.Bridge.BridgeServices {
+			checkOFPorts(fmt.Sprintf("bridge.bridgeServices[%d]", i), service.Ports)
+		}
+	}
+
+	if cfg.L3 != nil {
+		for i, link := range cfg.L3.L3Links {
+			if link.Port != nil && link.Mode != l3LinkModeDel {
+				checkPort(fmt.Sprintf("l3.l3Links[%d].port.portName", i), link.Port.PortName)
+			}
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,106 @@
+package configcheck
+
+import (
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+func testPorts() *config.PortSection {
+	return &config.PortSection{
+		PortConfigs: config.PortConfigs{
+			{Name: "eth0", Type: "wan", TableIDs: []int{100, 101}},
+			{Name: "eth1", Type: "wan", TableIDs: []int{110}},
+			{Name: "eth2", Type: "lan", TableIDs: []int{100}},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		expected entities.ConfigViolations
+	}{
+		{
+			name: "valid",
+			cfg: config.Config{
+				Port:          testPorts(),
+				WANProtection: &config.WANProtectionSection{PortNames: []string{"eth0", "eth1"}},
+				Pony: &config.PonySection{Clusters: config.PonyClusters{
+					{Uplinks: []config.UplinkConfig{{TableID: 101}, {TableID: 110}}},
+				}},
+				Trunk: &config.TrunkSection{Trunks: config.Trunks{{PortName: "eth2"}}},
+				P2P: &config.P2PServiceSection{P2PServices: config.P2PServices{
+					{Ports: config.OFPorts{{PortName: "eth2", PortType: "system"}, {PortName: "vx1", PortType: "vxlan"}}},
+				}},
+			},
+		},
+		{
+			name: "table reused by wan ports",
+			cfg: config.Config{
+				Port: &config.PortSection{PortConfigs: config.PortConfigs{
+					{Name: "eth0", Type: "wan", TableIDs: []int{100}},
+					{Name: "eth1", Type: "wan", TableIDs: []int{110, 100}},
+				}},
+			},
+			expected: entities.ConfigViolations{
+				{Field: "port.portConfigs[1].tableIds[1]", Message: "table 100 is already used by wan port eth0"},
+			},
+		},
+		{
+			name: "references to unknown ports",
+			cfg: config.Config{
+				Port:          testPorts(),
+				WANProtection: &config.WANProtectionSection{PortNames: []string{"eth2"}},
+				Pony: &config.PonySection{Clusters: config.PonyClusters{
+					{Uplinks: []config.UplinkConfig{{TableID: 101}, {TableID: 120}}},
+				}},
+				Trunk: &config.TrunkSection{Trunks: config.Trunks{{PortName: "eth5"}}},
+				L3: &config.L3ServiceSection{L3Links: config.L3Links{
+					{Mode: "add", Port: &config.L3LinkPort{PortName: "eth6"}},
+					{Mode: "del", Port: &config.L3LinkPort{PortName: "eth7"}},
+				}},
+			},
+			expected: entities.ConfigViolations{
+				{Field: "wanProtection.portNames[0]", Message: "port eth2 is not a wan port"},
+				{Field: "pony.clusters[0].uplinks[1].tableId", Message: "table 120 does not belong to any wan port"},
+				{Field: "trunk.trunks[0].portName", Message: "port eth5 not found in port section"},
+				{Field: "l3.l3Links[0].port.portName", Message: "port eth6 not found in port section"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Check(tt.cfg))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	current := config.Config{
+		Port:  testPorts(),
+		Trunk: &config.TrunkSection{Trunks: config.Trunks{{PortName: "eth9"}}}, // saved before validation existed
+	}
+
+	// unrelated update is not blocked by violation of the current config
+	require.NoError(t, Validate(current, config.Config{FW: &config.FWSection{}}))
+
+	// removing port still used by pony uplink is rejected
+	current.Pony = &config.PonySection{Clusters: config.PonyClusters{
+		{Uplinks: []config.UplinkConfig{{TableID: 110}}},
+	}}
+	err := Validate(current, config.Config{Port: &config.PortSection{PortConfigs: testPorts().PortConfigs[:1]}})
+	require.ErrorIs(t, err, errs.ErrConfigValidation)
+
+	var violations entities.ConfigViolations
+	require.ErrorAs(t, err, &violations)
+	require.Equal(t, entities.ConfigViolations{
+		{Field: "pony.clusters[0].uplinks[0].tableId", Message: "table 110 does not belong to any wan port"},
+	}, violations)
+}
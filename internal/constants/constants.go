@@ -5,6 +5,24 @@ const (
 	GrafanaLokiPort  = 1081
 )
 
+const (
+	LocalServerAddr = "127.0.0.1:9465"
+	MetricsPath     = "/metrics"
+	HealthPath      = "/health"
+)
+
+const (
+	AdminSocketPath       = "/run/sdwan-agent/admin.sock"
+	AdminStatusPath       = "/status"
+	AdminStateHistoryPath = "/state/history"
+	AdminConfigPath       = "/config"
+	AdminTxPath           = "/tx"
+	AdminTxRollbackPath   = "/tx/rollback"
+	AdminDiagBundlePath   = "/diag/bundle"
+	AdminLogLevelPath     = "/log-level"
+	AdminReconnectPath    = "/reconnect"
+)
+
 const (
 	LinuxOSName      = "linux"
 	OrchestratorWSID = "main_orchestrator"
@@ -1,15 +1,19 @@
 package constants
 
 const (
-	NetInitPath           = "/opt/picot/net/net_init.sh"
-	GrafanaConfigPath     = "/etc/systemd/system/grafana-agent-flow.service.d/override.conf"
-	DefaultLogfilePath    = "/var/log/sdwan/sdwan_agent.log"
-	NetworkInterfacesPath = "/etc/network/interfaces.d"
-	AgentEnvPath          = "/etc/sdwan/agent.env"
+	NetInitPath             = "/opt/picot/net/net_init.sh"
+	GrafanaConfigPath       = "/etc/systemd/system/grafana-agent-flow.service.d/override.conf"
+	GrafanaAgentConfigPath  = "/etc/grafana-agent-flow.river"               // main config, scrape module is imported into it
+	GrafanaScrapeConfigPath = "/etc/grafana-agent-flow.d/sdwan-agent.river" // agent metrics scrape module
+	DefaultLogfilePath      = "/var/log/sdwan/sdwan_agent.log"
+	NetworkInterfacesPath   = "/etc/network/interfaces.d"
+	AgentEnvPath            = "/etc/sdwan/agent.env"
+	AgentSettingsPath       = "/etc/sdwan/agent.yaml"  // optional, overridden by AGENT_CONFIG_FILE
+	LTEPINKeyPath           = "/etc/sdwan/lte-pin.key" // default encryption key of saved sim PINs and APN passwords
 )
 
 const (
-	AgentConfigPath  = "/etc/sdwan/agent-config"
+	AgentConfigPath  = "/etc/sdwan/agent-config" // default badger directory
 	SDWANProjectPath = "/etc/sdwan"
 )
 
@@ -17,6 +21,23 @@ const (
 	DHCPDirectory = "/etc/dhcp"
 )
 
+const (
+	PacketCaptureDirectory = "/var/tmp/sdwan-capture"
+)
+
 const (
 	EtcHostsPath = "/etc/hosts"
 )
+
+const (
+	AptArchivesDirectory       = "/var/cache/apt/archives"
+	TrustedKeysDirectory       = "/etc/sdwan/trusted-keys"
+	TrustedPublicKeyFileSuffix = ".pub"
+)
+
+const (
+	IncomingDirectory          = "/etc/sdwan/incoming"     // offline bundles, additional dirs (e.g. usb mount) are set by env
+	OfflineBundleKeysDirectory = "/etc/sdwan/offline-keys" // keys trusted to sign offline bundles, not packages
+	OfflineBundleFileSuffix    = ".json"
+	OfflineBundleResultSuffix  = ".result.json"
+)
@@ -14,9 +14,21 @@ const (
 	MQAgentHubListPorts    = "agent.hub.list_ports"
 	MQAgentHubInit         = "agent.hub.init"
 	MQAgentDebugDumpHeap   = "agent.debug.dump_heap"
+	MQAgentDebugProfile    = "agent.debug.profile"
+	MQAgentDebugChunk      = "agent.debug.profile_chunk"
+	MQAgentDebugStatus     = "agent.debug.profile_status"
+	MQAgentDebugStats      = "agent.debug.runtime_stats"
+	MQAgentLogSetLevel     = "agent.log.set_level"
+	MQAgentLogResetLevel   = "agent.log.reset_level"
+	MQAgentLogGetLevels    = "agent.log.get_levels"
+	MQAgentLogTail         = "agent.log.tail"
+	MQAgentHealthPing      = "agent.health.ping"
+	MQAgentUpdateProgress  = "agent.download.progress"
 
 	// out requests.
-	MQUpdateManagerDownload    = "update_manager.download"
-	MQUpdateManagerInstall     = "update_manager.install"
-	MQUpdateManagerGetVersions = "update_manager.get_versions"
+	MQUpdateManagerDownload       = "update_manager.download"
+	MQUpdateManagerInstall        = "update_manager.install"
+	MQUpdateManagerGetVersions    = "update_manager.get_versions"
+	MQUpdateManagerDownloadCancel = "update_manager.download_cancel"
+	MQUpdateManagerDownloadResume = "update_manager.download_resume"
 )
@@ -8,5 +8,7 @@ const (
 const (
 	AgentStarterServiceName  = "sdwan-agent-starter"
 	UpdateManagerServiceName = "sdwan-update-manager"
+	BGPDServiceName          = "sdwan-bgpd"
+	BGPAdapterServiceName    = "sdwan-bgp-adapter"
 	ISCDHCPServiceName       = "isc-dhcp-server"
 )
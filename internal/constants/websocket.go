@@ -12,6 +12,7 @@ const (
 	MethodFetchTunnelStates      = "fetch_tunnel_states"
 	MethodUpdateAllConfigs       = "update_all_configs"
 	MethodUpdateWgPeer           = "update_wg_peer"
+	MethodPatchWgPeers           = "patch_wg_peers"
 	MethodInitDevice             = "init_device"
 	MethodListFlowRoutes         = "list_flow_routes"
 	MethodL3UpdateConfig         = "l3_update_config"
@@ -31,14 +32,47 @@ const (
 	MethodDownloadDevicePackages = "download_device_packages"
 	MethodInstallDevicePackages  = "install_device_packages"
 	MethodGetPackagesVersions    = "get_packages_versions"
+	MethodCancelDownloadPackages = "cancel_download_packages"
+	MethodResumeDownloadPackages = "resume_download_packages"
+	MethodGetDownloadJobs        = "get_download_jobs"
 	MethodLTEFetchStats          = "lte_fetch_stats"
 	MethodLTEResetModem          = "lte_reset_modem"
+	MethodLTEFetchHistory        = "lte_fetch_history"
+	MethodLTEGetSIMSettings      = "lte_get_sim_settings"
+	MethodLTEUnlockSIM           = "lte_unlock_sim"
+	MethodLTESelectSIMSlot       = "lte_select_sim_slot"
+	MethodLTESetAPNProfiles      = "lte_set_apn_profiles"
+	MethodLTESetSIMFailover      = "lte_set_sim_failover"
+	MethodGetDataUsage           = "get_data_usage"
+	MethodSetDataUsagePolicies   = "set_data_usage_policies"
+	MethodGetConfigDrift         = "get_config_drift"
+	MethodGetDriftSettings       = "get_drift_settings"
+	MethodSetDriftSettings       = "set_drift_settings"
+	MethodSetLogLevel            = "set_log_level"
+	MethodResetLogLevel          = "reset_log_level"
+	MethodGetLogLevels           = "get_log_levels"
+	MethodTailLog                = "tail_log"
+	MethodPing                   = "ping"
+	MethodTraceroute             = "traceroute"
+	MethodDiscoverPathMTU        = "discover_path_mtu"
+	MethodStartPacketCapture     = "start_packet_capture"
+	MethodStopPacketCapture      = "stop_packet_capture"
+	MethodDebugProfile           = "debug_profile"
+	MethodDebugRuntimeStats      = "debug_runtime_stats"
+	MethodExportConfig           = "export_config"
+	MethodImportConfig           = "import_config"
 
 	// out requests.
 	MethodUplinkStateChanged            = "uplink_state_changed"
 	MethodInitDeviceFinished            = "init_device_finished"
 	MethodUpdateAllConfigsFinished      = "update_all_configs_finished"
+	MethodImportConfigFinished          = "import_config_finished"
 	MethodInstallDevicePackagesFinished = "install_device_packages_finished"
+	MethodAgentEvent                    = "agent_event"
+	MethodDownloadPackagesProgress      = "download_packages_progress"
+	MethodTransferChunk                 = "transfer_chunk"
+	MethodPacketCaptureFinished         = "packet_capture_finished"
+	MethodTailLogChunk                  = "tail_log_chunk"
 )
 
 const (
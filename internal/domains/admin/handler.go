@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/validator"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+type (
+	IAdminService interface {
+		Status(ctx context.Context) (status entities.AgentStatus, err error)
+		StateHistory() []entities.TransitionRecord
+		Config(section string) (result any, err error)
+		Transactions(ctx context.Context) (result []entities.TransactionInfo, err error)
+		RollbackTransactions(ctx context.Context) (err error)
+		SetLogLevel(request entities.SetLogLevelRequest) (err error)
+		Reconnect()
+		WriteDiagBundle(ctx context.Context, w io.Writer) (err error)
+	}
+
+	// Handler serves admin socket requests of the local CLI.
+	Handler struct {
+		adminService IAdminService
+	}
+)
+
+func NewHandler(adminService IAdminService) *Handler {
+	return &Handler{
+		adminService: adminService,
+	}
+}
+
+// ServeStatus serves agent status.
+func (h *Handler) ServeStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.adminService.Status(r.Context())
+	if err != nil {
+		writeError(w, fmt.Errorf("ServeStatus: %w", err))
+		return
+	}
+
+	writeJSON(w, status)
+}
+
+// ServeStateHistory serves last state transitions.
+func (h *Handler) ServeStateHistory(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.adminService.StateHistory())
+}
+
+// ServeConfig serves config with masked secrets, section is selected by query parameter.
+func (h *Handler) ServeConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.adminService.Config(r.URL.Query().Get("section"))
+	if err != nil {
+		writeError(w, fmt.Errorf("ServeConfig: %w", err))
+		return
+	}
+
+	writeJSON(w, cfg)
+}
+
+// ServeTransactions serves not finished activity transactions.
+func (h *Handler) ServeTransactions(w http.ResponseWriter, r *http.Request) {
+	transactions, err := h.adminService.Transactions(r.Context())
+	if err != nil {
+		writeError(w, fmt.Errorf("ServeTransactions: %w", err))
+		return
+	}
+
+	writeJSON(w, transactions)
+}
+
+// ServeRollbackTransactions rolls back dangling transactions.
+func (h *Handler) ServeRollbackTransactions(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.RollbackTransactions(r.Context()); err != nil {
+		writeError(w, fmt.Errorf("ServeRollbackTransactions: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeDiagBundle serves tar.gz diagnostic bundle.
+func (h *Handler) ServeDiagBundle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=sdwan-diag-%s.tar.gz", time.Now().Format("20060102-150405")))
+	if err := h.adminService.WriteDiagBundle(r.Context(), w); err != nil {
+		// headers may be already sent, client detects broken archive
+		log.Error().Err(err).Msg("ServeDiagBundle")
+	}
+}
+
+// ServeSetLogLevel changes log level globally or for the component.
+func (h *Handler) ServeSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var request entities.SetLogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, errors.Join(errs.ErrBadRequest, fmt.Errorf("ServeSetLogLevel: %w", err)))
+		return
+	}
+
+	if err := validator.Validator.Struct(request); err != nil {
+		writeError(w, errors.Join(errs.ErrBadRequest, fmt.Errorf("ServeSetLogLevel: %w", err)))
+		return
+	}
+
+	if err := h.adminService.SetLogLevel(request); err != nil {
+		writeError(w, fmt.Errorf("ServeSetLogLevel: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeReconnect reconnects orchestrator websocket.
+func (h *Handler) ServeReconnect(w http.ResponseWriter, _ *http.Request) {
+	h.adminService.Reconnect()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error().Err(err).Msg("writeJSON")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errs.ErrBadRequest), errors.Is(err, errs.ErrConfigSectionNotFound):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, errs.ErrTransitionInProgress):
+		statusCode = http.StatusConflict
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if eErr := json.NewEncoder(w).Encode(entities.AdminError{Error: err.Error()}); eErr != nil {
+		log.Error().Err(eErr).Msg("writeError")
+	}
+}
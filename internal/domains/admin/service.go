@@ -0,0 +1,292 @@
+package admin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/activity"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
+)
+
+const (
+	configKeyTag       = "cfg-key"
+	bundleLogTailBytes = 4 << 20 // last 4MB of the agent log
+	bundleFilePerm     = 0o600
+)
+
+type (
+	IHealthService interface {
+		Check(ctx context.Context) (health entities.AgentHealth)
+	}
+
+	IAppStateService interface {
+		Stats() entities.AppStateStats
+		History() (history []entities.TransitionRecord)
+		GetStatus() (status entities.HealthcheckStatus)
+		TryExclusive(fn func(ctx context.Context) error) (err error)
+	}
+
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	IStorageAdapter interface {
+		GetNotFinishedTransactions(ctx context.Context) (result activity.Transactions, err error)
+	}
+
+	IActivityService interface {
+		TryRollbackDanglingTransactions(ctx context.Context, maxGoroutines uint) (err error)
+	}
+
+	ILoggingService interface {
+		SetLevel(component, level string, ttl time.Duration) (err error)
+		GetLevels() entities.LogLevels
+	}
+
+	IDebugService interface {
+		GetRuntimeStats() (stats entities.RuntimeStats)
+	}
+
+	IMessagePublisher interface {
+		Reconnect()
+	}
+)
+
+// Service serves admin commands of the local CLI.
+type Service struct {
+	healthService    IHealthService
+	appStateService  IAppStateService
+	configService    IConfigService
+	storageAdapter   IStorageAdapter
+	activityService  IActivityService
+	loggingService   ILoggingService
+	debugService     IDebugService
+	messagePublisher IMessagePublisher
+	deviceType       string
+	logFilePath      string
+}
+
+func NewService(healthService IHealthService, appStateService IAppStateService, configService IConfigService,
+	storageAdapter IStorageAdapter, activityService IActivityService, loggingService ILoggingService,
+	debugService IDebugService, messagePublisher IMessagePublisher, deviceType, logFilePath string) *Service {
+	return &Service{
+		healthService:    healthService,
+		appStateService:  appStateService,
+		configService:    configService,
+		storageAdapter:   storageAdapter,
+		activityService:  activityService,
+		loggingService:   loggingService,
+		debugService:     debugService,
+		messagePublisher: messagePublisher,
+		deviceType:       deviceType,
+		logFilePath:      logFilePath,
+	}
+}
+
+// Status returns agent state, orchestrator connection and tunnel states.
+func (s *Service) Status(ctx context.Context) (status entities.AgentStatus, err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return status, fmt.Errorf("Status: %w", err)
+	}
+
+	status = entities.AgentStatus{
+		AgentHealth: s.healthService.Check(ctx),
+		StateSince:  s.appStateService.Stats().StateSince,
+		DeviceType:  s.deviceType,
+	}
+	if cfg.App != nil {
+		status.SerialNumber = cfg.App.SerialNumber
+		status.Orchestrator = cfg.App.ActiveOrchestratorAddr
+	}
+
+	return status, nil
+}
+
+// StateHistory returns last state transitions, the newest first.
+func (s *Service) StateHistory() []entities.TransitionRecord {
+	return s.appStateService.History()
+}
+
+// Config returns config with masked secrets, whole or the section with specified key (example: port).
+func (s *Service) Config(section string) (result any, err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Config: %w", err)
+	}
+
+	if section == "" {
+		return redact.Value(cfg), nil
+	}
+
+	cfgValue := reflect.ValueOf(cfg)
+	for i := range cfgValue.NumField() {
+		if cfgValue.Type().Field(i).Tag.Get(configKeyTag) == section {
+			return redact.Value(cfgValue.Field(i).Interface()), nil
+		}
+	}
+
+	return nil, fmt.Errorf("Config: %w: %s", errs.ErrConfigSectionNotFound, section)
+}
+
+// Transactions returns not finished activity transactions.
+func (s *Service) Transactions(ctx context.Context) (result []entities.TransactionInfo, err error) {
+	transactions, err := s.storageAdapter.GetNotFinishedTransactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Transactions: %w", err)
+	}
+
+	result = make([]entities.TransactionInfo, 0, len(transactions))
+	for _, tx := range transactions {
+		result = append(result, entities.TransactionInfo{
+			UUID:       tx.UUID,
+			Name:       tx.Name,
+			Status:     tx.Status,
+			Activities: len(tx.Activities),
+			Error:      tx.ErrorMessage,
+		})
+	}
+
+	return result, nil
+}
+
+// RollbackTransactions rolls back dangling transactions. Transaction of running transition is not finished too,
+// so rollback is run in transition loop and refused while transition is in progress.
+func (s *Service) RollbackTransactions(_ context.Context) (err error) {
+	if err = s.appStateService.TryExclusive(func(ctx context.Context) error {
+		return s.activityService.TryRollbackDanglingTransactions(ctx, 1)
+	}); err != nil {
+		return fmt.Errorf("RollbackTransactions: %w", err)
+	}
+
+	return nil
+}
+
+// SetLogLevel changes log level globally or for the component.
+func (s *Service) SetLogLevel(request entities.SetLogLevelRequest) (err error) {
+	if err = s.loggingService.SetLevel(request.Component, request.Level, time.Duration(request.TTLSeconds)*time.Second); err != nil {
+		return fmt.Errorf("SetLogLevel: %w", err)
+	}
+
+	return nil
+}
+
+// Reconnect reconnects orchestrator websocket.
+func (s *Service) Reconnect() {
+	s.messagePublisher.Reconnect()
+}
+
+// WriteDiagBundle writes tar.gz archive with agent status, history, masked config and the agent log tail.
+func (s *Service) WriteDiagBundle(ctx context.Context, w io.Writer) (err error) {
+	status, err := s.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	cfg, err := s.Config("")
+	if err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	transactions, err := s.Transactions(ctx)
+	if err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	createdAt := time.Now()
+
+	for _, item := range []struct {
+		name string
+		data any
+	}{
+		{name: "status.json", data: status},
+		{name: "state_history.json", data: s.StateHistory()},
+		{name: "config.json", data: cfg},
+		{name: "transactions.json", data: transactions},
+		{name: "runtime.json", data: s.debugService.GetRuntimeStats()},
+		{name: "log_levels.json", data: s.loggingService.GetLevels()},
+	} {
+		data, mErr := json.MarshalIndent(item.data, "", "  ")
+		if mErr != nil {
+			return fmt.Errorf("WriteDiagBundle: %w", mErr)
+		}
+
+		if err = writeBundleFile(tarWriter, item.name, data, createdAt); err != nil {
+			return fmt.Errorf("WriteDiagBundle: %w", err)
+		}
+	}
+
+	logTail, err := s.readLogTail()
+	if err != nil {
+		log.Warn().Err(err).Msg("WriteDiagBundle: read agent log error")
+	} else if err = writeBundleFile(tarWriter, "agent.log", logTail, createdAt); err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	if err = gzipWriter.Close(); err != nil {
+		return fmt.Errorf("WriteDiagBundle: %w", err)
+	}
+
+	return nil
+}
+
+// readLogTail reads the end of the agent log, lines are already masked by log writer.
+func (s *Service) readLogTail() (data []byte, err error) {
+	file, err := os.Open(s.logFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("readLogTail: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("readLogTail: %w", err)
+	}
+
+	if offset := info.Size() - bundleLogTailBytes; offset > 0 {
+		if _, err = file.Seek(offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("readLogTail: %w", err)
+		}
+	}
+
+	if data, err = io.ReadAll(file); err != nil {
+		return nil, fmt.Errorf("readLogTail: %w", err)
+	}
+
+	return data, nil
+}
+
+func writeBundleFile(tarWriter *tar.Writer, name string, data []byte, modTime time.Time) (err error) {
+	if err = tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    bundleFilePerm,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("writeBundleFile: %w", err)
+	}
+
+	if _, err = tarWriter.Write(data); err != nil {
+		return fmt.Errorf("writeBundleFile: %w", err)
+	}
+
+	return nil
+}
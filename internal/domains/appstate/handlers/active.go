@@ -19,10 +19,6 @@ import (
 	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
 )
 
-const (
-	deviceInitTimeout = 2 * time.Minute
-)
-
 type ActiveStateHandler struct {
 	configService     IConfigService
 	systemdService    ISystemdService
@@ -31,6 +27,7 @@ type ActiveStateHandler struct {
 	firstPortService  IFirstPortService
 	deviceInitService IDeviceInitService
 	activityService   IActivityService
+	settings          ISettings
 	deviceType        string
 
 	mqSubjects []string
@@ -38,7 +35,7 @@ type ActiveStateHandler struct {
 
 func NewActiveStateHandler(configService IConfigService, systemdService ISystemdService,
 	mqService IMQService, websocketService IWebsocketService, firstPortService IFirstPortService,
-	deviceInitService IDeviceInitService, activityService IActivityService,
+	deviceInitService IDeviceInitService, activityService IActivityService, settings ISettings,
 	deviceType string) *ActiveStateHandler {
 	return &ActiveStateHandler{
 		configService:     configService,
@@ -48,6 +45,7 @@ func NewActiveStateHandler(configService IConfigService, systemdService ISystemd
 		firstPortService:  firstPortService,
 		deviceInitService: deviceInitService,
 		activityService:   activityService,
+		settings:          settings,
 		deviceType:        deviceType,
 
 		mqSubjects: []string{
@@ -223,7 +221,7 @@ func (h *ActiveStateHandler) runFirstSetup(ctx context.Context, tx *activity.Tra
 	// wait for device init
 	select {
 	case err = <-waitInitChan:
-	case <-time.After(deviceInitTimeout):
+	case <-time.After(h.settings.Tunables().DeviceInitTimeout):
 		err = errors.New("device init timeout")
 	}
 	if err != nil {
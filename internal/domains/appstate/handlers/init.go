@@ -37,6 +37,11 @@ type (
 		UpdateConfigWithTx(ctx context.Context, tx *activity.Transaction, cfg config.Config, updateFuncs ...config.UpdateOption) (err error)
 	}
 
+	// ISectionGenerator applies config section to live system, section missing in old config is reconciled with live state.
+	ISectionGenerator interface {
+		Apply(ctx context.Context, tx *activity.Transaction, oldCfg, newCfg config.Config) (err error)
+	}
+
 	IWebsocketService interface {
 		IsStarted() bool
 		Start() (err error)
@@ -99,6 +104,10 @@ type (
 	INSLookupService interface {
 		SyncHosts() (err error)
 	}
+
+	ISettings interface {
+		Tunables() environment.Tunables
+	}
 )
 
 type InitStateHandler struct {
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os/exec"
 	"slices"
 	"time"
 
@@ -24,8 +23,7 @@ import (
 )
 
 const (
-	installDevicePackagesTimeout = 30 * time.Second
-	installRequestTimeout        = 2 * time.Second
+	installRequestTimeout = 2 * time.Second
 )
 
 type MaintenanceStateHandler struct {
@@ -34,16 +32,37 @@ type MaintenanceStateHandler struct {
 	messagePublisher IMessagePublisher
 	websocketService IWebsocketService
 	activityService  IActivityService
+	healthService    IHealthService
+	shellService     IShellService
+	upgradeStore     IUpgradeStore
+	eventService     IEventService
+	packageVerifier  IPackageVerifier
+	settings         ISettings
+
+	updateManagerUpgrader IUpdateManagerUpgrader
+	agentRollback         IAgentRollbackGuard
 }
 
 func NewMaintenanceStateHandler(mqService IMQService, configService IConfigService, messagePublisher IMessagePublisher,
-	websocketService IWebsocketService, activityService IActivityService) *MaintenanceStateHandler {
+	websocketService IWebsocketService, activityService IActivityService, healthService IHealthService,
+	shellService IShellService, upgradeStore IUpgradeStore, eventService IEventService,
+	packageVerifier IPackageVerifier, updateManagerUpgrader IUpdateManagerUpgrader, agentRollback IAgentRollbackGuard,
+	settings ISettings) *MaintenanceStateHandler {
 	return &MaintenanceStateHandler{
 		mqService:        mqService,
 		configService:    configService,
 		messagePublisher: messagePublisher,
 		websocketService: websocketService,
 		activityService:  activityService,
+		healthService:    healthService,
+		shellService:     shellService,
+		upgradeStore:     upgradeStore,
+		eventService:     eventService,
+		packageVerifier:  packageVerifier,
+		settings:         settings,
+
+		updateManagerUpgrader: updateManagerUpgrader,
+		agentRollback:         agentRollback,
 	}
 }
 
@@ -66,6 +85,7 @@ func (h *MaintenanceStateHandler) Handle(ctx context.Context, tx *activity.Trans
 			Msg("Handle: after boot transition")
 
 		updErr := h.waitInstallFinishedAfterBoot()
+		updErr = h.checkUpgradeAfterBoot(ctx, tx, updErr)
 		if sErr := h.sendInstallFinished(updErr); sErr != nil {
 			log.Error().
 				Err(sErr).
@@ -105,10 +125,8 @@ func (h *MaintenanceStateHandler) installUpdateManager(request entities.InstallP
 	if pkg, ok := lo.Find(request.PackagesToInstall, func(item entities.PackageItem) (ok bool) {
 		return item.Name == constants.SdwanUpdateManagerPackageName
 	}); ok {
-		newVersion, currentVersion := pkg.Version, pkg.PreviousVersion
-		output, err := exec.Command("bash", "-c", entities.InstallUpdateManagerScript, "-", newVersion, currentVersion).CombinedOutput() //nolint:gosec // all arguments are valid
-		if err != nil {
-			return result, fmt.Errorf("installUpdateManager: %w: %s", err, output)
+		if err = h.updateManagerUpgrader.Upgrade(pkg.Version, pkg.PreviousVersion); err != nil {
+			return result, fmt.Errorf("installUpdateManager: %w", err)
 		}
 
 		request.PackagesToInstall = slices.DeleteFunc(request.PackagesToInstall, func(item entities.PackageItem) bool {
@@ -120,18 +138,57 @@ func (h *MaintenanceStateHandler) installUpdateManager(request entities.InstallP
 }
 
 func (h *MaintenanceStateHandler) updateDevice(ctx context.Context, tx *activity.Transaction, request entities.InstallPackageRequest) (err error) {
+	// nothing is installed if any archive does not match
+	if err = h.packageVerifier.VerifyPackages(request.PackagesToInstall); err != nil {
+		return fmt.Errorf("updateDevice: %w", err)
+	}
+
 	request, err = h.installUpdateManager(request)
 	if err != nil {
 		return fmt.Errorf("updateDevice: %w", err)
 	}
 
+	// saved request is used by health gate after agent restart on self upgrade
+	pending := entities.PendingUpgrade{
+		Request:   request,
+		StartedAt: time.Now(),
+	}
+	if err = h.upgradeStore.SavePending(pending); err != nil {
+		return fmt.Errorf("updateDevice: %w", err)
+	}
+
+	defer func() {
+		if dErr := h.upgradeStore.DeletePending(); dErr != nil {
+			log.Error().Err(dErr).Msg("updateDevice: delete pending upgrade error")
+		}
+
+		// agent was not replaced, or the new one did not restart it
+		if dErr := h.agentRollback.Disarm(); dErr != nil {
+			log.Error().Err(dErr).Msg("updateDevice: disarm agent rollback error")
+		}
+	}()
+
+	h.armAgentRollback(request)
+
+	if err = h.installPackages(ctx, tx, request); err != nil {
+		return fmt.Errorf("updateDevice: %w", err)
+	}
+
+	if err = h.checkUpgrade(ctx, tx, pending); err != nil {
+		return fmt.Errorf("updateDevice: %w", err)
+	}
+
+	return nil
+}
+
+func (h *MaintenanceStateHandler) installPackages(ctx context.Context, tx *activity.Transaction, request entities.InstallPackageRequest) (err error) {
 	if lo.ContainsBy(request.PackagesToInstall, func(item entities.PackageItem) bool {
 		return item.Name == constants.SdwanAgentPackageName ||
 			item.Name == constants.SdwanBGPAdapterPackageName ||
 			item.Name == constants.SdwanBGPDPackageName
 	}) {
 		if err = h.deleteServices(ctx, tx); err != nil {
-			return fmt.Errorf("updateDevice: %w", err)
+			return fmt.Errorf("installPackages: %w", err)
 		}
 	}
 
@@ -141,7 +198,7 @@ func (h *MaintenanceStateHandler) updateDevice(ctx context.Context, tx *activity
 		// commit changes and wait for restart agent service
 		var checkpointID string
 		if checkpointID, err = h.activityService.AddCheckPoint(ctx, tx); err != nil {
-			return fmt.Errorf("updateDevice: %w", err)
+			return fmt.Errorf("installPackages: %w", err)
 		}
 
 		defer func() {
@@ -152,27 +209,27 @@ func (h *MaintenanceStateHandler) updateDevice(ctx context.Context, tx *activity
 			if delErr := h.activityService.DeleteCheckPoint(ctx, tx, checkpointID); delErr != nil {
 				log.Error().
 					Err(delErr).
-					Msg("updateDevice: cleanup checkpoint error")
+					Msg("installPackages: cleanup checkpoint error")
 			}
 		}()
 	}
 
 	resp, err := h.mqService.Request(constants.MQUpdateManagerInstall, request, installRequestTimeout)
 	if err != nil {
-		return fmt.Errorf("updateDevice: %w", err)
+		return fmt.Errorf("installPackages: %w", err)
 	}
 
 	var data mq.Response
 	if err = json.Unmarshal(resp.Data, &data); err != nil {
-		return fmt.Errorf("updateDevice: %w", err)
+		return fmt.Errorf("installPackages: %w", err)
 	}
 
 	if data.IsError() {
-		return fmt.Errorf("updateDevice: %w", data.Error())
+		return fmt.Errorf("installPackages: %w", data.Error())
 	}
 
 	if err = h.waitInstallFinished(); err != nil {
-		return fmt.Errorf("updateDevice: %w", err)
+		return fmt.Errorf("installPackages: %w", err)
 	}
 
 	return nil
@@ -231,7 +288,7 @@ func (h *MaintenanceStateHandler) waitInstallFinished() (err error) {
 
 		return nil
 
-	case <-time.After(installDevicePackagesTimeout):
+	case <-time.After(h.settings.Tunables().InstallPackagesTimeout):
 		return errors.New("waitInstallFinished: install timeout")
 	}
 }
@@ -277,7 +334,7 @@ func (h *MaintenanceStateHandler) waitInstallFinishedAfterBoot() (err error) {
 
 		return nil
 
-	case <-time.After(installDevicePackagesTimeout):
+	case <-time.After(h.settings.Tunables().InstallPackagesTimeout):
 		log.Error().
 			Msg("waitInstallFinishedAfterBoot: install device packages timeout")
 
@@ -288,9 +345,11 @@ func (h *MaintenanceStateHandler) waitInstallFinishedAfterBoot() (err error) {
 func (h *MaintenanceStateHandler) sendInstallFinished(updErr error) (err error) {
 	body := struct {
 		ErrorMessage string `json:"errorMessage"`
+		RolledBack   bool   `json:"rolledBack"`
 	}{}
 	if updErr != nil {
 		body.ErrorMessage = updErr.Error()
+		body.RolledBack = errors.Is(updErr, errs.ErrUpgradeRolledBack)
 	}
 
 	defer func() {
@@ -185,6 +185,7 @@ func (h *ResetStateHandler) reset(ctx context.Context, tx *activity.Transaction)
 	}
 
 	// close badger
+	badgerDir := h.db.Opts().Dir
 	if err = h.db.Close(); err != nil {
 		if delErr := h.activityService.DeleteCheckPoint(ctx, tx, checkpoinID); delErr != nil {
 			log.Error().
@@ -196,7 +197,7 @@ func (h *ResetStateHandler) reset(ctx context.Context, tx *activity.Transaction)
 	}
 
 	// delete config
-	if rmErr := os.RemoveAll(constants.AgentConfigPath); rmErr != nil && !os.IsNotExist(rmErr) {
+	if rmErr := os.RemoveAll(badgerDir); rmErr != nil && !os.IsNotExist(rmErr) {
 		log.Error().
 			Err(err).
 			Msg("reset")
@@ -10,6 +10,7 @@ import (
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/ping"
 	"github.com/rs/zerolog/log"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/configcheck"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
@@ -17,23 +18,26 @@ import (
 )
 
 type UpdateConfigStateHandler struct {
-	configService    IConfigService
-	ponyService      IPonyService
-	pingService      IPingService
-	nsLookupService  INSLookupService
-	websocketService IWebsocketService
-	deviceType       string
+	configService     IConfigService
+	ponyService       IPonyService
+	pingService       IPingService
+	nsLookupService   INSLookupService
+	websocketService  IWebsocketService
+	sectionGenerators map[string]ISectionGenerator // by drift section
+	deviceType        string
 }
 
 func NewUpdateConfigStateHandler(configService IConfigService, ponyService IPonyService, pingService IPingService,
-	nsLookupService INSLookupService, websocketService IWebsocketService, deviceType string) *UpdateConfigStateHandler {
+	nsLookupService INSLookupService, websocketService IWebsocketService, sectionGenerators map[string]ISectionGenerator,
+	deviceType string) *UpdateConfigStateHandler {
 	return &UpdateConfigStateHandler{
-		configService:    configService,
-		ponyService:      ponyService,
-		pingService:      pingService,
-		nsLookupService:  nsLookupService,
-		websocketService: websocketService,
-		deviceType:       deviceType,
+		configService:     configService,
+		ponyService:       ponyService,
+		pingService:       pingService,
+		nsLookupService:   nsLookupService,
+		websocketService:  websocketService,
+		sectionGenerators: sectionGenerators,
+		deviceType:        deviceType,
 	}
 }
 
@@ -75,6 +79,20 @@ func (h *UpdateConfigStateHandler) Handle(ctx context.Context, tx *activity.Tran
 		return result, nil
 	}
 
+	if data, ok := transition.(*entities.OnPatchWgPeers); ok {
+		log.Info().
+			Any("target state", h.StateID()).
+			Str("interface", data.Patch.Interface).
+			Msg("Handle: patch wireguard peers transition")
+
+		if err = h.applyWgPeersPatch(ctx, tx, data.Patch); err != nil {
+			return result, fmt.Errorf("Handle: %w", err)
+		}
+
+		result.Transition = entities.NewOnUpdateConfigFinished()
+		return result, nil
+	}
+
 	if _, ok := transition.(*entities.OnRebuildServices); ok {
 		log.Info().
 			Any("target state", h.StateID()).
@@ -88,6 +106,20 @@ func (h *UpdateConfigStateHandler) Handle(ctx context.Context, tx *activity.Tran
 		return result, nil
 	}
 
+	if data, ok := transition.(*entities.OnReapplySections); ok {
+		log.Info().
+			Any("target state", h.StateID()).
+			Strs("sections", data.Sections).
+			Msg("Handle: reapply sections transition")
+
+		if err = h.reapplySections(ctx, tx, data.Sections); err != nil {
+			return result, fmt.Errorf("Handle: %w", err)
+		}
+
+		result.Transition = entities.NewOnUpdateConfigFinished()
+		return result, nil
+	}
+
 	return result, fmt.Errorf("Handle: %w", errs.ErrInvalidTransitionType)
 }
 
@@ -153,6 +185,38 @@ func (h *UpdateConfigStateHandler) updateConfig(ctx context.Context, tx *activit
 	return nil
 }
 
+// applyWgPeersPatch patches wireguard configs of current config and applies wireguard section only.
+func (h *UpdateConfigStateHandler) applyWgPeersPatch(ctx context.Context, tx *activity.Transaction, patch entities.WgPeersPatch) (err error) {
+	cfg, err := h.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("applyWgPeersPatch: %w", err)
+	}
+
+	var wgConfigs []config.WgConfig
+	if cfg.Wireguard != nil {
+		wgConfigs = cfg.Wireguard.Configs
+	}
+
+	if wgConfigs, err = patchWgPeers(wgConfigs, patch); err != nil {
+		return fmt.Errorf("applyWgPeersPatch: %w", err)
+	}
+
+	update := config.Config{
+		Wireguard: &config.WireguardSection{
+			Configs: wgConfigs,
+		},
+	}
+	if err = configcheck.Validate(cfg, update); err != nil {
+		return fmt.Errorf("applyWgPeersPatch: %w", err)
+	}
+
+	if err = h.updateConfig(ctx, tx, update); err != nil {
+		return fmt.Errorf("applyWgPeersPatch: %w", err)
+	}
+
+	return nil
+}
+
 func (h *UpdateConfigStateHandler) rebuildServices(ctx context.Context, tx *activity.Transaction) (err error) {
 	cfg, err := h.configService.GetConfig()
 	if err != nil {
@@ -190,6 +254,47 @@ func (h *UpdateConfigStateHandler) rebuildServices(ctx context.Context, tx *acti
 	return nil
 }
 
+// reapplySections applies config sections again, so generators restore live state drifted from config.
+// Generator reconciles live state with section missing in old config, other sections are not touched.
+func (h *UpdateConfigStateHandler) reapplySections(ctx context.Context, tx *activity.Transaction, sections []string) (err error) {
+	cfg, err := h.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("reapplySections: %w", err)
+	}
+
+	var (
+		oldCfg     = cfg
+		generators = make([]ISectionGenerator, 0, len(sections))
+	)
+	for _, section := range sections {
+		generator, exists := h.sectionGenerators[section]
+		if !exists {
+			return fmt.Errorf("reapplySections: section %s cannot be reapplied", section)
+		}
+
+		switch section {
+		case entities.DriftSectionIPRule:
+			oldCfg.IPRule = nil
+		case entities.DriftSectionLoopback:
+			oldCfg.Loopback = nil
+		case entities.DriftSectionWireguard:
+			oldCfg.Wireguard = nil
+		default:
+			return fmt.Errorf("reapplySections: section %s cannot be reapplied", section)
+		}
+
+		generators = append(generators, generator)
+	}
+
+	for _, generator := range generators {
+		if err = generator.Apply(ctx, tx, oldCfg, cfg); err != nil {
+			return fmt.Errorf("reapplySections: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (h *UpdateConfigStateHandler) isPortConfigurationChanged(oldCfg, newCfg config.Config) bool {
 	if newCfg.Port != nil && !oldCfg.Port.Compare(newCfg.Port) {
 		return true
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/activity"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/shellcmd"
+)
+
+const (
+	defaultHealthGateTimeout = 3 * time.Minute
+	healthGateCheckInterval  = 5 * time.Second
+	maxUnitNameLength        = 255
+
+	// agentRollbackMargin time to install packages, restart agent and boot it till health gate
+	agentRollbackMargin = 15 * time.Minute
+	// maxHealthGateBoots agent restarts during health gate after which upgrade is rolled back without the gate
+	maxHealthGateBoots = 3
+)
+
+// unitNameRegexp systemd unit name (optionally with type suffix), it must not look like systemctl option.
+var unitNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9:_.@\\][a-zA-Z0-9:_.@\\-]*$`)
+
+type (
+	IHealthService interface {
+		Check(ctx context.Context) (health entities.AgentHealth)
+	}
+
+	IUpgradeStore interface {
+		GetPending() (pending entities.PendingUpgrade, found bool, err error)
+		SavePending(pending entities.PendingUpgrade) (err error)
+		DeletePending() (err error)
+	}
+
+	IEventService interface {
+		Report(event entities.AgentEvent)
+	}
+
+	IPackageVerifier interface {
+		VerifyPackages(packages entities.PackageItems) (err error)
+	}
+
+	IUpdateManagerUpgrader interface {
+		Upgrade(version, previousVersion string) (err error)
+	}
+
+	IAgentRollbackGuard interface {
+		Arm(previousVersion string, after time.Duration) (err error)
+		Fired() (fired bool, err error)
+		Disarm() (err error)
+	}
+)
+
+// checkUpgradeAfterBoot finishes upgrade interrupted by agent restart: runs health gate for the new agent or
+// reports rollback started by the previous agent.
+//
+// Agent can not roll itself back if the new version crashes before the gate, so agent self upgrade is guarded
+// by systemd timer armed before install (see armAgentRollback), and restarts during the gate are counted.
+// Limitations: the timer does not survive device reboot, and agent restored by the timer reports rollback
+// only if its version already has the gate, older agents just drop pending upgrade.
+func (h *MaintenanceStateHandler) checkUpgradeAfterBoot(ctx context.Context, tx *activity.Transaction, installErr error) (err error) {
+	pending, found, err := h.upgradeStore.GetPending()
+	if err != nil {
+		log.Error().Err(err).Msg("checkUpgradeAfterBoot: get pending upgrade error")
+		return installErr
+	}
+
+	if !found {
+		return installErr
+	}
+
+	defer func() {
+		if dErr := h.upgradeStore.DeletePending(); dErr != nil {
+			log.Error().Err(dErr).Msg("checkUpgradeAfterBoot: delete pending upgrade error")
+		}
+
+		if dErr := h.agentRollback.Disarm(); dErr != nil {
+			log.Error().Err(dErr).Msg("checkUpgradeAfterBoot: disarm agent rollback error")
+		}
+	}()
+
+	fired, err := h.agentRollback.Fired()
+	if err != nil {
+		log.Error().Err(err).Msg("checkUpgradeAfterBoot: check agent rollback error")
+	}
+
+	switch {
+	case pending.RollingBack:
+		rollbackErr := fmt.Errorf("%w: %s", errs.ErrUpgradeRolledBack, pending.GateError)
+		if installErr != nil {
+			return errors.Join(rollbackErr, fmt.Errorf("rollback install: %w", installErr))
+		}
+
+		return rollbackErr
+
+	case fired:
+		return fmt.Errorf("%w: agent did not pass health gate in %s, previous agent is restored by rollback timer",
+			errs.ErrUpgradeRolledBack, healthGateTimeout(lo.FromPtr(pending.Request.HealthGate))+agentRollbackMargin)
+
+	case installErr != nil:
+		return installErr
+	}
+
+	// pending upgrade is deleted when the gate is finished, so every boot with it is restart during the gate
+	pending.Boots++
+	if err = h.upgradeStore.SavePending(pending); err != nil {
+		log.Error().Err(err).Msg("checkUpgradeAfterBoot: save pending upgrade error")
+	}
+
+	return h.checkUpgrade(ctx, tx, pending)
+}
+
+// armAgentRollback arms rollback timer if the request upgrades agent itself and health gate is enabled.
+func (h *MaintenanceStateHandler) armAgentRollback(request entities.InstallPackageRequest) {
+	gate := lo.FromPtr(request.HealthGate)
+	agent, found := lo.Find(request.PackagesToInstall, func(item entities.PackageItem) bool {
+		return item.Name == constants.SdwanAgentPackageName
+	})
+	if gate.Disabled || !found || agent.IsNew || lo.IsEmpty(agent.PreviousVersion) {
+		return
+	}
+
+	// upgrade is not refused: previous archive may be missing if the agent was installed manually
+	if err := h.agentRollback.Arm(agent.PreviousVersion, healthGateTimeout(gate)+agentRollbackMargin); err != nil {
+		log.Error().
+			Err(err).
+			Str("previous version", agent.PreviousVersion).
+			Msg("armAgentRollback: agent rollback is not armed")
+	}
+}
+
+// checkUpgrade waits until device is healthy after install, otherwise installs previous versions of packages.
+func (h *MaintenanceStateHandler) checkUpgrade(ctx context.Context, tx *activity.Transaction, pending entities.PendingUpgrade) (err error) {
+	gate := lo.FromPtr(pending.Request.HealthGate)
+	if gate.Disabled {
+		return nil
+	}
+
+	var gateErr error
+	if pending.Boots > maxHealthGateBoots {
+		gateErr = fmt.Errorf("%w: agent restarted %d times during the gate", errs.ErrHealthGateFailed, pending.Boots-1)
+	} else {
+		gateErr = h.waitHealthy(ctx, gate, pending.Request.PackagesToInstall)
+	}
+	if gateErr == nil {
+		return nil
+	}
+
+	rollback := rollbackRequest(pending.Request)
+	if len(rollback.PackagesToInstall) == 0 {
+		return fmt.Errorf("checkUpgrade: %w", gateErr)
+	}
+
+	// previous archives are checked as strictly as the upgrade ones
+	if err = h.packageVerifier.VerifyPackages(rollback.PackagesToInstall); err != nil {
+		return fmt.Errorf("checkUpgrade: %w", errors.Join(gateErr, fmt.Errorf("rollback: %w", err)))
+	}
+
+	log.Error().
+		Err(gateErr).
+		Any("packages", rollback.PackagesToInstall).
+		Msg("checkUpgrade: health gate failed, rollback packages")
+
+	h.eventService.Report(entities.AgentEvent{
+		Source:   "maintenance",
+		Type:     "upgrade_rollback",
+		Severity: entities.EventSeverityError,
+		Message:  "post-upgrade health gate failed, packages are rolled back to previous versions",
+		Data: struct {
+			Error    string                `json:"error"`
+			Packages entities.PackageItems `json:"packages"`
+		}{
+			Error:    gateErr.Error(),
+			Packages: rollback.PackagesToInstall,
+		},
+	})
+
+	// rollback is done by this agent, timer must not install previous agent once more
+	if err = h.agentRollback.Disarm(); err != nil {
+		log.Error().Err(err).Msg("checkUpgrade: disarm agent rollback error")
+	}
+
+	// rollback may restart agent, the new agent reports result
+	pending.RollingBack = true
+	pending.GateError = gateErr.Error()
+	if err = h.upgradeStore.SavePending(pending); err != nil {
+		return fmt.Errorf("checkUpgrade: %w", errors.Join(gateErr, err))
+	}
+
+	if err = h.installPackages(ctx, tx, rollback); err != nil {
+		return fmt.Errorf("checkUpgrade: %w", errors.Join(gateErr, fmt.Errorf("rollback: %w", err)))
+	}
+
+	return fmt.Errorf("checkUpgrade: %w: %w", errs.ErrUpgradeRolledBack, gateErr)
+}
+
+// waitHealthy polls gate checks until all of them pass or timeout expires.
+func (h *MaintenanceStateHandler) waitHealthy(ctx context.Context, gate entities.UpgradeHealthGate, packages entities.PackageItems) (err error) {
+	timeout := healthGateTimeout(gate)
+
+	units := gate.Units
+	if len(units) == 0 {
+		units = defaultGateUnits(packages)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(healthGateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		problems := h.gateProblems(ctx, gate, units)
+		if len(problems) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waitHealthy: %w after %s: %s", errs.ErrHealthGateFailed, timeout, strings.Join(problems, "; "))
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *MaintenanceStateHandler) gateProblems(ctx context.Context, gate entities.UpgradeHealthGate, units []string) (problems []string) {
+	health := h.healthService.Check(ctx)
+	if !gate.SkipWebsocket && !health.WebsocketConnected {
+		problems = append(problems, "websocket is not connected")
+	}
+
+	if !gate.SkipTunnels && len(health.Tunnels) > 0 && !lo.ContainsBy(health.Tunnels, func(tunnel entities.TunnelHealth) bool {
+		return tunnel.Up
+	}) {
+		problems = append(problems, "no pony tunnel is up")
+	}
+
+	for _, unit := range units {
+		if !isValidUnitName(unit) {
+			problems = append(problems, fmt.Sprintf("unit name %q is invalid", unit))
+			continue
+		}
+
+		if err := h.shellService.Exec(shellcmd.New("systemctl", "is-active", "--quiet", unit)); err != nil {
+			problems = append(problems, fmt.Sprintf("unit %s is not active", unit))
+		}
+	}
+
+	return problems
+}
+
+func healthGateTimeout(gate entities.UpgradeHealthGate) time.Duration {
+	if gate.TimeoutSeconds > 0 {
+		return time.Duration(gate.TimeoutSeconds) * time.Second
+	}
+
+	return defaultHealthGateTimeout
+}
+
+func isValidUnitName(unit string) bool {
+	return len(unit) <= maxUnitNameLength && unitNameRegexp.MatchString(unit)
+}
+
+// defaultGateUnits returns daemons of installed packages, update manager is checked always.
+func defaultGateUnits(packages entities.PackageItems) []string {
+	units := []string{constants.UpdateManagerServiceName}
+	for _, pkg := range packages {
+		switch pkg.Name {
+		case constants.SdwanBGPDPackageName:
+			units = append(units, constants.BGPDServiceName)
+		case constants.SdwanBGPAdapterPackageName:
+			units = append(units, constants.BGPAdapterServiceName)
+		}
+	}
+
+	return units
+}
+
+// rollbackRequest returns request to install previous versions of upgraded packages. New packages and update
+// manager (it is rolled back by its own install) are skipped.
+func rollbackRequest(request entities.InstallPackageRequest) (rollback entities.InstallPackageRequest) {
+	for _, pkg := range request.PackagesToInstall {
+		if pkg.IsNew || lo.IsEmpty(pkg.PreviousVersion) || pkg.Name == constants.SdwanUpdateManagerPackageName {
+			continue
+		}
+
+		rollback.PackagesToInstall = append(rollback.PackagesToInstall, entities.PackageItem{
+			Name:            pkg.Name,
+			Version:         pkg.PreviousVersion,
+			PreviousVersion: pkg.Version,
+			SHA256:          pkg.PreviousSHA256,
+			PreviousSHA256:  pkg.SHA256,
+		})
+	}
+
+	return rollback
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func Test_rollbackRequest(t *testing.T) {
+	request := entities.InstallPackageRequest{
+		PackagesToInstall: entities.PackageItems{
+			{Name: constants.SdwanAgentPackageName, Version: "1.2.0", PreviousVersion: "1.1.0", SHA256: "new", PreviousSHA256: "old"},
+			{Name: constants.SdwanBGPDPackageName, Version: "2.0.0", PreviousVersion: "1.9.0"},
+			{Name: "sdwan-new-daemon", Version: "1.0.0", IsNew: true},
+		},
+	}
+
+	require.Equal(t, entities.PackageItems{
+		{Name: constants.SdwanAgentPackageName, Version: "1.1.0", PreviousVersion: "1.2.0", SHA256: "old", PreviousSHA256: "new"},
+		{Name: constants.SdwanBGPDPackageName, Version: "1.9.0", PreviousVersion: "2.0.0"},
+	}, rollbackRequest(request).PackagesToInstall)
+
+	require.Equal(t, []string{constants.UpdateManagerServiceName, constants.BGPDServiceName},
+		defaultGateUnits(request.PackagesToInstall))
+}
+
+func Test_isValidUnitName(t *testing.T) {
+	for _, unit := range []string{"sdwan-bgpd", "sdwan-bgpd.service", "getty@tty1.service", "dev-disk-by\\x2dlabel.device"} {
+		require.True(t, isValidUnitName(unit), unit)
+	}
+
+	for _, unit := range []string{"", "-H", "--host=evil", "nginx ssh", "a;reboot", "../unit"} {
+		require.False(t, isValidUnitName(unit), unit)
+	}
+}
+
+type fakeRollbackGuard struct {
+	armed map[string]time.Duration
+}
+
+func (g *fakeRollbackGuard) Arm(previousVersion string, after time.Duration) (err error) {
+	g.armed[previousVersion] = after
+	return nil
+}
+
+func (g *fakeRollbackGuard) Fired() (fired bool, err error) {
+	return false, nil
+}
+
+func (g *fakeRollbackGuard) Disarm() (err error) {
+	return nil
+}
+
+func TestMaintenanceStateHandler_armAgentRollback(t *testing.T) {
+	var (
+		guard   = &fakeRollbackGuard{armed: make(map[string]time.Duration)}
+		handler = &MaintenanceStateHandler{agentRollback: guard}
+	)
+
+	// agent is not upgraded or gate is disabled
+	handler.armAgentRollback(entities.InstallPackageRequest{
+		PackagesToInstall: entities.PackageItems{{Name: constants.SdwanBGPDPackageName, Version: "2.0.0", PreviousVersion: "1.9.0"}},
+	})
+	handler.armAgentRollback(entities.InstallPackageRequest{
+		PackagesToInstall: entities.PackageItems{{Name: constants.SdwanAgentPackageName, Version: "1.2.0", PreviousVersion: "1.1.0"}},
+		HealthGate:        &entities.UpgradeHealthGate{Disabled: true},
+	})
+	require.Empty(t, guard.armed)
+
+	handler.armAgentRollback(entities.InstallPackageRequest{
+		PackagesToInstall: entities.PackageItems{{Name: constants.SdwanAgentPackageName, Version: "1.2.0", PreviousVersion: "1.1.0"}},
+		HealthGate:        &entities.UpgradeHealthGate{TimeoutSeconds: 600},
+	})
+	require.Equal(t, map[string]time.Duration{"1.1.0": 10*time.Minute + agentRollbackMargin}, guard.armed)
+}
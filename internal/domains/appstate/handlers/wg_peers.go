@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+// patchWgPeers returns copy of wireguard configs with applied patch. Whole patch is rejected if any change fails.
+func patchWgPeers(wgConfigs []config.WgConfig, patch entities.WgPeersPatch) (patched []config.WgConfig, err error) {
+	index := slices.IndexFunc(wgConfigs, func(wgCfg config.WgConfig) bool {
+		return wgCfg.GetInterfaceName() == patch.Interface
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("patchWgPeers: %w: %s", errs.ErrWgInterfaceNotFound, patch.Interface)
+	}
+
+	// every peer can be changed only once, so result does not depend on order of changes
+	changed := make(map[string]bool)
+	for _, key := range slices.Concat(
+		peerKeys(patch.Add),
+		peerKeys(patch.Update),
+		patch.Remove,
+	) {
+		if changed[key] {
+			return nil, fmt.Errorf("patchWgPeers: %w: %s", errs.ErrWgPeerDuplicated, key)
+		}
+		changed[key] = true
+	}
+
+	peers := slices.Clone(wgConfigs[index].Peers)
+	findPeer := func(key string) int {
+		return slices.IndexFunc(peers, func(peer config.WgPeer) bool {
+			return peer.PublicKey == key
+		})
+	}
+
+	for _, key := range patch.Remove {
+		i := findPeer(key)
+		if i < 0 {
+			return nil, fmt.Errorf("patchWgPeers: %w: %s", errs.ErrWgPeerNotFound, key)
+		}
+		peers = slices.Delete(peers, i, i+1)
+	}
+
+	for _, peer := range patch.Update {
+		i := findPeer(peer.PublicKey)
+		if i < 0 {
+			return nil, fmt.Errorf("patchWgPeers: %w: %s", errs.ErrWgPeerNotFound, peer.PublicKey)
+		}
+		peers[i] = peer
+	}
+
+	for _, peer := range patch.Add {
+		if findPeer(peer.PublicKey) >= 0 {
+			return nil, fmt.Errorf("patchWgPeers: %w: %s", errs.ErrWgPeerExists, peer.PublicKey)
+		}
+		peers = append(peers, peer)
+	}
+
+	patched = slices.Clone(wgConfigs)
+	patched[index].Peers = peers
+
+	return patched, nil
+}
+
+func peerKeys(peers []config.WgPeer) (keys []string) {
+	for _, peer := range peers {
+		keys = append(keys, peer.PublicKey)
+	}
+
+	return keys
+}
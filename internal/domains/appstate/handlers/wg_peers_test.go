@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+func Test_patchWgPeers(t *testing.T) {
+	wgConfigs := []config.WgConfig{
+		{
+			Interface: config.WgInterface{IsClient: true, ClusterID: 1, Table: 100},
+			Peers:     []config.WgPeer{{PublicKey: "hub1", Endpoint: "1.1.1.1:51820"}, {PublicKey: "hub2"}},
+		},
+		{
+			Interface: config.WgInterface{IsClient: true, ClusterID: 1, Table: 101},
+			Peers:     []config.WgPeer{{PublicKey: "hub1"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		patch    entities.WgPeersPatch
+		expected []config.WgPeer
+		err      error
+	}{
+		{
+			name: "replace hub",
+			patch: entities.WgPeersPatch{
+				Interface: "wgc-1_0",
+				Add:       []config.WgPeer{{PublicKey: "hub3"}},
+				Update:    []config.WgPeer{{PublicKey: "hub1", Endpoint: "2.2.2.2:51820"}},
+				Remove:    []string{"hub2"},
+			},
+			expected: []config.WgPeer{{PublicKey: "hub1", Endpoint: "2.2.2.2:51820"}, {PublicKey: "hub3"}},
+		},
+		{
+			name:  "interface not found",
+			patch: entities.WgPeersPatch{Interface: "wgs-1_0", Remove: []string{"hub1"}},
+			err:   errs.ErrWgInterfaceNotFound,
+		},
+		{
+			name:  "add existing peer",
+			patch: entities.WgPeersPatch{Interface: "wgc-1_0", Add: []config.WgPeer{{PublicKey: "hub2"}}},
+			err:   errs.ErrWgPeerExists,
+		},
+		{
+			name:  "remove unknown peer",
+			patch: entities.WgPeersPatch{Interface: "wgc-1_1", Remove: []string{"hub2"}},
+			err:   errs.ErrWgPeerNotFound,
+		},
+		{
+			name: "peer changed twice",
+			patch: entities.WgPeersPatch{
+				Interface: "wgc-1_0",
+				Update:    []config.WgPeer{{PublicKey: "hub2"}},
+				Remove:    []string{"hub2"},
+			},
+			err: errs.ErrWgPeerDuplicated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := patchWgPeers(wgConfigs, tt.patch)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, patched[0].Peers)
+			require.Equal(t, wgConfigs[1], patched[1])
+		})
+	}
+
+	// source configs are not changed
+	require.Equal(t, []config.WgPeer{{PublicKey: "hub1", Endpoint: "1.1.1.1:51820"}, {PublicKey: "hub2"}}, wgConfigs[0].Peers)
+}
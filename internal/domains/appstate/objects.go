@@ -1,11 +1,14 @@
 package appstate
 
 import (
+	"context"
+
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
 )
 
 type transitionData struct {
 	transition common.IStateTransition
+	exclusive  func(ctx context.Context) error // run instead of transition
 	resultChan chan error
 }
 
@@ -15,3 +18,10 @@ func newTransitionData(transition common.IStateTransition) transitionData {
 		resultChan: make(chan error),
 	}
 }
+
+func newExclusiveData(fn func(ctx context.Context) error) transitionData {
+	return transitionData{
+		exclusive:  fn,
+		resultChan: make(chan error),
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/activity"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/configcheck"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
@@ -35,6 +40,14 @@ type (
 	}
 )
 
+const (
+	historyLength = 50
+
+	heartbeatInterval      = 10 * time.Second
+	loopStallTimeout       = time.Minute
+	transitionStallTimeout = 90 * time.Minute // package install with post-upgrade health gate (up to an hour)
+)
+
 type StateService struct {
 	configService   IConfigService
 	activityService IActivityService
@@ -43,6 +56,23 @@ type StateService struct {
 	stateHandlers  map[entities.AppState]IStateHandler
 	activeState    entities.AppState
 	transitionChan chan transitionData
+	booted         chan struct{}
+	stopped        chan struct{}
+	heartbeat      *entities.Heartbeat
+
+	statsMx     sync.RWMutex
+	stateSince  time.Time
+	transitions map[transitionKey]*entities.TransitionStat
+	history     []entities.TransitionRecord
+	lastApply   entities.HealthcheckStatus
+
+	runningSince time.Time // start of running transition, zero when idle
+	runningTxID  string    // transaction of running transition, empty when idle
+}
+
+type transitionKey struct {
+	from entities.AppState
+	to   entities.AppState
 }
 
 func NewService(configService IConfigService, activityService IActivityService, initState entities.AppState) *StateService {
@@ -53,6 +83,12 @@ func NewService(configService IConfigService, activityService IActivityService,
 
 		activeState:    entities.AppStateBoot,
 		transitionChan: make(chan transitionData),
+		booted:         make(chan struct{}),
+		stopped:        make(chan struct{}),
+		heartbeat:      entities.NewHeartbeat(),
+
+		stateSince:  time.Now(),
+		transitions: make(map[transitionKey]*entities.TransitionStat),
 	}
 }
 
@@ -81,15 +117,82 @@ func (s *StateService) SetStateHandlers(stateHandlers []IStateHandler) {
 
 // ActiveState returns active app state.
 func (s *StateService) ActiveState() entities.AppState {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
 	return s.activeState
 }
 
+// Stats returns active state and transition statistics.
+func (s *StateService) Stats() entities.AppStateStats {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
+	stats := entities.AppStateStats{
+		State:       s.activeState,
+		StateSince:  s.stateSince,
+		Transitions: make([]entities.TransitionStat, 0, len(s.transitions)),
+	}
+	for _, stat := range s.transitions {
+		transition := *stat
+		transition.Duration = stat.Duration.Copy()
+		stats.Transitions = append(stats.Transitions, transition)
+	}
+
+	sort.Slice(stats.Transitions, func(i, j int) bool {
+		if stats.Transitions[i].From != stats.Transitions[j].From {
+			return stats.Transitions[i].From < stats.Transitions[j].From
+		}
+
+		return stats.Transitions[i].To < stats.Transitions[j].To
+	})
+
+	return stats
+}
+
+// History returns last performed transitions, the newest first.
+func (s *StateService) History() (history []entities.TransitionRecord) {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
+	history = make([]entities.TransitionRecord, 0, len(s.history))
+	for i := len(s.history) - 1; i >= 0; i-- {
+		history = append(history, s.history[i])
+	}
+
+	return history
+}
+
+// GetStatus returns progress of the last (or running) state transition transaction.
+func (s *StateService) GetStatus() (status entities.HealthcheckStatus) {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
+	status = s.lastApply
+	status.CompletedSteps = slices.Clone(s.lastApply.CompletedSteps)
+
+	return status
+}
+
+// RunningTransactionID returns transaction of running state transition, empty when no transition runs.
+func (s *StateService) RunningTransactionID() string {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
+	return s.runningTxID
+}
+
 // Perform starts transition to new state.
 func (s *StateService) Perform(transition common.IStateTransition) (err error) {
 	data := newTransitionData(transition)
 	defer close(data.resultChan)
 
-	s.transitionChan <- data
+	select {
+	case s.transitionChan <- data:
+	case <-s.stopped:
+		return fmt.Errorf("Perform: %w", errs.ErrAgentShuttingDown)
+	}
+
 	if err = <-data.resultChan; err != nil {
 		log.Error().
 			Err(err).
@@ -101,36 +204,89 @@ func (s *StateService) Perform(transition common.IStateTransition) (err error) {
 	return nil
 }
 
-// Run starts state service.
+// TryExclusive runs fn in transition loop, so no transition is started until fn returns,
+// e.g. rollback of dangling transactions. Busy loop is not waited for.
+func (s *StateService) TryExclusive(fn func(ctx context.Context) error) (err error) {
+	data := newExclusiveData(fn)
+	defer close(data.resultChan)
+
+	select {
+	case s.transitionChan <- data:
+	case <-s.stopped:
+		return fmt.Errorf("TryExclusive: %w", errs.ErrAgentShuttingDown)
+	default:
+		return fmt.Errorf("TryExclusive: %w", errs.ErrTransitionInProgress)
+	}
+
+	if err = <-data.resultChan; err != nil {
+		return fmt.Errorf("TryExclusive: %w", err)
+	}
+
+	return nil
+}
+
+// Run starts state service. Cancelling ctx stops accepting transitions, transition in progress
+// is not interrupted: its transaction is finished or rolled back before Run returns.
 func (s *StateService) Run(ctx context.Context) {
-	defer close(s.transitionChan)
+	defer close(s.stopped)
+
+	txCtx := context.WithoutCancel(ctx)
 
 	// move to current state after reboot
-	if err := s.setActiveStateFromBoot(ctx); err != nil {
+	s.setRunning(true)
+	if err := s.setActiveStateFromBoot(txCtx); err != nil {
 		log.Fatal().
 			Err(err).
 			Msg("Run")
 	}
+	s.setRunning(false)
+	close(s.booted)
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
 
 	// listen for transition commands
 	for {
 		select {
 		case data := <-s.transitionChan:
+			s.setRunning(true)
+
 			var (
 				transition = data.transition
 				err        error
 			)
-			tx, err := s.activityService.StartTransaction(ctx, "perform state transition",
+			if ctx.Err() != nil {
+				data.resultChan <- fmt.Errorf("Run: %w", errs.ErrAgentShuttingDown)
+				break
+			}
+
+			if data.exclusive != nil {
+				if err = data.exclusive(txCtx); err != nil {
+					err = fmt.Errorf("Run: %w", err)
+				}
+				data.resultChan <- err
+				break
+			}
+
+			if err = s.validateConfig(transition); err != nil {
+				data.resultChan <- fmt.Errorf("Run: %w", err)
+				break
+			}
+
+			tx, err := s.activityService.StartTransaction(txCtx, "perform state transition",
 				activity.NewRollbackStrategyOption(activity.RollbackStrategySkipOnFail),
 			)
 			if err != nil {
 				data.resultChan <- fmt.Errorf("Run: %w", err)
 				break
 			}
+			s.setRunningTransaction(tx.UUID)
 
+			s.recordApplyProgress(tx, transition, nil)
 			for {
 				var result common.StateHandleResult
-				result, err = s.performTransition(ctx, tx, transition)
+				result, err = s.performTransition(txCtx, tx, transition)
+				s.recordApplyProgress(tx, transition, err)
 				if err != nil {
 					break
 				}
@@ -142,7 +298,8 @@ func (s *StateService) Run(ctx context.Context) {
 				transition = result.Transition
 			}
 
-			err = s.activityService.FinishTransaction(ctx, tx, err)
+			err = s.activityService.FinishTransaction(txCtx, tx, err)
+			s.recordApplyFinished(err)
 			if err != nil {
 				data.resultChan <- fmt.Errorf("Run: %w", err)
 				break
@@ -150,9 +307,77 @@ func (s *StateService) Run(ctx context.Context) {
 
 			data.resultChan <- nil
 
+		case <-ticker.C:
+			// wake up idle loop to record heartbeat
+
 		case <-ctx.Done():
 			return
 		}
+
+		s.setRunning(false)
+	}
+}
+
+// Booted returns channel which is closed when boot transitions are done.
+func (s *StateService) Booted() <-chan struct{} {
+	return s.booted
+}
+
+// IsTransitionRunning reports whether state transition is in progress.
+func (s *StateService) IsTransitionRunning() bool {
+	s.statsMx.RLock()
+	defer s.statsMx.RUnlock()
+
+	return !s.runningSince.IsZero()
+}
+
+// CheckProgress returns error if transition loop is stuck: idle loop stopped ticking or transition runs too long.
+func (s *StateService) CheckProgress() (err error) {
+	s.statsMx.RLock()
+	runningSince := s.runningSince
+	s.statsMx.RUnlock()
+
+	if !runningSince.IsZero() {
+		if running := time.Since(runningSince); running > transitionStallTimeout {
+			return fmt.Errorf("CheckProgress: %w: transition is running for %s", errs.ErrLoopStalled, running.Round(time.Second))
+		}
+
+		return nil
+	}
+
+	if since := s.heartbeat.Since(); since > loopStallTimeout {
+		return fmt.Errorf("CheckProgress: %w: no heartbeat for %s", errs.ErrLoopStalled, since.Round(time.Second))
+	}
+
+	return nil
+}
+
+// Wait blocks until Run returns or ctx is done.
+func (s *StateService) Wait(ctx context.Context) (err error) {
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Wait: %w", ctx.Err())
+	}
+}
+
+func (s *StateService) setRunningTransaction(txID string) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	s.runningTxID = txID
+}
+
+func (s *StateService) setRunning(isRunning bool) {
+	s.heartbeat.Beat()
+
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	s.runningSince = time.Time{}
+	if isRunning {
+		s.runningSince = time.Now()
 	}
 }
 
@@ -164,8 +389,10 @@ func (s *StateService) setActiveStateFromBoot(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("setActiveStateFromBoot: %w", err)
 	}
+	s.setRunningTransaction(tx.UUID)
 	defer func() {
 		err = s.activityService.FinishTransaction(ctx, tx, err)
+		s.recordApplyFinished(err)
 	}()
 
 	cfg, err := s.configService.GetConfig()
@@ -179,8 +406,10 @@ func (s *StateService) setActiveStateFromBoot(ctx context.Context) (err error) {
 	}
 
 	var transition common.IStateTransition = entities.NewOnAfterBoot(toState)
+	s.recordApplyProgress(tx, transition, nil)
 	for {
 		result, err := s.performTransition(ctx, tx, transition)
+		s.recordApplyProgress(tx, transition, err)
 		if err != nil {
 			return fmt.Errorf("setActiveStateFromBoot: %w", err)
 		}
@@ -196,8 +425,47 @@ func (s *StateService) setActiveStateFromBoot(ctx context.Context) (err error) {
 	return nil
 }
 
+// validateConfig checks references between sections of the config carried by transition,
+// so invalid update is rejected before transaction is started. Device init config is checked by device init service.
+func (s *StateService) validateConfig(transition common.IStateTransition) (err error) {
+	var update config.Config
+	switch data := transition.(type) {
+	case *entities.OnUpdateConfig:
+		update = data.Config
+	case *entities.OnZTPSetupConfig:
+		update = data.Config
+	case *entities.OnHubSetPort:
+		update = config.Config{
+			Port: &config.PortSection{
+				PortConfigs: []config.PortConfig{data.PortConfig},
+			},
+		}
+	default:
+		return nil
+	}
+
+	current, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("validateConfig: %w", err)
+	}
+
+	if err = configcheck.Validate(current, update); err != nil {
+		return fmt.Errorf("validateConfig: %w", err)
+	}
+
+	return nil
+}
+
 func (s *StateService) performTransition(ctx context.Context, tx *activity.Transaction, transition common.IStateTransition) (result common.StateHandleResult, err error) {
-	newStateID := transition.ToState()
+	var (
+		newStateID = transition.ToState()
+		fromState  = s.activeState
+		startedAt  = time.Now()
+	)
+	defer func() {
+		s.recordTransition(fromState, newStateID, startedAt, err)
+	}()
+
 	// validate transition
 	if s.activeState == newStateID {
 		return result, fmt.Errorf("performTransition: already in state %s", s.activeState)
@@ -266,12 +534,12 @@ func (s *StateService) updateAppState(ctx context.Context, tx *activity.Transact
 	if err = s.activityService.ExecuteFunc(
 		tx,
 		func() error {
-			s.activeState = newStateID
+			s.setActiveState(newStateID)
 			return nil
 		},
 		func() error {
 			if oldStateID != entities.AppStateBoot {
-				s.activeState = oldStateID
+				s.setActiveState(oldStateID)
 			}
 			return nil
 		},
@@ -281,3 +549,91 @@ func (s *StateService) updateAppState(ctx context.Context, tx *activity.Transact
 
 	return nil
 }
+
+func (s *StateService) setActiveState(state entities.AppState) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	s.activeState = state
+	s.stateSince = time.Now()
+}
+
+// recordTransition updates transition statistics.
+func (s *StateService) recordTransition(from, to entities.AppState, startedAt time.Time, err error) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	key := transitionKey{from: from, to: to}
+	stat, exists := s.transitions[key]
+	if !exists {
+		stat = &entities.TransitionStat{
+			From:     from,
+			To:       to,
+			Duration: entities.NewHistogram(entities.DurationBuckets),
+		}
+		s.transitions[key] = stat
+	}
+
+	duration := time.Since(startedAt)
+	stat.Duration.Observe(duration.Seconds())
+	if err != nil {
+		stat.Failures++
+	}
+
+	record := entities.TransitionRecord{
+		From:      from,
+		To:        to,
+		StartedAt: startedAt,
+		Duration:  duration,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if len(s.history) == historyLength {
+		s.history = s.history[1:]
+	}
+	s.history = append(s.history, record)
+}
+
+// recordApplyProgress saves completed activities of the transaction after every transition step.
+// Must be called from the goroutine executing transaction.
+func (s *StateService) recordApplyProgress(tx *activity.Transaction, transition common.IStateTransition, err error) {
+	status := entities.HealthcheckStatus{
+		CompletedSteps: make([]string, 0, len(tx.Activities)),
+	}
+	for _, act := range tx.Activities {
+		if act.Status == activity.StatusComplete {
+			status.CompletedSteps = append(status.CompletedSteps, act.Name)
+			continue
+		}
+
+		if err != nil && status.ErrorStep == "" {
+			status.ErrorStep = act.Name
+		}
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+		if status.ErrorStep == "" {
+			status.ErrorStep = fmt.Sprintf("transition to %s", transition.ToState())
+		}
+	}
+
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	s.lastApply = status
+}
+
+// recordApplyFinished marks the last transaction as finished.
+func (s *StateService) recordApplyFinished(err error) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	s.runningTxID = ""
+	s.lastApply.ExecFinished = true
+	if err != nil && s.lastApply.Error == "" {
+		s.lastApply.Error = err.Error()
+	}
+}
@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	ICaptureService interface {
+		Start(request entities.PacketCaptureRequest) (started entities.PacketCaptureStarted, err error)
+		Stop(captureID string) (err error)
+	}
+
+	Handler struct {
+		messagePublisher IMessagePublisher
+		captureService   ICaptureService
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, captureService ICaptureService) *Handler {
+	return &Handler{
+		messagePublisher: messagePublisher,
+		captureService:   captureService,
+
+		validate: validator.New(),
+	}
+}
+
+// StartPacketCapture starts bounded packet capture, pcap is sent with chunked transfer when capture is finished.
+func (h *Handler) StartPacketCapture(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.PacketCaptureRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("StartPacketCapture: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("StartPacketCapture: %w", err)
+	}
+
+	started, err := h.captureService.Start(requestBody)
+	if err != nil {
+		return fmt.Errorf("StartPacketCapture: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, started); err != nil {
+		return fmt.Errorf("StartPacketCapture: %w", err)
+	}
+
+	return nil
+}
+
+// StopPacketCapture stops running packet capture.
+func (h *Handler) StopPacketCapture(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.StopPacketCaptureRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("StopPacketCapture: %w", err)
+	}
+
+	if err = h.captureService.Stop(requestBody.CaptureID); err != nil {
+		return fmt.Errorf("StopPacketCapture: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, nil); err != nil {
+		return fmt.Errorf("StopPacketCapture: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,426 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	defaultDuration   = 60 * time.Second
+	maxDuration       = 10 * time.Minute
+	defaultMaxPackets = 10000
+	maxPackets        = 1000000
+	defaultMaxBytes   = 10 << 20 // 10MB
+	maxBytes          = 50 << 20 // 50MB
+	defaultSnapLen    = 262144
+
+	minFreeDiskBytes   = 200 << 20 // 200MB
+	maxLoadPerCPU      = 0.8
+	sizeCheckPeriod    = 200 * time.Millisecond
+	stopWaitDelay      = 5 * time.Second
+	finishedSendTimout = 10 * time.Second
+
+	pcapGlobalHeaderSize = 24
+	pcapRecordHeaderSize = 16
+
+	transferKindPcap = "pcap"
+)
+
+const (
+	StopReasonDuration = "duration"
+	StopReasonPackets  = "packets"
+	StopReasonBytes    = "bytes"
+	StopReasonStopped  = "stopped"
+	StopReasonError    = "error"
+)
+
+type (
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	ITransferService interface {
+		Send(transferID, kind string, data []byte) (err error)
+	}
+
+	IMessagePublisher interface {
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
+	}
+
+	Service struct {
+		configService    IConfigService
+		transferService  ITransferService
+		messagePublisher IMessagePublisher
+		captureDir       string
+
+		mx     sync.Mutex
+		active *activeCapture
+	}
+
+	activeCapture struct {
+		id     string
+		cancel context.CancelFunc
+
+		mx         sync.Mutex
+		stopReason string
+	}
+)
+
+func NewService(configService IConfigService, transferService ITransferService, messagePublisher IMessagePublisher, captureDir string) *Service {
+	return &Service{
+		configService:    configService,
+		transferService:  transferService,
+		messagePublisher: messagePublisher,
+		captureDir:       captureDir,
+	}
+}
+
+// Start starts packet capture in background, result is sent to orchestrator as pcap chunks.
+// Only one capture can run at a time.
+func (s *Service) Start(request entities.PacketCaptureRequest) (started entities.PacketCaptureStarted, err error) {
+	if strings.HasPrefix(strings.TrimSpace(request.Filter), "-") {
+		return started, fmt.Errorf("Start: %w: filter must not start with '-'", errs.ErrInvalidFilter)
+	}
+
+	iface, err := s.resolveInterface(request)
+	if err != nil {
+		return started, fmt.Errorf("Start: %w", err)
+	}
+
+	started = entities.PacketCaptureStarted{
+		CaptureID:       uuid.New().String(),
+		Interface:       iface,
+		DurationSeconds: int(limitDuration(time.Duration(request.DurationSeconds) * time.Second).Seconds()),
+		MaxPackets:      limit(request.MaxPackets, defaultMaxPackets, maxPackets),
+		MaxBytes:        int64(limit(int(request.MaxBytes), defaultMaxBytes, maxBytes)),
+	}
+
+	if err = s.checkPressure(started.MaxBytes); err != nil {
+		return started, fmt.Errorf("Start: %w", err)
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.active != nil {
+		return started, fmt.Errorf("Start: %w: capture %s is running", errs.ErrCaptureInProgress, s.active.id)
+	}
+
+	if err = os.MkdirAll(s.captureDir, constants.FilePerm); err != nil {
+		return started, fmt.Errorf("Start: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(started.DurationSeconds)*time.Second)
+	capture := &activeCapture{
+		id:     started.CaptureID,
+		cancel: cancel,
+	}
+	s.active = capture
+
+	go func() {
+		defer cancel()
+		s.run(ctx, capture, started, request)
+	}()
+
+	return started, nil
+}
+
+// Stop stops running capture, captured packets are sent as usual.
+func (s *Service) Stop(captureID string) (err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.active == nil || (captureID != "" && s.active.id != captureID) {
+		return fmt.Errorf("Stop: %w", errs.ErrCaptureNotFound)
+	}
+
+	s.active.stop(StopReasonStopped)
+	return nil
+}
+
+func (s *Service) run(ctx context.Context, capture *activeCapture, started entities.PacketCaptureStarted, request entities.PacketCaptureRequest) {
+	defer func() {
+		s.mx.Lock()
+		s.active = nil
+		s.mx.Unlock()
+	}()
+
+	finished := entities.PacketCaptureFinished{
+		CaptureID: started.CaptureID,
+		Interface: started.Interface,
+	}
+
+	pcapFile := filepath.Join(s.captureDir, started.CaptureID+".pcap")
+	defer func() {
+		if err := os.Remove(pcapFile); err != nil && !os.IsNotExist(err) {
+			log.Error().Err(err).Msg("run: remove capture file error")
+		}
+	}()
+
+	data, err := s.capture(ctx, capture, pcapFile, started, request)
+	finished.StopReason = capture.reason()
+	finished.Size = len(data)
+	if err != nil {
+		finished.StopReason = StopReasonError
+		finished.Error = err.Error()
+	}
+
+	if err == nil {
+		if err = s.transferService.Send(started.CaptureID, transferKindPcap, data); err != nil {
+			finished.StopReason = StopReasonError
+			finished.Error = err.Error()
+		}
+	}
+
+	log.Info().
+		Any("capture", finished).
+		Msg("run: packet capture finished")
+
+	resp, err := s.messagePublisher.PublishRequest(constants.MethodPacketCaptureFinished, constants.OrchestratorWSID, finished,
+		wschat.RequestOptions{
+			Timeout: lo.ToPtr(finishedSendTimout),
+		},
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("run: send capture finished error")
+		return
+	}
+
+	if resp.IsErrorResponse() {
+		log.Error().Err(resp.Error()).Msg("run: send capture finished error")
+	}
+}
+
+func (s *Service) capture(
+	ctx context.Context,
+	capture *activeCapture,
+	pcapFile string,
+	started entities.PacketCaptureStarted,
+	request entities.PacketCaptureRequest,
+) (data []byte, err error) {
+	args := []string{
+		"-i", started.Interface,
+		"-w", pcapFile,
+		"-U", "-n",
+		"-Z", "root", // do not drop privileges, capture directory is owned by root
+		"-s", strconv.Itoa(lo.Ternary(request.SnapLen > 0, request.SnapLen, defaultSnapLen)),
+		"-c", strconv.Itoa(started.MaxPackets),
+	}
+	if filter := strings.TrimSpace(request.Filter); filter != "" {
+		// whole expression as single argument after "--", so it is never parsed as tcpdump option
+		args = append(args, "--", filter)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "tcpdump", args...)
+	cmd.Stderr = &stderr
+	cmd.Cancel = func() error {
+		// SIGINT allows tcpdump to flush buffered packets
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = stopWaitDelay
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+
+	// watch capture file size
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		ticker := time.NewTicker(sizeCheckPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watchDone:
+				return
+			case <-ticker.C:
+				if stat, statErr := os.Stat(pcapFile); statErr == nil && stat.Size() >= started.MaxBytes {
+					capture.stop(StopReasonBytes)
+					return
+				}
+			}
+		}
+	}()
+
+	waitErr := cmd.Wait()
+	switch {
+	case capture.reason() != "":
+		// stopped by size limit or by request
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		capture.setReason(StopReasonDuration)
+	case waitErr != nil:
+		return nil, fmt.Errorf("capture: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	default:
+		capture.setReason(StopReasonPackets)
+	}
+
+	if data, err = os.ReadFile(pcapFile); err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+
+	return truncatePcap(data, started.MaxBytes), nil
+}
+
+// resolveInterface returns interface name for port or pony tunnel.
+func (s *Service) resolveInterface(request entities.PacketCaptureRequest) (iface string, err error) {
+	iface = request.Port
+	if request.Tunnel != "" {
+		if iface, err = s.searchTunnelInterface(request.Tunnel); err != nil {
+			return iface, fmt.Errorf("resolveInterface: %w", err)
+		}
+	}
+
+	if _, err = net.InterfaceByName(iface); err != nil {
+		return iface, fmt.Errorf("resolveInterface: interface %s: %w", iface, err)
+	}
+
+	return iface, nil
+}
+
+func (s *Service) searchTunnelInterface(tunnel string) (iface string, err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return iface, fmt.Errorf("searchTunnelInterface: %w", err)
+	}
+
+	if cfg.Pony == nil || cfg.Wireguard == nil {
+		return iface, fmt.Errorf("searchTunnelInterface: pony tunnel %s not found", tunnel)
+	}
+
+	for _, cluster := range cfg.Pony.Clusters {
+		uplink, err := cluster.GetUplinkConfigByAddr(tunnel)
+		if err != nil {
+			continue
+		}
+
+		for _, wgConfig := range cfg.Wireguard.Configs {
+			if wgConfig.Interface.Table == uplink.TableID {
+				return wgConfig.GetInterfaceName(), nil
+			}
+		}
+	}
+
+	return iface, fmt.Errorf("searchTunnelInterface: pony tunnel %s not found", tunnel)
+}
+
+// checkPressure refuses capture when device lacks free disk space or is overloaded.
+func (s *Service) checkPressure(captureBytes int64) (err error) {
+	var stat syscall.Statfs_t
+	if err = syscall.Statfs(filepath.Dir(s.captureDir), &stat); err != nil {
+		return fmt.Errorf("checkPressure: %w", err)
+	}
+
+	freeBytes := int64(stat.Bavail) * stat.Bsize //nolint:gosec // block counts fit int64
+	if freeBytes < minFreeDiskBytes+2*captureBytes {
+		return fmt.Errorf("checkPressure: %w: only %d bytes of disk space available", errs.ErrDevicePressure, freeBytes)
+	}
+
+	loadAvg, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return fmt.Errorf("checkPressure: %w", err)
+	}
+
+	fields := strings.Fields(string(loadAvg))
+	if len(fields) == 0 {
+		return fmt.Errorf("checkPressure: invalid loadavg format")
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return fmt.Errorf("checkPressure: %w", err)
+	}
+
+	if loadPerCPU := load / float64(runtime.NumCPU()); loadPerCPU > maxLoadPerCPU {
+		return fmt.Errorf("checkPressure: %w: load average %.2f per cpu", errs.ErrDevicePressure, loadPerCPU)
+	}
+
+	return nil
+}
+
+func (c *activeCapture) stop(reason string) {
+	c.setReason(reason)
+	c.cancel()
+}
+
+func (c *activeCapture) setReason(reason string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.stopReason == "" {
+		c.stopReason = reason
+	}
+}
+
+func (c *activeCapture) reason() string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.stopReason
+}
+
+// truncatePcap cuts pcap data by the last whole record fitting into maxSize.
+func truncatePcap(data []byte, maxSize int64) []byte {
+	if int64(len(data)) <= maxSize || len(data) < pcapGlobalHeaderSize {
+		return data
+	}
+
+	byteOrder := binary.ByteOrder(binary.LittleEndian)
+	if magic := binary.BigEndian.Uint32(data); magic == 0xa1b2c3d4 || magic == 0xa1b23c4d {
+		byteOrder = binary.BigEndian
+	}
+
+	offset := pcapGlobalHeaderSize
+	for offset+pcapRecordHeaderSize <= len(data) {
+		recordSize := pcapRecordHeaderSize + int(byteOrder.Uint32(data[offset+8:]))
+		if int64(offset+recordSize) > maxSize || offset+recordSize > len(data) {
+			break
+		}
+
+		offset += recordSize
+	}
+
+	return data[:offset]
+}
+
+func limitDuration(duration time.Duration) time.Duration {
+	if duration <= 0 {
+		return defaultDuration
+	}
+
+	return min(duration, maxDuration)
+}
+
+func limit(value, defaultValue, maxValue int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+
+	return min(value, maxValue)
+}
@@ -0,0 +1,47 @@
+package capture
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_truncatePcap(t *testing.T) {
+	// global header + records with 10 and 20 bytes of packet data
+	data := make([]byte, pcapGlobalHeaderSize)
+	binary.LittleEndian.PutUint32(data, 0xa1b2c3d4)
+	for _, size := range []int{10, 20} {
+		record := make([]byte, pcapRecordHeaderSize+size)
+		binary.LittleEndian.PutUint32(record[8:], uint32(size)) //nolint:gosec // test data
+		data = append(data, record...)
+	}
+
+	tests := []struct {
+		name     string
+		maxSize  int64
+		expected int
+	}{
+		{
+			name:     "fits",
+			maxSize:  1000,
+			expected: len(data),
+		},
+		{
+			name:     "cut by last whole record",
+			maxSize:  70,
+			expected: pcapGlobalHeaderSize + pcapRecordHeaderSize + 10,
+		},
+		{
+			name:     "header only",
+			maxSize:  40,
+			expected: pcapGlobalHeaderSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Len(t, truncatePcap(data, tt.maxSize), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	backupKDFIterations    = 600_000
+	backupMaxKDFIterations = 10 * backupKDFIterations
+	backupKeyLength        = 32
+	backupSaltLength       = 16
+)
+
+// newBackup makes backup document of config, config is encrypted if passphrase is set.
+func newBackup(cfg config.Config, metadata entities.ConfigBackupMetadata, passphrase string) (backup entities.ConfigBackup, err error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return backup, fmt.Errorf("newBackup: %w", err)
+	}
+
+	metadataData, err := json.Marshal(metadata)
+	if err != nil {
+		return backup, fmt.Errorf("newBackup: %w", err)
+	}
+
+	backup = entities.ConfigBackup{
+		FormatVersion: entities.ConfigBackupFormatVersion,
+		Metadata:      metadata,
+		Checksum:      backupChecksum(metadataData, data),
+	}
+
+	if lo.IsEmpty(passphrase) {
+		backup.Config = &cfg
+		return backup, nil
+	}
+
+	encryption := entities.ConfigBackupEncryption{
+		Cipher:     entities.ConfigBackupCipherAES256GCM,
+		KDF:        entities.ConfigBackupKDFPBKDF2SHA256,
+		Iterations: backupKDFIterations,
+		Salt:       make([]byte, backupSaltLength),
+	}
+	if _, err = rand.Read(encryption.Salt); err != nil {
+		return backup, fmt.Errorf("newBackup: %w", err)
+	}
+
+	aead, err := backupCipher(passphrase, encryption)
+	if err != nil {
+		return backup, fmt.Errorf("newBackup: %w", err)
+	}
+
+	encryption.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(encryption.Nonce); err != nil {
+		return backup, fmt.Errorf("newBackup: %w", err)
+	}
+
+	backup.Encryption = &encryption
+	backup.Payload = aead.Seal(nil, encryption.Nonce, data, metadataData)
+
+	return backup, nil
+}
+
+// openBackup checks backup document and returns its config, encrypted backup requires passphrase.
+func openBackup(backup entities.ConfigBackup, passphrase string) (cfg config.Config, err error) {
+	if backup.FormatVersion != entities.ConfigBackupFormatVersion {
+		return cfg, fmt.Errorf("openBackup: %w: %d", errs.ErrUnsupportedBackupVersion, backup.FormatVersion)
+	}
+
+	metadataData, err := json.Marshal(backup.Metadata)
+	if err != nil {
+		return cfg, fmt.Errorf("openBackup: %w", err)
+	}
+
+	var data []byte
+	switch {
+	case backup.Encryption != nil:
+		if lo.IsEmpty(passphrase) {
+			return cfg, fmt.Errorf("openBackup: %w", errs.ErrBackupPassphrase)
+		}
+
+		aead, err := backupCipher(passphrase, *backup.Encryption)
+		if err != nil {
+			return cfg, fmt.Errorf("openBackup: %w", err)
+		}
+
+		if len(backup.Encryption.Nonce) != aead.NonceSize() {
+			return cfg, fmt.Errorf("openBackup: invalid nonce size %d", len(backup.Encryption.Nonce))
+		}
+
+		if data, err = aead.Open(nil, backup.Encryption.Nonce, backup.Payload, metadataData); err != nil {
+			return cfg, fmt.Errorf("openBackup: %w", errs.ErrBackupPassphrase)
+		}
+
+	case backup.Config != nil:
+		if data, err = json.Marshal(backup.Config); err != nil {
+			return cfg, fmt.Errorf("openBackup: %w", err)
+		}
+
+	default:
+		return cfg, fmt.Errorf("openBackup: backup has no config")
+	}
+
+	if backupChecksum(metadataData, data) != backup.Checksum {
+		return cfg, fmt.Errorf("openBackup: %w", errs.ErrBackupChecksumMismatch)
+	}
+
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("openBackup: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// backupChecksum returns hex sha256 of metadata and config json, so neither can be changed alone.
+func backupChecksum(metadata, config []byte) string {
+	hash := sha256.New()
+	hash.Write(metadata)
+	hash.Write(config)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func backupCipher(passphrase string, encryption entities.ConfigBackupEncryption) (aead cipher.AEAD, err error) {
+	if encryption.Cipher != entities.ConfigBackupCipherAES256GCM || encryption.KDF != entities.ConfigBackupKDFPBKDF2SHA256 {
+		return nil, fmt.Errorf("backupCipher: unsupported cipher %s with %s", encryption.Cipher, encryption.KDF)
+	}
+
+	// iterations come with imported document, limit them to not hang on key derivation
+	if encryption.Iterations <= 0 || encryption.Iterations > backupMaxKDFIterations {
+		return nil, fmt.Errorf("backupCipher: invalid iterations %d", encryption.Iterations)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, encryption.Salt, encryption.Iterations, backupKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("backupCipher: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("backupCipher: %w", err)
+	}
+
+	if aead, err = cipher.NewGCM(block); err != nil {
+		return nil, fmt.Errorf("backupCipher: %w", err)
+	}
+
+	return aead, nil
+}
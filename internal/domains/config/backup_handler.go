@@ -0,0 +1,189 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/validator"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
+)
+
+// ExportConfig returns full device config as backup document.
+func (h *Handler) ExportConfig(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.publisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = fmt.Errorf("%w: %w", sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.ExportConfigRequest
+	if len(request.Body) > 0 {
+		if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+			return fmt.Errorf("ExportConfig: %w", err)
+		}
+	}
+
+	if err = validator.Validator.Struct(requestBody); err != nil {
+		return fmt.Errorf("ExportConfig: %w", err)
+	}
+
+	cfg, err := h.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("ExportConfig: %w", err)
+	}
+
+	metadata := entities.ConfigBackupMetadata{
+		CreatedAt:  time.Now().UTC(),
+		DeviceType: h.deviceType,
+	}
+	if cfg.App != nil {
+		metadata.SerialNumber = cfg.App.SerialNumber
+	}
+	if cfg.AppState != nil {
+		metadata.AppState = cfg.AppState.State
+	}
+	if cfg.Version != nil {
+		metadata.ConfigVersion = cfg.Version.ConfigVersion
+	}
+
+	// plain backup leaves device with secrets only if they are requested explicitly
+	if lo.IsEmpty(requestBody.Passphrase) && !requestBody.IncludeSecrets {
+		var redactedCfg config.Config
+		if err = redact.Into(cfg, &redactedCfg); err != nil {
+			return fmt.Errorf("ExportConfig: %w", err)
+		}
+
+		cfg = redactedCfg
+		metadata.Redacted = true
+	}
+
+	backup, err := newBackup(cfg, metadata, requestBody.Passphrase)
+	if err != nil {
+		return fmt.Errorf("ExportConfig: %w", err)
+	}
+
+	if err = h.publisher.PublishResponse(request, backup); err != nil {
+		return fmt.Errorf("ExportConfig: %w", err)
+	}
+
+	return nil
+}
+
+// ImportConfig validates backup document and applies its config in background,
+// result is sent with import config finished request.
+func (h *Handler) ImportConfig(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.publisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = fmt.Errorf("%w: %w", sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.ImportConfigRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("ImportConfig: %w", err)
+	}
+
+	currentCfg, err := h.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("ImportConfig: %w", err)
+	}
+
+	newCfg, err := h.prepareImport(requestBody, currentCfg)
+	if err != nil {
+		return fmt.Errorf("ImportConfig: %w", err)
+	}
+
+	log.Info().
+		Str("serial number", newCfg.App.SerialNumber).
+		Time("created at", requestBody.Backup.Metadata.CreatedAt).
+		Msg("ImportConfig: got config backup to import")
+
+	if err = h.publisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("ImportConfig: %w", err)
+	}
+
+	go func() {
+		updErr := h.appStateService.Perform(entities.NewOnUpdateConfig(newCfg))
+		if updErr != nil {
+			log.Error().
+				Err(updErr).
+				Msg("ImportConfig: import config error")
+		}
+
+		h.notifyFinished(constants.MethodImportConfigFinished, updErr)
+
+		// device identity is changed, orchestrator connection is made again with new serial number
+		if updErr == nil && currentCfg.App != nil && currentCfg.App.SerialNumber != newCfg.App.SerialNumber {
+			h.publisher.Reconnect()
+		}
+	}()
+
+	return nil
+}
+
+// prepareImport opens and validates backup, returns config to apply.
+func (h *Handler) prepareImport(request entities.ImportConfigRequest, currentCfg config.Config) (cfg config.Config, err error) {
+	if err = validator.Validator.Struct(request); err != nil {
+		return cfg, fmt.Errorf("prepareImport: %w", err)
+	}
+
+	if request.Backup.Metadata.DeviceType != h.deviceType {
+		return cfg, fmt.Errorf("prepareImport: backup of %s cannot be imported to %s",
+			request.Backup.Metadata.DeviceType, h.deviceType)
+	}
+
+	if request.Backup.Metadata.Redacted {
+		return cfg, fmt.Errorf("prepareImport: %w", errs.ErrBackupRedacted)
+	}
+
+	if cfg, err = openBackup(request.Backup, request.Passphrase); err != nil {
+		return cfg, fmt.Errorf("prepareImport: %w", err)
+	}
+
+	if err = validator.Validator.Struct(cfg); err != nil {
+		return cfg, fmt.Errorf("prepareImport: %w", err)
+	}
+
+	// metadata flag is not enough, masked secrets would be applied to device as is
+	if redact.Masked(cfg) {
+		return cfg, fmt.Errorf("prepareImport: %w", errs.ErrBackupRedacted)
+	}
+
+	if cfg.App == nil {
+		return cfg, fmt.Errorf("prepareImport: backup has no app section")
+	}
+
+	var currentSerial string
+	if currentCfg.App != nil {
+		currentSerial = currentCfg.App.SerialNumber
+	}
+
+	switch {
+	case !lo.IsEmpty(request.SerialNumber):
+		// RMA swap: replacement device takes configuration with serial number given by orchestrator
+		cfg.App.SerialNumber = request.SerialNumber
+
+	case cfg.App.SerialNumber != currentSerial:
+		return cfg, fmt.Errorf("prepareImport: %w: %s", errs.ErrBackupSerialMismatch, cfg.App.SerialNumber)
+	}
+
+	// version is owned by migrations and app state by state machine
+	cfg.Version = nil
+	cfg.AppState = nil
+
+	return cfg, nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
+)
+
+func testBackupConfig() config.Config {
+	cfg := config.EmptyConfig()
+	cfg.App.SerialNumber = "CPE-0001"
+	cfg.App.OrchestratorAddrs = []string{"orch.example.com"}
+	cfg.AppState.State = "active"
+	cfg.Version.ConfigVersion = 3
+	cfg.AdminState.AdminStatePorts = config.AdminStatePorts{{PortName: "wan1", IsDown: true}}
+
+	return cfg
+}
+
+func TestBackup(t *testing.T) {
+	cfg := testBackupConfig()
+	metadata := entities.ConfigBackupMetadata{SerialNumber: cfg.App.SerialNumber, DeviceType: constants.DeviceTypeCPE}
+
+	t.Run("plain", func(t *testing.T) {
+		backup, err := newBackup(cfg, metadata, "")
+		require.NoError(t, err)
+		require.Nil(t, backup.Encryption)
+
+		opened, err := openBackup(backup, "")
+		require.NoError(t, err)
+		require.Equal(t, cfg, opened)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		backup, err := newBackup(cfg, metadata, "secret passphrase")
+		require.NoError(t, err)
+		require.Nil(t, backup.Config)
+		require.NotEmpty(t, backup.Payload)
+
+		_, err = openBackup(backup, "")
+		require.ErrorIs(t, err, errs.ErrBackupPassphrase)
+
+		_, err = openBackup(backup, "wrong passphrase")
+		require.ErrorIs(t, err, errs.ErrBackupPassphrase)
+
+		opened, err := openBackup(backup, "secret passphrase")
+		require.NoError(t, err)
+		require.Equal(t, cfg, opened)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		backup, err := newBackup(cfg, metadata, "")
+		require.NoError(t, err)
+
+		backup.Config.App.SerialNumber = "CPE-0002"
+		_, err = openBackup(backup, "")
+		require.ErrorIs(t, err, errs.ErrBackupChecksumMismatch)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		backup, err := newBackup(cfg, metadata, "")
+		require.NoError(t, err)
+
+		backup.FormatVersion++
+		_, err = openBackup(backup, "")
+		require.ErrorIs(t, err, errs.ErrUnsupportedBackupVersion)
+	})
+}
+
+func TestHandler_prepareImport(t *testing.T) {
+	var (
+		handler   = &Handler{deviceType: constants.DeviceTypeCPE}
+		backupCfg = testBackupConfig()
+	)
+
+	backup, err := newBackup(backupCfg, entities.ConfigBackupMetadata{
+		SerialNumber: backupCfg.App.SerialNumber,
+		DeviceType:   constants.DeviceTypeCPE,
+	}, "")
+	require.NoError(t, err)
+
+	redactedBackup := backup
+	redactedBackup.Metadata.Redacted = true
+
+	maskedCfg := testBackupConfig()
+	maskedCfg.Wireguard.Configs = []config.WgConfig{{Interface: config.WgInterface{PrivateKey: redact.Mask}}}
+	maskedBackup, err := newBackup(maskedCfg, entities.ConfigBackupMetadata{
+		SerialNumber: backupCfg.App.SerialNumber,
+		DeviceType:   constants.DeviceTypeCPE,
+	}, "")
+	require.NoError(t, err)
+
+	tamperedBackup := backup
+	tamperedBackup.Metadata.SerialNumber = "CPE-0002"
+
+	currentCfg := func(serialNumber string) config.Config {
+		cfg := config.EmptyConfig()
+		cfg.App.SerialNumber = serialNumber
+
+		return cfg
+	}
+
+	tests := []struct {
+		name       string
+		request    entities.ImportConfigRequest
+		currentCfg config.Config
+		wantSerial string
+		wantErr    error
+	}{
+		{
+			name:       "same device",
+			request:    entities.ImportConfigRequest{Backup: backup},
+			currentCfg: currentCfg("CPE-0001"),
+			wantSerial: "CPE-0001",
+		},
+		{
+			name:       "another device",
+			request:    entities.ImportConfigRequest{Backup: backup},
+			currentCfg: currentCfg("CPE-0002"),
+			wantErr:    errs.ErrBackupSerialMismatch,
+		},
+		{
+			name:       "redacted backup",
+			request:    entities.ImportConfigRequest{Backup: redactedBackup},
+			currentCfg: currentCfg("CPE-0001"),
+			wantErr:    errs.ErrBackupRedacted,
+		},
+		{
+			name:       "masked secrets",
+			request:    entities.ImportConfigRequest{Backup: maskedBackup},
+			currentCfg: currentCfg("CPE-0001"),
+			wantErr:    errs.ErrBackupRedacted,
+		},
+		{
+			name:       "tampered metadata",
+			request:    entities.ImportConfigRequest{Backup: tamperedBackup},
+			currentCfg: currentCfg("CPE-0001"),
+			wantErr:    errs.ErrBackupChecksumMismatch,
+		},
+		{
+			name:       "rma swap",
+			request:    entities.ImportConfigRequest{Backup: backup, SerialNumber: "CPE-0002"},
+			currentCfg: currentCfg("CPE-0002"),
+			wantSerial: "CPE-0002",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := handler.prepareImport(tt.request, tt.currentCfg)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSerial, cfg.App.SerialNumber)
+			require.Nil(t, cfg.Version)
+			require.Nil(t, cfg.AppState)
+			require.Equal(t, backupCfg.AdminState, cfg.AdminState)
+		})
+	}
+
+	t.Run("invalid rma serial number", func(t *testing.T) {
+		_, err := handler.prepareImport(entities.ImportConfigRequest{Backup: backup, SerialNumber: "CPE.>"}, currentCfg("CPE-0002"))
+		require.ErrorContains(t, err, "SerialNumber")
+	})
+}
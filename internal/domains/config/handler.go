@@ -14,6 +14,7 @@ import (
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
 )
 
 const (
@@ -38,14 +39,17 @@ type (
 		publisher       IMessagePublisher
 		appStateService IAppStateService
 		configService   IConfigService
+		deviceType      string
 	}
 )
 
-func NewHandler(publisher IMessagePublisher, appStateService IAppStateService, configService IConfigService) *Handler {
+func NewHandler(publisher IMessagePublisher, appStateService IAppStateService, configService IConfigService,
+	deviceType string) *Handler {
 	return &Handler{
 		publisher:       publisher,
 		appStateService: appStateService,
 		configService:   configService,
+		deviceType:      deviceType,
 	}
 }
 
@@ -67,7 +71,7 @@ func (h *Handler) UpdateWgPeer(request wschat.WebsocketMessage) (err error) {
 	}
 
 	log.Debug().
-		Any("configs", requestBody).
+		Any("configs", redact.Value(requestBody)).
 		Msg("UpdateWgPeer: got wireguard peer to update")
 
 	cfg, err := h.configService.GetConfig()
@@ -135,7 +139,7 @@ func (h *Handler) UpdateAllConfigs(request wschat.WebsocketMessage) (err error)
 	}
 
 	log.Debug().
-		Any("configs", requestBody).
+		Any("configs", redact.Value(requestBody)).
 		Msg("UpdateAllConfigs: got configs to update")
 
 	if err = h.publisher.PublishResponse(request, wschat.EmptyBody); err != nil {
@@ -176,35 +180,41 @@ func (h *Handler) UpdateAllConfigs(request wschat.WebsocketMessage) (err error)
 				Msg("UpdateAllConfigs: update config error")
 		}
 
-		var (
-			sErr     error
-			attempts = sendUpdateFinishedAttempts
-		)
-		for {
-			if sErr = h.sendUpdateConfigFinished(updErr); sErr == nil {
-				// successfully send
-				break
-			}
+		h.notifyFinished(constants.MethodUpdateAllConfigsFinished, updErr)
+	}()
 
-			attempts--
-			if attempts <= 0 {
-				break
-			}
-		}
+	return nil
+}
 
-		if sErr != nil {
-			log.Error().
-				Err(sErr).
-				Msg("UpdateAllConfigs: update config error")
+// notifyFinished sends result of background config update to orchestrator, reconnects if it cannot be sent.
+func (h *Handler) notifyFinished(method string, updErr error) {
+	var (
+		sErr     error
+		attempts = sendUpdateFinishedAttempts
+	)
+	for {
+		if sErr = h.sendUpdateConfigFinished(method, updErr); sErr == nil {
+			// successfully send
+			break
+		}
 
-			h.publisher.Reconnect()
+		attempts--
+		if attempts <= 0 {
+			break
 		}
-	}()
+	}
 
-	return nil
+	if sErr != nil {
+		log.Error().
+			Err(sErr).
+			Str("method", method).
+			Msg("notifyFinished: send update finished error")
+
+		h.publisher.Reconnect()
+	}
 }
 
-func (h *Handler) sendUpdateConfigFinished(updErr error) (err error) {
+func (h *Handler) sendUpdateConfigFinished(method string, updErr error) (err error) {
 	body := struct {
 		ErrorMessage string `json:"errorMessage"`
 	}{}
@@ -212,7 +222,7 @@ func (h *Handler) sendUpdateConfigFinished(updErr error) (err error) {
 		body.ErrorMessage = updErr.Error()
 	}
 
-	resp, err := h.publisher.PublishRequest(constants.MethodUpdateAllConfigsFinished, constants.OrchestratorWSID, body,
+	resp, err := h.publisher.PublishRequest(method, constants.OrchestratorWSID, body,
 		wschat.RequestOptions{
 			Timeout: lo.ToPtr(sendUpdateFinishedTimeout),
 		},
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"encoding/json"
+
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
 	"github.com/nats-io/nats.go"
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
 )
 
 type (
@@ -26,12 +29,31 @@ func NewMQHandler(configService IConfigService, appStateService IAppStateService
 	}
 }
 
-func (h *MQHandler) GetConfig(_ *nats.Msg) (resp any) {
+// GetConfig returns device config, secrets are masked if redact is requested.
+func (h *MQHandler) GetConfig(message *nats.Msg) (resp any) {
+	var request struct {
+		Redact bool `json:"redact"`
+	}
+	if len(message.Data) > 0 {
+		if err := json.Unmarshal(message.Data, &request); err != nil {
+			return mq.NewBadRequestResponse(err.Error())
+		}
+	}
+
 	cfg, err := h.configService.GetConfig()
 	if err != nil {
 		return mq.NewInternalErrorResponse(err.Error())
 	}
 
+	if request.Redact {
+		var redactedCfg config.Config
+		if err = redact.Into(cfg, &redactedCfg); err != nil {
+			return mq.NewInternalErrorResponse(err.Error())
+		}
+
+		cfg = redactedCfg
+	}
+
 	response := struct {
 		mq.Response
 
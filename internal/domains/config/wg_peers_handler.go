@@ -0,0 +1,52 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/validator"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
+)
+
+// PatchWgPeers adds, updates and removes peers of one wireguard interface in a single config update.
+// Other sections are not sent, so ports, pony and websocket connection are not touched.
+func (h *Handler) PatchWgPeers(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.publisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = fmt.Errorf("%w: %w", sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.WgPeersPatch
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("PatchWgPeers: %w", err)
+	}
+
+	if err = validator.Validator.Struct(requestBody); err != nil {
+		return fmt.Errorf("PatchWgPeers: %w", err)
+	}
+
+	log.Debug().
+		Any("patch", redact.Value(requestBody)).
+		Msg("PatchWgPeers: got wireguard peers patch")
+
+	// config is read and patched by state handler, so patch is not applied to stale config
+	if !requestBody.IsEmpty() {
+		if err = h.appStateService.Perform(entities.NewOnPatchWgPeers(requestBody)); err != nil {
+			return fmt.Errorf("PatchWgPeers: %w", err)
+		}
+	}
+
+	if err = h.publisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("PatchWgPeers: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package datausage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	IMessagePublisher interface {
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+	}
+
+	IDataUsageService interface {
+		Usage() (usage []entities.PortDataUsage, err error)
+		SetPolicies(policies []entities.DataUsagePolicy) (err error)
+	}
+
+	Handler struct {
+		messagePublisher IMessagePublisher
+		dataUsageService IDataUsageService
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, dataUsageService IDataUsageService) *Handler {
+	return &Handler{
+		messagePublisher: messagePublisher,
+		dataUsageService: dataUsageService,
+
+		validate: validator.New(),
+	}
+}
+
+// GetDataUsage returns current billing cycle data usage of ports.
+func (h *Handler) GetDataUsage(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	usage, err := h.dataUsageService.Usage()
+	if err != nil {
+		return fmt.Errorf("GetDataUsage: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, usage); err != nil {
+		return fmt.Errorf("GetDataUsage: %w", err)
+	}
+
+	return nil
+}
+
+// SetDataUsagePolicies replaces billing cycles and caps of ports.
+func (h *Handler) SetDataUsagePolicies(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.DataUsageSetPoliciesRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SetDataUsagePolicies: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SetDataUsagePolicies: %w", err)
+	}
+
+	if err = h.dataUsageService.SetPolicies(requestBody.Policies); err != nil {
+		return fmt.Errorf("SetDataUsagePolicies: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("SetDataUsagePolicies: %w", err)
+	}
+
+	return nil
+}
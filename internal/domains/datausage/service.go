@@ -0,0 +1,499 @@
+package datausage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/netstats"
+)
+
+const (
+	DefaultInterval   = time.Minute
+	BootIDPath        = "/proc/sys/kernel/random/boot_id"
+	defaultBillingDay = 1
+	portTypeWAN       = "wan"
+	eventSource       = "data_usage"
+)
+
+type (
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	IAppStateService interface {
+		Perform(transition common.IStateTransition) (err error)
+	}
+
+	IEventService interface {
+		Report(event entities.AgentEvent)
+	}
+
+	ILTEMonitor interface {
+		LastStats() entities.LTEStats
+	}
+
+	IStore interface {
+		Policies() (policies []entities.DataUsagePolicy, err error)
+		SavePolicies(policies []entities.DataUsagePolicy) (err error)
+		Records() (records []UsageRecord, err error)
+		SaveRecord(record UsageRecord) (err error)
+		DeleteRecord(portName string) (err error)
+	}
+
+	// Service accounts bytes sent and received by LTE ports (and ports with policy) per billing cycle
+	// and enforces soft and hard caps.
+	Service struct {
+		configService   IConfigService
+		appStateService IAppStateService
+		eventService    IEventService
+		lteMonitor      ILTEMonitor
+		store           IStore
+		sysClassNetPath string
+		bootIDPath      string
+		interval        time.Duration
+
+		collectMx sync.Mutex
+	}
+
+	capEventData struct {
+		PortName   string    `json:"portName"`
+		CycleStart time.Time `json:"cycleStart"`
+		TotalBytes uint64    `json:"totalBytes"`
+		CapBytes   uint64    `json:"capBytes"`
+	}
+)
+
+func NewService(configService IConfigService, appStateService IAppStateService, eventService IEventService,
+	lteMonitor ILTEMonitor, store IStore, sysClassNetPath, bootIDPath string, interval time.Duration) *Service {
+	return &Service{
+		configService:   configService,
+		appStateService: appStateService,
+		eventService:    eventService,
+		lteMonitor:      lteMonitor,
+		store:           store,
+		sysClassNetPath: sysClassNetPath,
+		bootIDPath:      bootIDPath,
+		interval:        interval,
+	}
+}
+
+// Start starts periodic usage accounting.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Collect(time.Now()); err != nil {
+				log.Error().Err(err).Msg("Start: collect data usage error")
+			}
+		}
+	}
+}
+
+// Usage returns current billing cycle usage of all accounted ports.
+func (s *Service) Usage() (usage []entities.PortDataUsage, err error) {
+	records, err := s.store.Records()
+	if err != nil {
+		return nil, fmt.Errorf("Usage: %w", err)
+	}
+
+	usage = lo.Map(records, func(record UsageRecord, _ int) entities.PortDataUsage {
+		return record.PortDataUsage
+	})
+
+	return usage, nil
+}
+
+// SetPolicies replaces data usage policies, caps are checked on the next collect.
+func (s *Service) SetPolicies(policies []entities.DataUsagePolicy) (err error) {
+	if err = s.store.SavePolicies(policies); err != nil {
+		return fmt.Errorf("SetPolicies: %w", err)
+	}
+
+	return nil
+}
+
+// Collect accounts usage of ports since the previous collect and checks caps.
+func (s *Service) Collect(now time.Time) (err error) {
+	s.collectMx.Lock()
+	defer s.collectMx.Unlock()
+
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("Collect: %w", err)
+	}
+
+	policies, err := s.store.Policies()
+	if err != nil {
+		return fmt.Errorf("Collect: %w", err)
+	}
+
+	records, err := s.store.Records()
+	if err != nil {
+		return fmt.Errorf("Collect: %w", err)
+	}
+
+	recordsByPort := lo.SliceToMap(records, func(record UsageRecord) (string, UsageRecord) {
+		return record.PortName, record
+	})
+	policiesByPort := lo.SliceToMap(policies, func(policy entities.DataUsagePolicy) (string, entities.DataUsagePolicy) {
+		return policy.PortName, policy
+	})
+
+	bootID := s.readBootID()
+
+	var (
+		portsDown []string
+		portsUp   []string
+		ports     = accountedPorts(cfg, policies)
+		netdevs   = s.resolveNetdevs(ltePorts(cfg), ports)
+	)
+	for _, portName := range ports {
+		netdev, resolved := netdevs[portName]
+		if !resolved {
+			log.Debug().Str("port", portName).Msg("Collect: port network interface not found")
+			continue
+		}
+
+		rx, tx, err := s.readCounters(netdev)
+		if err != nil {
+			log.Debug().Err(err).Str("port", portName).Str("netdev", netdev).Msg("Collect: read port counters error")
+			continue
+		}
+
+		record, exists := recordsByPort[portName]
+		policy, hasPolicy := policiesByPort[portName]
+		billingDay := defaultBillingDay
+		if hasPolicy {
+			billingDay = policy.BillingDay
+			record.Policy = &policy
+		} else {
+			record.Policy = nil
+		}
+
+		cycleStart := billingCycleStart(now, billingDay)
+		if !record.CycleStart.Equal(cycleStart) {
+			// port disabled in the previous cycle is restored below
+			record.PortDataUsage = entities.PortDataUsage{
+				PortName:      portName,
+				CycleStart:    cycleStart,
+				Policy:        record.Policy,
+				DisabledByCap: record.DisabledByCap,
+			}
+		}
+
+		if exists {
+			// counters of another interface (e.g. modem re-enumerated) start from its own zero
+			reset := record.BootID != bootID || (record.Netdev != "" && record.Netdev != netdev)
+			record.RxBytes += counterDelta(record.LastRx, rx, reset)
+			record.TxBytes += counterDelta(record.LastTx, tx, reset)
+		}
+		record.LastRx, record.LastTx, record.BootID, record.Netdev = rx, tx, bootID, netdev
+		record.UpdatedAt = now
+
+		if hasPolicy && s.checkCaps(&record, policy) {
+			portsDown = append(portsDown, portName)
+		}
+
+		// new billing cycle or hard cap removed
+		if record.DisabledByCap && !record.HardCapReached {
+			portsUp = append(portsUp, portName)
+		}
+
+		if err = s.store.SaveRecord(record); err != nil {
+			return fmt.Errorf("Collect: %w", err)
+		}
+	}
+
+	// forget ports which are not LTE anymore and have no policy
+	for _, record := range records {
+		if !lo.Contains(ports, record.PortName) && !record.DisabledByCap {
+			if err = s.store.DeleteRecord(record.PortName); err != nil {
+				return fmt.Errorf("Collect: %w", err)
+			}
+		}
+	}
+
+	if len(portsDown) == 0 && len(portsUp) == 0 {
+		return nil
+	}
+
+	if err = s.updateAdminState(cfg, portsDown, portsUp); err != nil {
+		return fmt.Errorf("Collect: %w", err)
+	}
+
+	return nil
+}
+
+// checkCaps checks caps of record, returns true if port should be put admin down.
+func (s *Service) checkCaps(record *UsageRecord, policy entities.DataUsagePolicy) bool {
+	var (
+		total = record.TotalBytes()
+		data  = capEventData{
+			PortName:   record.PortName,
+			CycleStart: record.CycleStart,
+			TotalBytes: total,
+		}
+	)
+
+	if policy.SoftCapBytes > 0 && total >= policy.SoftCapBytes && !record.SoftCapReached {
+		record.SoftCapReached = true
+
+		data.CapBytes = policy.SoftCapBytes
+		s.report(entities.EventSeverityWarning, "data_usage_soft_cap",
+			fmt.Sprintf("port %s reached data usage soft cap", record.PortName), data)
+	}
+
+	if policy.HardCapBytes > 0 && total >= policy.HardCapBytes && !record.HardCapReached {
+		record.HardCapReached = true
+		return true
+	}
+
+	return false
+}
+
+// updateAdminState marks ports admin down (except the last remaining uplink) and restores ports disabled
+// in the previous billing cycle.
+func (s *Service) updateAdminState(cfg config.Config, portsDown, portsUp []string) (err error) {
+	section := new(config.AdminStateSection)
+	if cfg.AdminState != nil {
+		section.AdminStatePorts = append(section.AdminStatePorts, cfg.AdminState.AdminStatePorts...)
+	}
+
+	setDown := func(portName string, isDown bool) {
+		for i := range section.AdminStatePorts {
+			if section.AdminStatePorts[i].PortName == portName {
+				section.AdminStatePorts[i].IsDown = isDown
+				return
+			}
+		}
+
+		section.AdminStatePorts = append(section.AdminStatePorts, config.AdminStatePort{PortName: portName, IsDown: isDown})
+	}
+
+	records, err := s.store.Records()
+	if err != nil {
+		return fmt.Errorf("updateAdminState: %w", err)
+	}
+
+	recordsByPort := lo.SliceToMap(records, func(record UsageRecord) (string, UsageRecord) {
+		return record.PortName, record
+	})
+
+	var changed []UsageRecord
+	for _, portName := range portsUp {
+		setDown(portName, false)
+
+		record := recordsByPort[portName]
+		record.DisabledByCap = false
+		changed = append(changed, record)
+	}
+
+	for _, portName := range portsDown {
+		record := recordsByPort[portName]
+		data := capEventData{
+			PortName:   portName,
+			CycleStart: record.CycleStart,
+			TotalBytes: record.TotalBytes(),
+			CapBytes:   record.Policy.HardCapBytes,
+		}
+
+		if len(activeUplinks(cfg.Port, section, portName)) == 0 {
+			s.report(entities.EventSeverityError, "data_usage_hard_cap",
+				fmt.Sprintf("port %s reached data usage hard cap, port is the last uplink and stays up", portName), data)
+			continue
+		}
+
+		setDown(portName, true)
+		record.DisabledByCap = true
+		changed = append(changed, record)
+
+		s.report(entities.EventSeverityError, "data_usage_hard_cap",
+			fmt.Sprintf("port %s reached data usage hard cap and is marked admin down", portName), data)
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err = s.appStateService.Perform(entities.NewOnUpdateConfig(config.Config{AdminState: section})); err != nil {
+		// hard cap is checked again on the next collect
+		for _, portName := range portsDown {
+			record := recordsByPort[portName]
+			record.HardCapReached = false
+			if sErr := s.store.SaveRecord(record); sErr != nil {
+				log.Error().Err(sErr).Msg("updateAdminState: save record error")
+			}
+		}
+
+		return fmt.Errorf("updateAdminState: %w", err)
+	}
+
+	for _, record := range changed {
+		if err = s.store.SaveRecord(record); err != nil {
+			return fmt.Errorf("updateAdminState: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) report(severity, eventType, message string, data capEventData) {
+	s.eventService.Report(entities.AgentEvent{
+		Source:   eventSource,
+		Type:     eventType,
+		Severity: severity,
+		Message:  message,
+		Data:     data,
+	})
+}
+
+func (s *Service) readCounters(portName string) (rx, tx uint64, err error) {
+	if rx, err = netstats.ReadCounter(s.sysClassNetPath, portName, netstats.CounterRxBytes); err != nil {
+		return 0, 0, fmt.Errorf("readCounters: %w", err)
+	}
+
+	if tx, err = netstats.ReadCounter(s.sysClassNetPath, portName, netstats.CounterTxBytes); err != nil {
+		return 0, 0, fmt.Errorf("readCounters: %w", err)
+	}
+
+	return rx, tx, nil
+}
+
+// resolveNetdevs maps accounted ports to network interfaces to read counters from. Name of LTE port in config
+// is not always the name of modem network interface (e.g. modem is enumerated as wwan1), such port is resolved
+// to data interface of the only modem which is not used by other ports.
+func (s *Service) resolveNetdevs(ltePorts, ports []string) map[string]string {
+	var (
+		netdevs    = make(map[string]string, len(ports))
+		unresolved []string
+	)
+	for _, portName := range ports {
+		if s.netdevExists(portName) {
+			netdevs[portName] = portName
+			continue
+		}
+
+		if lo.Contains(ltePorts, portName) {
+			unresolved = append(unresolved, portName)
+		}
+	}
+
+	if len(unresolved) == 0 {
+		return netdevs
+	}
+
+	used := lo.Values(netdevs)
+	candidates := lo.Uniq(lo.FilterMap(s.lteMonitor.LastStats(), func(stat entities.LTEStat, _ int) (string, bool) {
+		return stat.Port, stat.Port != "" && !lo.Contains(used, stat.Port) && s.netdevExists(stat.Port)
+	}))
+	if len(unresolved) != 1 || len(candidates) != 1 {
+		log.Debug().
+			Strs("ports", unresolved).
+			Strs("modemInterfaces", candidates).
+			Msg("resolveNetdevs: LTE ports network interfaces are ambiguous")
+		return netdevs
+	}
+
+	netdevs[unresolved[0]] = candidates[0]
+
+	return netdevs
+}
+
+func (s *Service) netdevExists(name string) bool {
+	_, err := os.Stat(filepath.Join(s.sysClassNetPath, name))
+	return err == nil
+}
+
+func (s *Service) readBootID() string {
+	data, err := os.ReadFile(s.bootIDPath)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// counterDelta returns bytes counted since the last sample, counters start from zero after reboot
+// or interface recreation.
+func counterDelta(last, current uint64, rebooted bool) uint64 {
+	if rebooted || current < last {
+		return current
+	}
+
+	return current - last
+}
+
+// billingCycleStart returns start of billing cycle containing now.
+func billingCycleStart(now time.Time, billingDay int) time.Time {
+	start := time.Date(now.Year(), now.Month(), billingDay, 0, 0, 0, 0, now.Location())
+	if now.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+
+	return start
+}
+
+// accountedPorts returns LTE ports and ports with policy.
+func accountedPorts(cfg config.Config, policies []entities.DataUsagePolicy) []string {
+	ports := ltePorts(cfg)
+	for _, policy := range policies {
+		ports = append(ports, policy.PortName)
+	}
+
+	return lo.Uniq(ports)
+}
+
+func ltePorts(cfg config.Config) []string {
+	if cfg.Port == nil {
+		return nil
+	}
+
+	var ports []string
+	for _, portConfig := range cfg.Port.PortConfigs {
+		if portConfig.LTE != nil {
+			ports = append(ports, portConfig.Name)
+		}
+	}
+
+	return ports
+}
+
+// activeUplinks returns wan ports which are not admin down, except specified port.
+func activeUplinks(portSection *config.PortSection, adminState *config.AdminStateSection, exceptPort string) []string {
+	if portSection == nil {
+		return nil
+	}
+
+	var uplinks []string
+	for _, portConfig := range portSection.PortConfigs {
+		if portConfig.Type != portTypeWAN || portConfig.Name == exceptPort {
+			continue
+		}
+
+		if lo.ContainsBy(adminState.AdminStatePorts, func(item config.AdminStatePort) bool {
+			return item.PortName == portConfig.Name && item.IsDown
+		}) {
+			continue
+		}
+
+		uplinks = append(uplinks, portConfig.Name)
+	}
+
+	return uplinks
+}
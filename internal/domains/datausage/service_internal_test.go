@@ -0,0 +1,36 @@
+package datausage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_billingCycleStart(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		day  int
+		want time.Time
+	}{
+		{
+			now:  time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC),
+			day:  5,
+			want: time.Date(2024, 12, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			now:  time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+			day:  5,
+			want: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			now:  time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC),
+			day:  28,
+			want: time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, billingCycleStart(tt.now, tt.day))
+	}
+}
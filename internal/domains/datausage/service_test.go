@@ -0,0 +1,195 @@
+package datausage_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/datausage"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/datausage/datausage_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type serviceFields struct {
+	configService   *datausage_mocks.MockIConfigService
+	appStateService *datausage_mocks.MockIAppStateService
+	eventService    *datausage_mocks.MockIEventService
+	lteMonitor      *datausage_mocks.MockILTEMonitor
+	store           *datausage_mocks.MockIStore
+
+	sysPath string
+	cfg     config.Config
+	records map[string]datausage.UsageRecord
+	events  []string
+}
+
+func newServiceFields(t *testing.T, cfg config.Config, policies []entities.DataUsagePolicy) *serviceFields {
+	f := &serviceFields{
+		configService:   datausage_mocks.NewMockIConfigService(t),
+		appStateService: datausage_mocks.NewMockIAppStateService(t),
+		eventService:    datausage_mocks.NewMockIEventService(t),
+		lteMonitor:      datausage_mocks.NewMockILTEMonitor(t),
+		store:           datausage_mocks.NewMockIStore(t),
+
+		sysPath: t.TempDir(),
+		cfg:     cfg,
+		records: make(map[string]datausage.UsageRecord),
+	}
+
+	f.configService.EXPECT().
+		GetConfig().
+		RunAndReturn(func() (config.Config, error) {
+			return f.cfg, nil
+		}).
+		Maybe()
+	f.appStateService.EXPECT().
+		Perform(mock.Anything).
+		RunAndReturn(func(transition common.IStateTransition) error {
+			f.cfg.AdminState = transition.(*entities.OnUpdateConfig).Config.AdminState
+			return nil
+		}).
+		Maybe()
+	f.eventService.EXPECT().
+		Report(mock.Anything).
+		Run(func(event entities.AgentEvent) {
+			f.events = append(f.events, event.Type)
+		}).
+		Maybe()
+	f.store.EXPECT().
+		Policies().
+		Return(policies, nil).
+		Maybe()
+	f.store.EXPECT().
+		Records().
+		RunAndReturn(func() (records []datausage.UsageRecord, err error) {
+			for _, record := range f.records {
+				records = append(records, record)
+			}
+
+			return records, nil
+		}).
+		Maybe()
+	f.store.EXPECT().
+		SaveRecord(mock.Anything).
+		RunAndReturn(func(record datausage.UsageRecord) error {
+			f.records[record.PortName] = record
+			return nil
+		}).
+		Maybe()
+	f.store.EXPECT().
+		DeleteRecord(mock.Anything).
+		RunAndReturn(func(portName string) error {
+			delete(f.records, portName)
+			return nil
+		}).
+		Maybe()
+
+	return f
+}
+
+func (f *serviceFields) newService() *datausage.Service {
+	return datausage.NewService(f.configService, f.appStateService, f.eventService, f.lteMonitor, f.store,
+		f.sysPath, filepath.Join(f.sysPath, "boot_id"), time.Minute)
+}
+
+func (f *serviceFields) writeCounters(t *testing.T, port string, rx, tx uint64) {
+	t.Helper()
+
+	statisticsPath := filepath.Join(f.sysPath, port, "statistics")
+	require.NoError(t, os.MkdirAll(statisticsPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(statisticsPath, "rx_bytes"), []byte(strconv.FormatUint(rx, 10)), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(statisticsPath, "tx_bytes"), []byte(strconv.FormatUint(tx, 10)), 0o600))
+}
+
+func TestService_Collect(t *testing.T) {
+	var (
+		f = newServiceFields(t, config.Config{
+			Port: &config.PortSection{
+				PortConfigs: config.PortConfigs{
+					{Name: "wwan0", Type: "wan", LTE: &config.LTEConfig{}},
+					{Name: "eth0", Type: "wan"},
+				},
+			},
+		}, []entities.DataUsagePolicy{
+			{PortName: "wwan0", BillingDay: 5, SoftCapBytes: 1000, HardCapBytes: 2000},
+		})
+		service = f.newService()
+		now     = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	)
+
+	// baseline
+	f.writeCounters(t, "wwan0", 100, 100)
+	require.NoError(t, service.Collect(now))
+	require.Zero(t, f.records["wwan0"].TotalBytes())
+	require.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), f.records["wwan0"].CycleStart)
+
+	// soft cap
+	f.writeCounters(t, "wwan0", 700, 700)
+	require.NoError(t, service.Collect(now.Add(time.Minute)))
+	require.EqualValues(t, 1200, f.records["wwan0"].TotalBytes())
+	require.Equal(t, []string{"data_usage_soft_cap"}, f.events)
+
+	// counters reset after interface recreation, hard cap: port is marked admin down
+	f.writeCounters(t, "wwan0", 500, 500)
+	require.NoError(t, service.Collect(now.Add(2*time.Minute)))
+	require.EqualValues(t, 2200, f.records["wwan0"].TotalBytes())
+	require.True(t, f.records["wwan0"].DisabledByCap)
+	require.Equal(t, config.AdminStatePorts{{PortName: "wwan0", IsDown: true}}, f.cfg.AdminState.AdminStatePorts)
+
+	// new billing cycle: port is restored
+	require.NoError(t, service.Collect(time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC)))
+	require.Zero(t, f.records["wwan0"].TotalBytes())
+	require.False(t, f.records["wwan0"].DisabledByCap)
+	require.Equal(t, config.AdminStatePorts{{PortName: "wwan0", IsDown: false}}, f.cfg.AdminState.AdminStatePorts)
+
+	// the last uplink stays up
+	f.cfg.AdminState.AdminStatePorts = append(f.cfg.AdminState.AdminStatePorts,
+		config.AdminStatePort{PortName: "eth0", IsDown: true})
+	f.writeCounters(t, "wwan0", 3000, 3000)
+	require.NoError(t, service.Collect(time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC)))
+	require.True(t, f.records["wwan0"].HardCapReached)
+	require.False(t, f.records["wwan0"].DisabledByCap)
+	require.Equal(t, "data_usage_hard_cap", f.events[len(f.events)-1])
+}
+
+func TestService_Collect_modemInterface(t *testing.T) {
+	var (
+		f = newServiceFields(t, config.Config{
+			Port: &config.PortSection{
+				PortConfigs: config.PortConfigs{
+					{Name: "lte0", Type: "wan", LTE: &config.LTEConfig{}},
+				},
+			},
+		}, nil)
+		service = f.newService()
+		now     = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		modem   = entities.LTEStat{IMEI: "1", Port: "wwan1"}
+	)
+
+	f.lteMonitor.EXPECT().
+		LastStats().
+		RunAndReturn(func() entities.LTEStats {
+			return entities.LTEStats{modem}
+		})
+
+	// LTE port is accounted by modem network interface
+	f.writeCounters(t, "wwan1", 100, 100)
+	require.NoError(t, service.Collect(now))
+	f.writeCounters(t, "wwan1", 300, 200)
+	require.NoError(t, service.Collect(now.Add(time.Minute)))
+	require.EqualValues(t, 300, f.records["lte0"].TotalBytes())
+	require.Equal(t, "wwan1", f.records["lte0"].Netdev)
+
+	// modem is re-enumerated: counters of new interface start from zero
+	modem.Port = "wwan2"
+	f.writeCounters(t, "wwan2", 50, 50)
+	require.NoError(t, service.Collect(now.Add(2*time.Minute)))
+	require.EqualValues(t, 400, f.records["lte0"].TotalBytes())
+}
@@ -0,0 +1,143 @@
+package datausage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	policiesKey    = "datausage:policies"
+	usageKeyPrefix = "datausage:usage:"
+)
+
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	// Store keeps data usage policies and per port usage in badger, so usage survives reboots.
+	Store struct {
+		db IBadgerDB
+	}
+
+	// UsageRecord port usage with the last seen interface counters.
+	UsageRecord struct {
+		entities.PortDataUsage
+
+		BootID string `json:"bootId"`
+		Netdev string `json:"netdev"` // network interface counters are read from
+		LastRx uint64 `json:"lastRx"`
+		LastTx uint64 `json:"lastTx"`
+	}
+)
+
+func NewStore(db IBadgerDB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Policies returns saved data usage policies.
+func (s *Store) Policies() (policies []entities.DataUsagePolicy, err error) {
+	if err = s.get(policiesKey, &policies); err != nil {
+		return nil, fmt.Errorf("Policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// SavePolicies replaces data usage policies.
+func (s *Store) SavePolicies(policies []entities.DataUsagePolicy) (err error) {
+	if err = s.set(policiesKey, policies); err != nil {
+		return fmt.Errorf("SavePolicies: %w", err)
+	}
+
+	return nil
+}
+
+// Records returns usage records of all ports.
+func (s *Store) Records() (records []UsageRecord, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(usageKeyPrefix)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var record UsageRecord
+			if err := it.Item().Value(func(value []byte) error {
+				return json.Unmarshal(value, &record)
+			}); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("Records: %w", err)
+	}
+
+	return records, nil
+}
+
+// SaveRecord saves port usage record.
+func (s *Store) SaveRecord(record UsageRecord) (err error) {
+	if err = s.set(usageKeyPrefix+record.PortName, record); err != nil {
+		return fmt.Errorf("SaveRecord: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRecord deletes usage record of port.
+func (s *Store) DeleteRecord(portName string) (err error) {
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(usageKeyPrefix + portName))
+	}); err != nil {
+		return fmt.Errorf("DeleteRecord: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) get(key string, value any) (err error) {
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(data []byte) error {
+			return json.Unmarshal(data, value)
+		})
+	})
+	if err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return fmt.Errorf("get: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) set(key string, value any) (err error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	}); err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+
+	return nil
+}
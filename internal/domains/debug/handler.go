@@ -0,0 +1,125 @@
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+type (
+	IMessagePublisher interface {
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+	}
+
+	ITransferService interface {
+		Send(transferID, kind string, data []byte) (err error)
+	}
+
+	Handler struct {
+		messagePublisher IMessagePublisher
+		debugService     IDebugService
+		transferService  ITransferService
+		allowed          bool
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, debugService IDebugService, transferService ITransferService, allowed bool) *Handler {
+	return &Handler{
+		messagePublisher: messagePublisher,
+		debugService:     debugService,
+		transferService:  transferService,
+		allowed:          allowed,
+
+		validate: validator.New(),
+	}
+}
+
+// Profile starts pprof profile collection and responds with profile info at once,
+// profile data is sent with chunked transfer when collected.
+func (h *Handler) Profile(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	if !h.allowed {
+		return fmt.Errorf("Profile: %w", errs.ErrDebugNotAllowed)
+	}
+
+	var requestBody entities.ProfileRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("Profile: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("Profile: %w", err)
+	}
+
+	// response is sent before profile is collected
+	var responded sync.WaitGroup
+	responded.Add(1)
+	info, err := h.debugService.CollectProfile(requestBody, func(info entities.ProfileInfo, data []byte) {
+		go func() {
+			responded.Wait()
+			h.sendProfile(info, data)
+		}()
+	})
+	if err != nil {
+		return fmt.Errorf("Profile: %w", err)
+	}
+
+	err = h.messagePublisher.PublishResponse(request, info)
+	responded.Done()
+	if err != nil {
+		return fmt.Errorf("Profile: %w", err)
+	}
+
+	return nil
+}
+
+// sendProfile sends collected profile with chunked transfer, errors are only logged.
+func (h *Handler) sendProfile(info entities.ProfileInfo, data []byte) {
+	if info.State != entities.ProfileStateReady {
+		return
+	}
+
+	if err := h.transferService.Send(info.ProfileID, "profile_"+info.Kind, data); err != nil {
+		log.Error().Err(err).Str("profile id", info.ProfileID).Msg("sendProfile: send profile error")
+	}
+}
+
+// GetRuntimeStats returns runtime memory stats and badger stats.
+func (h *Handler) GetRuntimeStats(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	if !h.allowed {
+		return fmt.Errorf("GetRuntimeStats: %w", errs.ErrDebugNotAllowed)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, h.debugService.GetRuntimeStats()); err != nil {
+		return fmt.Errorf("GetRuntimeStats: %w", err)
+	}
+
+	return nil
+}
@@ -2,16 +2,38 @@ package debug
 
 import (
 	"bytes"
+	"encoding/json"
 	"runtime/pprof"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/go-playground/validator/v10"
 	"github.com/nats-io/nats.go"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 )
 
-type MQHandler struct{}
+type (
+	IDebugService interface {
+		CollectProfile(request entities.ProfileRequest, done func(info entities.ProfileInfo, data []byte)) (
+			info entities.ProfileInfo, err error)
+		GetProfile(profileID string) (info entities.ProfileInfo, err error)
+		GetProfileChunk(profileID string, index int) (chunk entities.TransferChunk, err error)
+		GetRuntimeStats() (stats entities.RuntimeStats)
+	}
+
+	MQHandler struct {
+		debugService IDebugService
 
-func NewMQHandler() *MQHandler {
-	return new(MQHandler)
+		validate *validator.Validate
+	}
+)
+
+func NewMQHandler(debugService IDebugService) *MQHandler {
+	return &MQHandler{
+		debugService: debugService,
+
+		validate: validator.New(),
+	}
 }
 
 // DumpHeap dumps heap memory using pprof.
@@ -32,3 +54,102 @@ func (h *MQHandler) DumpHeap(_ *nats.Msg) (resp any) {
 
 	return response
 }
+
+// Profile starts pprof profile collection, profile state is polled with GetProfile
+// and ready profile data is fetched by chunks with GetProfileChunk.
+func (h *MQHandler) Profile(message *nats.Msg) (resp any) {
+	var request entities.ProfileRequest
+	if err := json.Unmarshal(message.Data, &request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	info, err := h.debugService.CollectProfile(request, nil)
+	if err != nil {
+		return mq.NewInternalErrorResponse(err.Error())
+	}
+
+	response := struct {
+		mq.Response
+
+		Data entities.ProfileInfo `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     info,
+	}
+
+	return response
+}
+
+// GetProfile returns state of the profile.
+func (h *MQHandler) GetProfile(message *nats.Msg) (resp any) {
+	var request entities.ProfileStatusRequest
+	if err := json.Unmarshal(message.Data, &request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	info, err := h.debugService.GetProfile(request.ProfileID)
+	if err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	response := struct {
+		mq.Response
+
+		Data entities.ProfileInfo `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     info,
+	}
+
+	return response
+}
+
+// GetProfileChunk returns chunk of collected profile.
+func (h *MQHandler) GetProfileChunk(message *nats.Msg) (resp any) {
+	var request entities.ProfileChunkRequest
+	if err := json.Unmarshal(message.Data, &request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	chunk, err := h.debugService.GetProfileChunk(request.ProfileID, request.Index)
+	if err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	response := struct {
+		mq.Response
+
+		Data entities.TransferChunk `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     chunk,
+	}
+
+	return response
+}
+
+// GetRuntimeStats returns runtime memory stats and badger stats.
+func (h *MQHandler) GetRuntimeStats(_ *nats.Msg) (resp any) {
+	response := struct {
+		mq.Response
+
+		Data entities.RuntimeStats `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     h.debugService.GetRuntimeStats(),
+	}
+
+	return response
+}
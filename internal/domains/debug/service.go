@@ -0,0 +1,358 @@
+package debug
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/transfer"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	MQChunkSize = 512 << 10 // 512KB, fits into default NATS max payload with base64 overhead
+
+	defaultCPUProfileDuration     = 30 * time.Second
+	defaultSampledProfileDuration = 10 * time.Second
+	blockProfileRate              = 10000 // sample blocking events longer than 10us
+	mutexProfileFraction          = 100
+
+	profileTTL        = 10 * time.Minute
+	maxStoredProfiles = 4
+)
+
+type (
+	IBadgerDB interface {
+		Size() (lsm, vlog int64)
+		Tables() []badger.TableInfo
+		Levels() []badger.LevelInfo
+	}
+
+	Service struct {
+		db        IBadgerDB
+		chunkSize int
+
+		// collectMx allows single time bounded profile (cpu, block, mutex) at a time, it is held by background collection
+		collectMx sync.Mutex
+
+		mx       sync.Mutex
+		profiles map[string]storedProfile
+	}
+
+	storedProfile struct {
+		info   entities.ProfileInfo
+		chunks [][]byte
+	}
+)
+
+func NewService(db IBadgerDB, chunkSize int) *Service {
+	return &Service{
+		db:        db,
+		chunkSize: chunkSize,
+		profiles:  make(map[string]storedProfile),
+	}
+}
+
+// CollectProfile collects pprof profile and stores it for chunked fetching. Time bounded profiles
+// (cpu, block, mutex) are collected in background: returned info is in collecting state, done is called
+// when profile is ready or failed. Block and mutex profiles are cumulative since the agent start,
+// their base profile is taken before sampling and passed to done as well.
+func (s *Service) CollectProfile(request entities.ProfileRequest, done func(info entities.ProfileInfo, data []byte)) (
+	info entities.ProfileInfo, err error) {
+	duration, timed := profileDuration(request)
+	if !timed {
+		data, cErr := collectSnapshot(request.Kind, request.Debug)
+		if cErr != nil {
+			return info, fmt.Errorf("CollectProfile: %w", cErr)
+		}
+
+		info = s.finish(newProfileInfo(request.Kind, 0), data, nil)
+		if done != nil {
+			done(info, data)
+		}
+
+		return info, nil
+	}
+
+	if !s.collectMx.TryLock() {
+		return info, fmt.Errorf("CollectProfile: %w", errs.ErrProfilingInProgress)
+	}
+
+	info = newProfileInfo(request.Kind, duration)
+	if request.Kind != entities.ProfileKindCPU {
+		base, bErr := collectSnapshot(request.Kind, 0)
+		if bErr != nil {
+			s.collectMx.Unlock()
+			return info, fmt.Errorf("CollectProfile: %w", bErr)
+		}
+
+		baseInfo := s.finish(newProfileInfo(request.Kind, duration), base, nil)
+		info.BaseProfileID = baseInfo.ProfileID
+		if done != nil {
+			done(baseInfo, base)
+		}
+	}
+	s.store(storedProfile{info: info})
+
+	go func() {
+		defer s.collectMx.Unlock()
+
+		data, cErr := collectTimed(request.Kind, duration)
+		finished := s.finish(info, data, cErr)
+		if done != nil {
+			done(finished, data)
+		}
+	}()
+
+	return info, nil
+}
+
+// GetProfile returns info of collected or collecting profile.
+func (s *Service) GetProfile(profileID string) (info entities.ProfileInfo, err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.cleanupExpired()
+
+	profile, ok := s.profiles[profileID]
+	if !ok {
+		return info, fmt.Errorf("GetProfile: %w", errs.ErrProfileNotFound)
+	}
+
+	return profile.info, nil
+}
+
+// GetProfileChunk returns chunk of previously collected profile.
+func (s *Service) GetProfileChunk(profileID string, index int) (chunk entities.TransferChunk, err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.cleanupExpired()
+
+	profile, ok := s.profiles[profileID]
+	if !ok {
+		return chunk, fmt.Errorf("GetProfileChunk: %w", errs.ErrProfileNotFound)
+	}
+
+	if profile.info.State != entities.ProfileStateReady {
+		return chunk, fmt.Errorf("GetProfileChunk: %w: %s", errs.ErrProfileNotReady, profile.info.State)
+	}
+
+	if index < 0 || index >= len(profile.chunks) {
+		return chunk, fmt.Errorf("GetProfileChunk: chunk index %d is out of range [0, %d)", index, len(profile.chunks))
+	}
+
+	return entities.TransferChunk{
+		TransferID: profileID,
+		Kind:       profile.info.Kind,
+		Index:      index,
+		Total:      len(profile.chunks),
+		Size:       profile.info.Size,
+		SHA256:     profile.info.SHA256,
+		Data:       profile.chunks[index],
+	}, nil
+}
+
+// GetRuntimeStats returns go runtime memory/gc stats and badger stats.
+func (s *Service) GetRuntimeStats() (stats entities.RuntimeStats) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	stats = entities.RuntimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		CPUs:       runtime.NumCPU(),
+		Memory: entities.MemoryStats{
+			Alloc:        memStats.Alloc,
+			TotalAlloc:   memStats.TotalAlloc,
+			Sys:          memStats.Sys,
+			HeapAlloc:    memStats.HeapAlloc,
+			HeapInuse:    memStats.HeapInuse,
+			HeapIdle:     memStats.HeapIdle,
+			HeapReleased: memStats.HeapReleased,
+			HeapObjects:  memStats.HeapObjects,
+			StackInuse:   memStats.StackInuse,
+			Mallocs:      memStats.Mallocs,
+			Frees:        memStats.Frees,
+		},
+		GC: entities.GCStats{
+			NumGC:        memStats.NumGC,
+			PauseTotalNs: memStats.PauseTotalNs,
+			LastPauseNs:  memStats.PauseNs[(memStats.NumGC+255)%256],
+			NextGC:       memStats.NextGC,
+			CPUFraction:  memStats.GCCPUFraction,
+		},
+		CollectedAt: time.Now(),
+	}
+	if memStats.LastGC > 0 {
+		stats.GC.LastGC = time.Unix(0, int64(memStats.LastGC)) //nolint:gosec // unix nanoseconds fit int64
+	}
+
+	stats.Badger.LSMSize, stats.Badger.VlogSize = s.db.Size()
+	stats.Badger.Tables = len(s.db.Tables())
+	stats.Badger.Levels = make([]entities.BadgerLevelStats, 0)
+	for _, level := range s.db.Levels() {
+		stats.Badger.Levels = append(stats.Badger.Levels, entities.BadgerLevelStats{
+			Level:      level.Level,
+			Tables:     level.NumTables,
+			Size:       level.Size,
+			TargetSize: level.TargetSize,
+			Score:      level.Score,
+		})
+	}
+
+	return stats
+}
+
+// finish stores collected profile data or collection error.
+func (s *Service) finish(info entities.ProfileInfo, data []byte, collectErr error) entities.ProfileInfo {
+	info.ExpiresAt = time.Now().Add(profileTTL)
+	if collectErr != nil {
+		info.State = entities.ProfileStateFailed
+		info.Error = collectErr.Error()
+		s.store(storedProfile{info: info})
+
+		log.Error().
+			Err(collectErr).
+			Str("profile id", info.ProfileID).
+			Msg("finish: collect profile error")
+
+		return info
+	}
+
+	checksum := sha256.Sum256(data)
+	chunks := transfer.SplitChunks(data, s.chunkSize)
+	info.State = entities.ProfileStateReady
+	info.Size = len(data)
+	info.SHA256 = hex.EncodeToString(checksum[:])
+	info.Chunks = len(chunks)
+
+	s.store(storedProfile{
+		info:   info,
+		chunks: chunks,
+	})
+
+	log.Debug().
+		Any("profile", info).
+		Msg("finish: profile collected")
+
+	return info
+}
+
+func newProfileInfo(kind string, duration time.Duration) entities.ProfileInfo {
+	return entities.ProfileInfo{
+		ProfileID: uuid.New().String(),
+		Kind:      kind,
+		State:     entities.ProfileStateCollecting,
+		ExpiresAt: time.Now().Add(duration + profileTTL),
+	}
+}
+
+// profileDuration returns duration of time bounded profile.
+func profileDuration(request entities.ProfileRequest) (duration time.Duration, timed bool) {
+	switch request.Kind {
+	case entities.ProfileKindCPU:
+		return durationOrDefault(request.DurationSeconds, defaultCPUProfileDuration), true
+	case entities.ProfileKindBlock, entities.ProfileKindMutex:
+		return durationOrDefault(request.DurationSeconds, defaultSampledProfileDuration), true
+	default:
+		return 0, false
+	}
+}
+
+// collectSnapshot writes current state of the profile.
+func collectSnapshot(kind string, debug int) (data []byte, err error) {
+	profile := pprof.Lookup(kind)
+	if profile == nil {
+		return nil, fmt.Errorf("collectSnapshot: unknown profile %s", kind)
+	}
+
+	var buf bytes.Buffer
+	if err = profile.WriteTo(&buf, debug); err != nil {
+		return nil, fmt.Errorf("collectSnapshot: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// collectTimed collects cpu profile or enables block/mutex sampling for duration.
+func collectTimed(kind string, duration time.Duration) (data []byte, err error) {
+	var buf bytes.Buffer
+	switch kind {
+	case entities.ProfileKindCPU:
+		if err = pprof.StartCPUProfile(&buf); err != nil {
+			return nil, fmt.Errorf("collectTimed: %w", err)
+		}
+
+		time.Sleep(duration)
+		pprof.StopCPUProfile()
+
+		return buf.Bytes(), nil
+
+	case entities.ProfileKindBlock:
+		runtime.SetBlockProfileRate(blockProfileRate)
+		time.Sleep(duration)
+		runtime.SetBlockProfileRate(0)
+
+	case entities.ProfileKindMutex:
+		prevFraction := runtime.SetMutexProfileFraction(mutexProfileFraction)
+		time.Sleep(duration)
+		runtime.SetMutexProfileFraction(prevFraction)
+
+	default:
+		return nil, fmt.Errorf("collectTimed: unknown profile %s", kind)
+	}
+
+	if data, err = collectSnapshot(kind, 0); err != nil {
+		return nil, fmt.Errorf("collectTimed: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s *Service) store(profile storedProfile) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.cleanupExpired()
+
+	// evict oldest profiles
+	for len(s.profiles) >= maxStoredProfiles {
+		var oldest storedProfile
+		for _, stored := range s.profiles {
+			if oldest.info.ProfileID == "" || stored.info.ExpiresAt.Before(oldest.info.ExpiresAt) {
+				oldest = stored
+			}
+		}
+
+		delete(s.profiles, oldest.info.ProfileID)
+	}
+
+	s.profiles[profile.info.ProfileID] = profile
+}
+
+func (s *Service) cleanupExpired() {
+	now := time.Now()
+	for id, profile := range s.profiles {
+		if now.After(profile.info.ExpiresAt) {
+			delete(s.profiles, id)
+		}
+	}
+}
+
+func durationOrDefault(seconds int, defaultDuration time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return defaultDuration
+}
@@ -0,0 +1,55 @@
+package debug_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/debug"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+func TestService_CollectProfile(t *testing.T) {
+	service := debug.NewService(nil, debug.MQChunkSize)
+
+	// snapshot profile is ready at once
+	heap, err := service.CollectProfile(entities.ProfileRequest{Kind: entities.ProfileKindHeap}, nil)
+	require.NoError(t, err)
+	require.Equal(t, entities.ProfileStateReady, heap.State)
+	_, err = service.GetProfileChunk(heap.ProfileID, 0)
+	require.NoError(t, err)
+
+	// time bounded profile is collected in background
+	collected := make(chan entities.ProfileInfo, 2)
+	block, err := service.CollectProfile(entities.ProfileRequest{Kind: entities.ProfileKindBlock, DurationSeconds: 1},
+		func(info entities.ProfileInfo, _ []byte) {
+			collected <- info
+		})
+	require.NoError(t, err)
+	require.Equal(t, entities.ProfileStateCollecting, block.State)
+	require.NotEmpty(t, block.BaseProfileID)
+
+	_, err = service.GetProfileChunk(block.ProfileID, 0)
+	require.ErrorIs(t, err, errs.ErrProfileNotReady)
+
+	_, err = service.CollectProfile(entities.ProfileRequest{Kind: entities.ProfileKindCPU, DurationSeconds: 1}, nil)
+	require.ErrorIs(t, err, errs.ErrProfilingInProgress)
+
+	// base profile is passed first, then sampled one
+	require.Equal(t, block.BaseProfileID, (<-collected).ProfileID)
+	select {
+	case info := <-collected:
+		require.Equal(t, block.ProfileID, info.ProfileID)
+		require.Equal(t, entities.ProfileStateReady, info.State)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "profile is not collected")
+	}
+
+	info, err := service.GetProfile(block.ProfileID)
+	require.NoError(t, err)
+	require.Equal(t, entities.ProfileStateReady, info.State)
+	_, err = service.GetProfileChunk(block.ProfileID, 0)
+	require.NoError(t, err)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/redact"
 )
 
 type (
@@ -55,7 +56,7 @@ func (h *Handler) InitDevice(request wschat.WebsocketMessage) (err error) {
 	}
 
 	log.Debug().
-		Any("configs", initConfig).
+		Any("configs", redact.Value(initConfig)).
 		Msg("got init config")
 
 	activeState := h.appStateService.ActiveState()
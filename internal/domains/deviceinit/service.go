@@ -16,6 +16,7 @@ import (
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
 	"github.com/rs/zerolog/log"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/configcheck"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 )
@@ -195,6 +196,12 @@ func (s *Service) InitDevice(initConfig entities.InitConfig) (err error) {
 		return fmt.Errorf("InitDevice: %w", err)
 	}
 
+	// init config is checked as a whole before anything is applied
+	rulesCfg, servicesCfg := initSections(initConfig)
+	if err = configcheck.Validate(oldConfig, configcheck.Merge(rulesCfg, servicesCfg)); err != nil {
+		return fmt.Errorf("InitDevice: %w", err)
+	}
+
 	var (
 		app            = *oldConfig.App
 		deviceSerial   = app.SerialNumber
@@ -220,32 +227,7 @@ func (s *Service) InitDevice(initConfig entities.InitConfig) (err error) {
 	}
 
 	// install rules
-	if err = s.configService.UpdateConfigWithTx(
-		ctx, tx,
-		config.Config{
-			Wireguard: &config.WireguardSection{
-				Configs: initConfig.Wireguard,
-			},
-			Port: &config.PortSection{
-				PortConfigs: initConfig.NetInit.PortConfigs,
-				PortMTUs:    initConfig.NetInit.PortMTUs,
-			},
-			WANProtection: &config.WANProtectionSection{
-				PortNames:    initConfig.NetInit.PortNames,
-				AllowedPorts: initConfig.NetInit.AllowedPorts,
-			},
-			Loopback: &config.LoopbackSection{
-				Addresses: initConfig.NetInit.LoopbackAddresses,
-			},
-			IPRule: &config.IPRuleSection{
-				IPRules: initConfig.NetInit.IPRules,
-			},
-			Pony: &initConfig.Pony,
-			AdminState: &config.AdminStateSection{
-				AdminStatePorts: initConfig.NetInit.AdminStatePorts,
-			},
-		},
-	); err != nil {
+	if err = s.configService.UpdateConfigWithTx(ctx, tx, rulesCfg); err != nil {
 		return fmt.Errorf("InitDevice: %w", err)
 	}
 
@@ -268,22 +250,52 @@ func (s *Service) InitDevice(initConfig entities.InitConfig) (err error) {
 	}
 
 	// install services
-	if err = s.configService.UpdateConfigWithTx(
-		ctx, tx,
-		config.Config{
-			Trunk:  &initConfig.Services.Trunk,
-			L3:     &initConfig.Services.L3,
-			ISB:    &initConfig.Services.ISB,
-			Bridge: &initConfig.Services.Bridge,
-			P2P:    &initConfig.Services.P2P,
-			FW:     &initConfig.Services.FW,
-		}); err != nil {
+	if err = s.configService.UpdateConfigWithTx(ctx, tx, servicesCfg); err != nil {
 		return fmt.Errorf("InitDevice: %w", err)
 	}
 
 	return nil
 }
 
+// initSections returns config sections of init config: rules applied first and services applied
+// after connection checks.
+func initSections(initConfig entities.InitConfig) (rulesCfg, servicesCfg config.Config) {
+	rulesCfg = config.Config{
+		Wireguard: &config.WireguardSection{
+			Configs: initConfig.Wireguard,
+		},
+		Port: &config.PortSection{
+			PortConfigs: initConfig.NetInit.PortConfigs,
+			PortMTUs:    initConfig.NetInit.PortMTUs,
+		},
+		WANProtection: &config.WANProtectionSection{
+			PortNames:    initConfig.NetInit.PortNames,
+			AllowedPorts: initConfig.NetInit.AllowedPorts,
+		},
+		Loopback: &config.LoopbackSection{
+			Addresses: initConfig.NetInit.LoopbackAddresses,
+		},
+		IPRule: &config.IPRuleSection{
+			IPRules: initConfig.NetInit.IPRules,
+		},
+		Pony: &initConfig.Pony,
+		AdminState: &config.AdminStateSection{
+			AdminStatePorts: initConfig.NetInit.AdminStatePorts,
+		},
+	}
+
+	servicesCfg = config.Config{
+		Trunk:  &initConfig.Services.Trunk,
+		L3:     &initConfig.Services.L3,
+		ISB:    &initConfig.Services.ISB,
+		Bridge: &initConfig.Services.Bridge,
+		P2P:    &initConfig.Services.P2P,
+		FW:     &initConfig.Services.FW,
+	}
+
+	return rulesCfg, servicesCfg
+}
+
 func (s *Service) checkHubTunnels(ponyCfg config.PonySection) (err error) {
 	if len(ponyCfg.Clusters) == 0 {
 		return nil
@@ -9,13 +9,10 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/environment"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
 )
 
-const (
-	monitoringInterval = time.Second * 20
-)
-
 type (
 	IMessagePublisher interface {
 		IsActive() bool
@@ -30,24 +27,32 @@ type (
 		FetchPrimary(hosts []string) (primary string, err error)
 		GetHosts() []string
 	}
+
+	ISettings interface {
+		Tunables() environment.Tunables
+	}
 )
 
 type Service struct {
 	messagePublisher IMessagePublisher
 	configService    IConfigService
 	discoveryService IDiscoveryService
+	settings         ISettings
 }
 
-func NewService(messagePublisher IMessagePublisher, configService IConfigService, discoveryService IDiscoveryService) *Service {
+func NewService(messagePublisher IMessagePublisher, configService IConfigService, discoveryService IDiscoveryService,
+	settings ISettings) *Service {
 	return &Service{
 		messagePublisher: messagePublisher,
 		configService:    configService,
 		discoveryService: discoveryService,
+		settings:         settings,
 	}
 }
 
 func (s *Service) Start(ctx context.Context) {
-	ticker := time.NewTicker(monitoringInterval)
+	interval := s.settings.Tunables().DiscoveryInterval
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -55,6 +60,12 @@ func (s *Service) Start(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// interval is changed by settings reload
+			if reloaded := s.settings.Tunables().DiscoveryInterval; reloaded != interval {
+				interval = reloaded
+				ticker.Reset(interval)
+			}
+
 			cfg, err := s.configService.GetConfig()
 			if err != nil {
 				log.Error().Err(err).Msg("StartMonitoring: read config error")
@@ -1,14 +1,17 @@
 package discovery
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 	"github.com/sourcegraph/conc/pool"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
 )
 
@@ -22,13 +25,35 @@ type Service struct {
 	httpClientService IHTTPClientService
 	hosts             []string
 	mx                sync.Mutex
+
+	statsMx sync.Mutex
+	stats   entities.DiscoveryStats
 }
 
 func NewService(httpClientService IHTTPClientService) *Service {
 	return &Service{
 		httpClientService: httpClientService,
 		mx:                sync.Mutex{},
+
+		stats: entities.DiscoveryStats{
+			Checks: make(map[string]uint64),
+		},
+	}
+}
+
+// Stats returns results of the last primary discovery.
+func (s *Service) Stats() entities.DiscoveryStats {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	stats := s.stats
+	stats.Checks = make(map[string]uint64, len(s.stats.Checks))
+	for result, count := range s.stats.Checks {
+		stats.Checks[result] = count
 	}
+	stats.Hosts = slices.Clone(s.stats.Hosts)
+
+	return stats
 }
 
 func (s *Service) GetHosts() []string {
@@ -52,6 +77,11 @@ func (s *Service) setHosts(hosts []string) {
 func (s *Service) FetchPrimary(hosts []string) (primary string, err error) {
 	s.setHosts(hosts)
 
+	var results []*discoveryResult
+	defer func() {
+		s.recordResults(results, primary, err)
+	}()
+
 	p := pool.NewWithResults[*discoveryResult]().WithMaxGoroutines(len(hosts))
 	for _, host := range hosts {
 		p.Go(func() *discoveryResult {
@@ -70,7 +100,7 @@ func (s *Service) FetchPrimary(hosts []string) (primary string, err error) {
 		})
 	}
 
-	results := p.Wait()
+	results = p.Wait()
 	for _, result := range results {
 		if result.Err != nil {
 			log.Warn().
@@ -94,3 +124,30 @@ func (s *Service) FetchPrimary(hosts []string) (primary string, err error) {
 
 	return primary, nil
 }
+
+func (s *Service) recordResults(results []*discoveryResult, primary string, err error) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	checkResult := "ok"
+	switch {
+	case errors.Is(err, errs.ErrSplitBrain):
+		checkResult = "split_brain"
+	case errors.Is(err, errs.ErrPrimaryNotFound):
+		checkResult = "primary_not_found"
+	case err != nil:
+		checkResult = "error"
+	}
+
+	s.stats.Checks[checkResult]++
+	s.stats.CheckedAt = time.Now()
+	s.stats.Primary = primary
+	s.stats.Hosts = make([]entities.DiscoveryHostStat, 0, len(results))
+	for _, result := range results {
+		s.stats.Hosts = append(s.stats.Hosts, entities.DiscoveryHostStat{
+			Host:      result.Host,
+			IsPrimary: result.IsPrimary,
+			Failed:    result.Err != nil,
+		})
+	}
+}
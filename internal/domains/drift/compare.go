@@ -0,0 +1,160 @@
+package drift
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const portTypeWAN = "wan"
+
+// comparePortAddresses checks static addresses of wan ports which are not administratively down.
+func comparePortAddresses(cfg config.Config, addresses map[string][]string) (items []entities.DriftItem) {
+	if cfg.Port == nil {
+		return nil
+	}
+
+	for _, port := range activeWANPorts(cfg) {
+		if port.Wan.Mode != "static" {
+			continue
+		}
+
+		expected := toCIDR(port.Wan.IPAddr, port.Wan.SubnetMask)
+		if !slices.Contains(addresses[port.Name], expected) {
+			items = append(items, newItem(entities.DriftSectionPort, entities.DriftKindMissing, port.Name, expected))
+		}
+	}
+
+	return items
+}
+
+// compareIPRules checks intended ip rules are present, rules added by other components are not reported.
+func compareIPRules(cfg config.Config, existingRules map[string]bool) (items []entities.DriftItem) {
+	if cfg.IPRule == nil {
+		return nil
+	}
+
+	for _, rule := range cfg.IPRule.IPRules {
+		if command := rule.ToCommand(); !existingRules[command] {
+			items = append(items, newItem(entities.DriftSectionIPRule, entities.DriftKindMissing, rule.IPAddr, command))
+		}
+	}
+
+	return items
+}
+
+// compareLoopback checks intended loopback addresses are assigned to lo.
+func compareLoopback(cfg config.Config, addresses map[string][]string) (items []entities.DriftItem) {
+	if cfg.Loopback == nil {
+		return nil
+	}
+
+	for _, addr := range cfg.Loopback.Addresses {
+		if !slices.Contains(addresses[loopbackInterface], addr) {
+			items = append(items, newItem(entities.DriftSectionLoopback, entities.DriftKindMissing, loopbackInterface, addr))
+		}
+	}
+
+	return items
+}
+
+// compareRoutes checks default routes of wan ports in their routing tables, missing holds port tables without route.
+func compareRoutes(missing map[string][]int) (items []entities.DriftItem) {
+	for _, portName := range lo.Keys(missing) {
+		for _, table := range missing[portName] {
+			items = append(items, newItem(entities.DriftSectionRouting, entities.DriftKindMissing, portName,
+				fmt.Sprintf("default route in table %d", table)))
+		}
+	}
+
+	return items
+}
+
+// compareWireguard checks interfaces, peers and peer allowed ips, wireguard interfaces are owned by agent,
+// so unknown peers are reported too.
+func compareWireguard(cfg config.Config, interfaces map[string]wgPeers) (items []entities.DriftItem) {
+	if cfg.Wireguard == nil {
+		return nil
+	}
+
+	for _, wgCfg := range cfg.Wireguard.Configs {
+		ifaceName := wgCfg.GetInterfaceName()
+		livePeers, exists := interfaces[ifaceName]
+		if !exists {
+			items = append(items, newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, ifaceName, "interface"))
+			continue
+		}
+
+		for _, peer := range wgCfg.Peers {
+			liveAllowedIPs, exists := livePeers[peer.PublicKey]
+			if !exists {
+				items = append(items, newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, ifaceName,
+					"peer "+peer.PublicKey))
+				continue
+			}
+
+			liveAllowedIPs = lo.Map(liveAllowedIPs, func(item string, _ int) string {
+				return normalizeCIDR(item)
+			})
+			for _, allowedIP := range peer.AllowedIPs {
+				if !slices.Contains(liveAllowedIPs, normalizeCIDR(allowedIP)) {
+					items = append(items, newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, ifaceName,
+						fmt.Sprintf("peer %s allowed ip %s", peer.PublicKey, allowedIP)))
+				}
+			}
+		}
+
+		for _, publicKey := range lo.Keys(livePeers) {
+			if !lo.ContainsBy(wgCfg.Peers, func(peer config.WgPeer) bool {
+				return peer.PublicKey == publicKey
+			}) {
+				items = append(items, newItem(entities.DriftSectionWireguard, entities.DriftKindUnexpected, ifaceName,
+					"peer "+publicKey))
+			}
+		}
+	}
+
+	return items
+}
+
+// compareOVSManagers checks manager target of the last manager setup is configured in OVS.
+func compareOVSManagers(expectedTarget string, managers []string) (items []entities.DriftItem) {
+	if expectedTarget == "" || slices.Contains(managers, expectedTarget) {
+		return nil
+	}
+
+	return []entities.DriftItem{newItem(entities.DriftSectionOVS, entities.DriftKindMissing, "manager", expectedTarget)}
+}
+
+// activeWANPorts returns wan ports which are not administratively down.
+func activeWANPorts(cfg config.Config) (ports []config.PortConfig) {
+	if cfg.Port == nil {
+		return nil
+	}
+
+	var downPorts []string
+	if cfg.AdminState != nil {
+		for _, port := range cfg.AdminState.AdminStatePorts {
+			if port.IsDown {
+				downPorts = append(downPorts, port.PortName)
+			}
+		}
+	}
+
+	return lo.Filter(cfg.Port.PortConfigs, func(port config.PortConfig, _ int) bool {
+		return port.Type == portTypeWAN && port.Wan != nil && !slices.Contains(downPorts, port.Name)
+	})
+}
+
+func newItem(section, kind, object, value string) entities.DriftItem {
+	return entities.DriftItem{
+		Section: section,
+		Kind:    kind,
+		Object:  object,
+		Value:   value,
+	}
+}
@@ -0,0 +1,75 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func TestCompareWireguard(t *testing.T) {
+	cfg := config.Config{
+		Wireguard: &config.WireguardSection{
+			Configs: []config.WgConfig{
+				{
+					Interface: config.WgInterface{IsClient: true, ClusterID: 1, Table: 101},
+					Peers: []config.WgPeer{
+						{PublicKey: "peerA", AllowedIPs: []string{"10.0.0.1", "192.168.0.0/24"}},
+						{PublicKey: "peerB", AllowedIPs: []string{"10.0.0.2/32"}},
+					},
+				},
+				{
+					Interface: config.WgInterface{ClusterID: 2, Table: 102},
+				},
+			},
+		},
+	}
+
+	dump := []byte("wgc-1_1\tprivate\tpublic\t51820\toff\n" +
+		"wgc-1_1\tpeerA\t(none)\t1.1.1.1:51820\t10.0.0.1/32\t0\t0\t0\t25\n" +
+		"wgc-1_1\tpeerC\t(none)\t(none)\t(none)\t0\t0\t0\toff\n")
+
+	items := compareWireguard(cfg, parseWgDump(dump))
+	require.ElementsMatch(t, []entities.DriftItem{
+		newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, "wgc-1_1", "peer peerA allowed ip 192.168.0.0/24"),
+		newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, "wgc-1_1", "peer peerB"),
+		newItem(entities.DriftSectionWireguard, entities.DriftKindUnexpected, "wgc-1_1", "peer peerC"),
+		newItem(entities.DriftSectionWireguard, entities.DriftKindMissing, "wgs-2_2", "interface"),
+	}, items)
+}
+
+func TestComparePortAddresses(t *testing.T) {
+	cfg := config.Config{
+		Port: &config.PortSection{
+			PortConfigs: config.PortConfigs{
+				{Name: "wan1", Type: "wan", Wan: &config.WanConfig{Mode: "static", IPAddr: "1.1.1.2", SubnetMask: "255.255.255.0"}},
+				{Name: "wan2", Type: "wan", Wan: &config.WanConfig{Mode: "static", IPAddr: "2.2.2.2", SubnetMask: "255.255.255.252"}},
+				{Name: "wan3", Type: "wan", Wan: &config.WanConfig{Mode: "static", IPAddr: "3.3.3.2", SubnetMask: "255.255.255.0"}},
+				{Name: "wan4", Type: "wan", Wan: &config.WanConfig{Mode: "dhcp"}},
+			},
+		},
+		AdminState: &config.AdminStateSection{
+			AdminStatePorts: config.AdminStatePorts{{PortName: "wan3", IsDown: true}},
+		},
+		Loopback: &config.LoopbackSection{
+			Addresses: []string{"10.222.5.32/32", "10.245.5.32/32"},
+		},
+	}
+
+	addresses, err := parseAddresses([]byte(`[
+		{"ifname":"lo","addr_info":[{"family":"inet","local":"127.0.0.1","prefixlen":8},{"family":"inet","local":"10.222.5.32","prefixlen":32}]},
+		{"ifname":"wan1","addr_info":[{"family":"inet","local":"1.1.1.2","prefixlen":24}]},
+		{"ifname":"wan2","addr_info":[{"family":"inet","local":"2.2.2.2","prefixlen":24}]}
+	]`))
+	require.NoError(t, err)
+
+	require.Equal(t, []entities.DriftItem{
+		newItem(entities.DriftSectionPort, entities.DriftKindMissing, "wan2", "2.2.2.2/30"),
+	}, comparePortAddresses(cfg, addresses))
+
+	require.Equal(t, []entities.DriftItem{
+		newItem(entities.DriftSectionLoopback, entities.DriftKindMissing, "lo", "10.245.5.32/32"),
+	}, compareLoopback(cfg, addresses))
+}
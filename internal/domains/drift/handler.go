@@ -0,0 +1,116 @@
+package drift
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	IMessagePublisher interface {
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+	}
+
+	IDriftService interface {
+		Check() (report entities.DriftReport, err error)
+		Settings() (settings entities.DriftSettings, err error)
+		SetSettings(settings entities.DriftSettings) (err error)
+	}
+
+	Handler struct {
+		messagePublisher IMessagePublisher
+		driftService     IDriftService
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, driftService IDriftService) *Handler {
+	return &Handler{
+		messagePublisher: messagePublisher,
+		driftService:     driftService,
+
+		validate: validator.New(),
+	}
+}
+
+// GetConfigDrift compares config with live state and returns found differences.
+func (h *Handler) GetConfigDrift(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	report, err := h.driftService.Check()
+	if err != nil {
+		return fmt.Errorf("GetConfigDrift: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, report); err != nil {
+		return fmt.Errorf("GetConfigDrift: %w", err)
+	}
+
+	return nil
+}
+
+// GetDriftSettings returns sections configured for auto remediation.
+func (h *Handler) GetDriftSettings(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	settings, err := h.driftService.Settings()
+	if err != nil {
+		return fmt.Errorf("GetDriftSettings: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, settings); err != nil {
+		return fmt.Errorf("GetDriftSettings: %w", err)
+	}
+
+	return nil
+}
+
+// SetDriftSettings replaces sections configured for auto remediation.
+func (h *Handler) SetDriftSettings(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.SetDriftSettingsRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SetDriftSettings: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SetDriftSettings: %w", err)
+	}
+
+	if err = h.driftService.SetSettings(requestBody.Settings); err != nil {
+		return fmt.Errorf("SetDriftSettings: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, requestBody.Settings); err != nil {
+		return fmt.Errorf("SetDriftSettings: %w", err)
+	}
+
+	return nil
+}
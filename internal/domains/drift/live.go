@@ -0,0 +1,113 @@
+package drift
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	wgDumpInterfaceFields = 5
+	wgDumpPeerFields      = 9
+	wgNoAllowedIPs        = "(none)"
+)
+
+type (
+	// interfaceAddresses ip --json address output.
+	interfaceAddresses []struct {
+		IfName   string `json:"ifname"`
+		AddrInfo []struct {
+			Family    string `json:"family"`
+			Local     string `json:"local"`
+			PrefixLen int    `json:"prefixlen"`
+		} `json:"addr_info"` //nolint:tagliatelle // iproute2 API
+	}
+
+	// wgPeers live wireguard peers of interface with their allowed ips.
+	wgPeers map[string][]string
+)
+
+// parseAddresses returns addresses in cidr notation by interface name.
+func parseAddresses(output []byte) (addresses map[string][]string, err error) {
+	var interfaces interfaceAddresses
+	if err = json.Unmarshal(output, &interfaces); err != nil {
+		return nil, fmt.Errorf("parseAddresses: %w", err)
+	}
+
+	addresses = make(map[string][]string, len(interfaces))
+	for _, iface := range interfaces {
+		for _, addr := range iface.AddrInfo {
+			addresses[iface.IfName] = append(addresses[iface.IfName], fmt.Sprintf("%s/%d", addr.Local, addr.PrefixLen))
+		}
+	}
+
+	return addresses, nil
+}
+
+// parseWgDump parses `wg show all dump` output and returns peers by interface name.
+// Interfaces without peers are present with empty peers.
+func parseWgDump(output []byte) (interfaces map[string]wgPeers) {
+	interfaces = make(map[string]wgPeers)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), "\t")
+		switch len(fields) {
+		case wgDumpInterfaceFields:
+			if _, exists := interfaces[fields[0]]; !exists {
+				interfaces[fields[0]] = make(wgPeers)
+			}
+
+		case wgDumpPeerFields:
+			peers, exists := interfaces[fields[0]]
+			if !exists {
+				peers = make(wgPeers)
+				interfaces[fields[0]] = peers
+			}
+
+			var allowedIPs []string
+			if fields[4] != wgNoAllowedIPs {
+				allowedIPs = strings.Split(fields[4], ",")
+			}
+			peers[fields[1]] = allowedIPs
+		}
+	}
+
+	return interfaces
+}
+
+// toCIDR converts address and dotted subnet mask to cidr notation.
+func toCIDR(addr, mask string) string {
+	ip := net.ParseIP(mask).To4()
+	if ip == nil {
+		return addr
+	}
+
+	ones, _ := net.IPMask(ip).Size()
+	return fmt.Sprintf("%s/%d", addr, ones)
+}
+
+// normalizeCIDR returns prefix in canonical form, single address gets host prefix length.
+func normalizeCIDR(value string) string {
+	if !strings.Contains(value, "/") {
+		if ip := net.ParseIP(value); ip != nil {
+			if ip.To4() != nil {
+				return value + "/32"
+			}
+
+			return value + "/128"
+		}
+
+		return value
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return value
+	}
+
+	return network.String()
+}
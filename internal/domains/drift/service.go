@@ -0,0 +1,423 @@
+package drift
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/cmd"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/ovs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	DefaultInterval   = 5 * time.Minute
+	loopbackInterface = "lo"
+	eventSource       = "drift"
+
+	maxRemediationAttempts = 3 // re-applying section is given up for drift item after attempts, interval doubles between them
+)
+
+type (
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	ICmdService interface {
+		ApplyCommandWithOutput(cmd string) (output []byte, err error)
+		GetIPRules() (existingRules map[string]bool, err error)
+		GetPortDefaultRoute(portName string, tableID int) (route cmd.IPRoute, err error)
+	}
+
+	IOVSService interface {
+		SetupOVSManager(ofControllerAddr string) (err error)
+		ControllerAddr() (ofControllerAddr string, err error)
+		Managers() (targets []string, err error)
+	}
+
+	IAppStateService interface {
+		ActiveState() entities.AppState
+		Perform(transition common.IStateTransition) (err error)
+	}
+
+	IEventService interface {
+		Report(event entities.AgentEvent)
+	}
+
+	IStore interface {
+		Settings() (settings entities.DriftSettings, err error)
+		SaveSettings(settings entities.DriftSettings) (err error)
+	}
+
+	// Service periodically compares intended config with live kernel and daemons state,
+	// reports drift as events and re-applies sections configured for auto remediation.
+	Service struct {
+		configService   IConfigService
+		cmdService      ICmdService
+		ovsService      IOVSService
+		appStateService IAppStateService
+		eventService    IEventService
+		store           IStore
+		interval        time.Duration
+
+		checkMx      sync.Mutex
+		lastReport   entities.DriftReport
+		remediations map[entities.DriftItem]*remediation
+	}
+
+	remediation struct {
+		attempts int
+		nextAt   time.Time
+		givenUp  bool
+	}
+)
+
+func NewService(configService IConfigService, cmdService ICmdService, ovsService IOVSService,
+	appStateService IAppStateService, eventService IEventService, store IStore, interval time.Duration) *Service {
+	return &Service{
+		configService:   configService,
+		cmdService:      cmdService,
+		ovsService:      ovsService,
+		appStateService: appStateService,
+		eventService:    eventService,
+		store:           store,
+		interval:        interval,
+
+		remediations: make(map[entities.DriftItem]*remediation),
+	}
+}
+
+// Start starts periodic drift reconciliation.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// live state is changing while config is applied
+			if s.appStateService.ActiveState() != entities.AppStateActive {
+				continue
+			}
+
+			if _, err := s.Reconcile(); err != nil {
+				log.Error().Err(err).Msg("Start: reconcile config drift error")
+			}
+		}
+	}
+}
+
+// Check compares config with live state without remediation.
+func (s *Service) Check() (report entities.DriftReport, err error) {
+	s.checkMx.Lock()
+	defer s.checkMx.Unlock()
+
+	if report, err = s.check(); err != nil {
+		return report, fmt.Errorf("Check: %w", err)
+	}
+
+	return report, nil
+}
+
+// Reconcile compares config with live state, reports changed drift and re-applies drifted sections
+// configured for auto remediation.
+func (s *Service) Reconcile() (report entities.DriftReport, err error) {
+	s.checkMx.Lock()
+	defer s.checkMx.Unlock()
+
+	if report, err = s.check(); err != nil {
+		return report, fmt.Errorf("Reconcile: %w", err)
+	}
+
+	settings, err := s.store.Settings()
+	if err != nil {
+		return report, fmt.Errorf("Reconcile: %w", err)
+	}
+
+	if report.Remediated, err = s.remediate(report.Items, settings.AutoRemediate, report.CheckedAt); err != nil {
+		log.Error().Err(err).Msg("Reconcile: remediate config drift error")
+	}
+
+	s.reportChanges(s.lastReport, report)
+	s.lastReport = report
+
+	return report, nil
+}
+
+// Settings returns drift reconciler settings.
+func (s *Service) Settings() (settings entities.DriftSettings, err error) {
+	if settings, err = s.store.Settings(); err != nil {
+		return settings, fmt.Errorf("Settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetSettings replaces drift reconciler settings, applied on the next reconciliation.
+func (s *Service) SetSettings(settings entities.DriftSettings) (err error) {
+	if err = s.store.SaveSettings(settings); err != nil {
+		return fmt.Errorf("SetSettings: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) check() (report entities.DriftReport, err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return report, fmt.Errorf("check: %w", err)
+	}
+
+	report = entities.DriftReport{
+		CheckedAt: time.Now(),
+		Items:     make([]entities.DriftItem, 0),
+		Errors:    make(map[string]string),
+	}
+
+	// sections are checked independently, live state of one section may be unavailable
+	addSection := func(sections []string, items []entities.DriftItem, err error) {
+		if err != nil {
+			for _, section := range sections {
+				report.Errors[section] = err.Error()
+			}
+
+			return
+		}
+
+		report.Items = append(report.Items, items...)
+	}
+
+	addresses, err := s.liveAddresses()
+	addSection([]string{entities.DriftSectionPort, entities.DriftSectionLoopback}, append(
+		comparePortAddresses(cfg, addresses),
+		compareLoopback(cfg, addresses)...,
+	), err)
+
+	existingRules, err := s.cmdService.GetIPRules()
+	addSection([]string{entities.DriftSectionIPRule}, compareIPRules(cfg, existingRules), err)
+
+	missingRoutes, err := s.missingDefaultRoutes(cfg)
+	addSection([]string{entities.DriftSectionRouting}, compareRoutes(missingRoutes), err)
+
+	wgInterfaces, err := s.liveWireguard(cfg)
+	addSection([]string{entities.DriftSectionWireguard}, compareWireguard(cfg, wgInterfaces), err)
+
+	expectedTarget, managers, err := s.liveOVSManagers()
+	addSection([]string{entities.DriftSectionOVS}, compareOVSManagers(expectedTarget, managers), err)
+
+	slices.SortFunc(report.Items, compareItems)
+
+	return report, nil
+}
+
+func (s *Service) liveAddresses() (addresses map[string][]string, err error) {
+	output, err := s.cmdService.ApplyCommandWithOutput("ip --json address show")
+	if err != nil {
+		return nil, fmt.Errorf("liveAddresses: %w", err)
+	}
+
+	if addresses, err = parseAddresses(output); err != nil {
+		return nil, fmt.Errorf("liveAddresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+func (s *Service) missingDefaultRoutes(cfg config.Config) (missing map[string][]int, err error) {
+	missing = make(map[string][]int)
+	for _, port := range activeWANPorts(cfg) {
+		for _, table := range port.TableIDs {
+			if _, err = s.cmdService.GetPortDefaultRoute(port.Name, table); err != nil {
+				if !errors.Is(err, cmd.ErrDefaultRouteNotFound) {
+					return nil, fmt.Errorf("missingDefaultRoutes: %w", err)
+				}
+
+				missing[port.Name] = append(missing[port.Name], table)
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+func (s *Service) liveWireguard(cfg config.Config) (interfaces map[string]wgPeers, err error) {
+	if cfg.Wireguard == nil || len(cfg.Wireguard.Configs) == 0 {
+		return nil, nil
+	}
+
+	output, err := s.cmdService.ApplyCommandWithOutput("wg show all dump")
+	if err != nil {
+		return nil, fmt.Errorf("liveWireguard: %w", err)
+	}
+
+	return parseWgDump(output), nil
+}
+
+func (s *Service) liveOVSManagers() (expectedTarget string, managers []string, err error) {
+	controllerAddr, err := s.ovsService.ControllerAddr()
+	if err != nil {
+		return "", nil, fmt.Errorf("liveOVSManagers: %w", err)
+	}
+
+	if controllerAddr == "" {
+		return "", nil, nil
+	}
+
+	if managers, err = s.ovsService.Managers(); err != nil {
+		return "", nil, fmt.Errorf("liveOVSManagers: %w", err)
+	}
+
+	return ovs.ManagerTarget(controllerAddr), managers, nil
+}
+
+// remediate re-applies drifted sections allowed for auto remediation, returns re-applied sections.
+// Each drift item is remediated at most maxRemediationAttempts times with growing delay.
+func (s *Service) remediate(items []entities.DriftItem, autoRemediate []string, now time.Time) (remediated []string, err error) {
+	// resolved drift is forgotten, it starts from the first attempt if it appears again
+	for item := range s.remediations {
+		if !lo.Contains(items, item) {
+			delete(s.remediations, item)
+		}
+	}
+
+	attempted := lo.Filter(items, func(item entities.DriftItem, _ int) bool {
+		if !lo.Contains(autoRemediate, item.Section) {
+			return false
+		}
+
+		state, exists := s.remediations[item]
+		if exists && state.attempts >= maxRemediationAttempts {
+			s.giveUp(item, state)
+			return false
+		}
+
+		return !exists || !now.Before(state.nextAt)
+	})
+	sections := lo.Uniq(lo.Map(attempted, func(item entities.DriftItem, _ int) string {
+		return item.Section
+	}))
+	if len(sections) == 0 {
+		return nil, nil
+	}
+
+	defer s.countAttempts(attempted, now)
+
+	if lo.Contains(sections, entities.DriftSectionOVS) {
+		controllerAddr, err := s.ovsService.ControllerAddr()
+		if err != nil {
+			return remediated, fmt.Errorf("remediate: %w", err)
+		}
+
+		if err = s.ovsService.SetupOVSManager(controllerAddr); err != nil {
+			return remediated, fmt.Errorf("remediate: %w", err)
+		}
+
+		remediated = append(remediated, entities.DriftSectionOVS)
+	}
+
+	configSections := lo.Without(sections, entities.DriftSectionOVS)
+	if len(configSections) > 0 {
+		if err = s.appStateService.Perform(entities.NewOnReapplySections(configSections)); err != nil {
+			return remediated, fmt.Errorf("remediate: %w", err)
+		}
+
+		remediated = append(remediated, configSections...)
+	}
+
+	log.Info().
+		Strs("sections", remediated).
+		Msg("remediate: drifted sections re-applied")
+
+	return remediated, nil
+}
+
+// countAttempts counts remediation attempts of items, the next attempt is delayed twice longer.
+func (s *Service) countAttempts(items []entities.DriftItem, now time.Time) {
+	for _, item := range items {
+		state, exists := s.remediations[item]
+		if !exists {
+			state = &remediation{}
+			s.remediations[item] = state
+		}
+
+		state.nextAt = now.Add(s.interval << state.attempts)
+		state.attempts++
+	}
+}
+
+// giveUp reports drift item which is not remediated after all attempts, it is reported once.
+func (s *Service) giveUp(item entities.DriftItem, state *remediation) {
+	if state.givenUp {
+		return
+	}
+	state.givenUp = true
+
+	log.Error().
+		Any("item", item).
+		Int("attempts", state.attempts).
+		Msg("giveUp: drift is not remediated, auto remediation is stopped")
+
+	s.eventService.Report(entities.AgentEvent{
+		Source:   eventSource,
+		Type:     "config_drift_remediation_failed",
+		Severity: entities.EventSeverityError,
+		Message: fmt.Sprintf("%s %s %s of %s is not remediated after %d attempts",
+			item.Section, item.Kind, item.Value, item.Object, state.attempts),
+		Data: item,
+	})
+}
+
+// reportChanges reports drift event when drift items differ from the previous reconciliation.
+func (s *Service) reportChanges(previous, current entities.DriftReport) {
+	if slices.Equal(previous.Items, current.Items) && len(current.Remediated) == 0 {
+		return
+	}
+
+	switch {
+	case len(current.Items) == 0:
+		s.eventService.Report(entities.AgentEvent{
+			Source:   eventSource,
+			Type:     "config_drift_resolved",
+			Severity: entities.EventSeverityInfo,
+			Message:  "live state matches configuration",
+		})
+
+	case len(current.Remediated) > 0:
+		s.eventService.Report(entities.AgentEvent{
+			Source:   eventSource,
+			Type:     "config_drift_remediated",
+			Severity: entities.EventSeverityWarning,
+			Message:  fmt.Sprintf("live state drifted from configuration, re-applied sections %v", current.Remediated),
+			Data:     current,
+		})
+
+	default:
+		s.eventService.Report(entities.AgentEvent{
+			Source:   eventSource,
+			Type:     "config_drift",
+			Severity: entities.EventSeverityWarning,
+			Message:  fmt.Sprintf("live state drifted from configuration, %d differences found", len(current.Items)),
+			Data:     current,
+		})
+	}
+}
+
+func compareItems(a, b entities.DriftItem) int {
+	return cmp.Or(
+		cmp.Compare(a.Section, b.Section),
+		cmp.Compare(a.Object, b.Object),
+		cmp.Compare(a.Value, b.Value),
+		cmp.Compare(a.Kind, b.Kind),
+	)
+}
@@ -0,0 +1,141 @@
+package drift_test
+
+import (
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/drift"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/drift/drift_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const testControllerAddr = "10.0.0.1:6653"
+
+type serviceFields struct {
+	configService   *drift_mocks.MockIConfigService
+	cmdService      *drift_mocks.MockICmdService
+	ovsService      *drift_mocks.MockIOVSService
+	appStateService *drift_mocks.MockIAppStateService
+	eventService    *drift_mocks.MockIEventService
+	store           *drift_mocks.MockIStore
+
+	events []string
+}
+
+func newServiceFields(t *testing.T) *serviceFields {
+	f := &serviceFields{
+		configService:   drift_mocks.NewMockIConfigService(t),
+		cmdService:      drift_mocks.NewMockICmdService(t),
+		ovsService:      drift_mocks.NewMockIOVSService(t),
+		appStateService: drift_mocks.NewMockIAppStateService(t),
+		eventService:    drift_mocks.NewMockIEventService(t),
+		store:           drift_mocks.NewMockIStore(t),
+	}
+
+	f.configService.EXPECT().
+		GetConfig().
+		Return(config.Config{}, nil).
+		Maybe()
+	f.cmdService.EXPECT().
+		ApplyCommandWithOutput("ip --json address show").
+		Return([]byte("[]"), nil).
+		Maybe()
+	f.cmdService.EXPECT().
+		GetIPRules().
+		Return(map[string]bool{}, nil).
+		Maybe()
+	f.ovsService.EXPECT().
+		ControllerAddr().
+		Return(testControllerAddr, nil).
+		Maybe()
+	f.store.EXPECT().
+		Settings().
+		Return(entities.DriftSettings{AutoRemediate: []string{entities.DriftSectionOVS}}, nil).
+		Maybe()
+	f.eventService.EXPECT().
+		Report(mock.Anything).
+		Run(func(event entities.AgentEvent) {
+			f.events = append(f.events, event.Type)
+		}).
+		Maybe()
+
+	return f
+}
+
+func TestService_Reconcile_givesUp(t *testing.T) {
+	var (
+		f       = newServiceFields(t)
+		service = drift.NewService(f.configService, f.cmdService, f.ovsService, f.appStateService, f.eventService, f.store, 0)
+	)
+
+	// manager is not restored by remediation
+	f.ovsService.EXPECT().
+		Managers().
+		Return(nil, nil).
+		Maybe()
+	f.ovsService.EXPECT().
+		SetupOVSManager(testControllerAddr).
+		Return(nil).
+		Times(3)
+
+	for range 3 {
+		report, err := service.Reconcile()
+		require.NoError(t, err)
+		require.Equal(t, []string{entities.DriftSectionOVS}, report.Remediated)
+	}
+
+	for range 2 {
+		report, err := service.Reconcile()
+		require.NoError(t, err)
+		require.Empty(t, report.Remediated)
+	}
+	require.Equal(t, 1, countEvents(f.events, "config_drift_remediation_failed"))
+}
+
+func TestService_Reconcile_resolved(t *testing.T) {
+	var (
+		f        = newServiceFields(t)
+		service  = drift.NewService(f.configService, f.cmdService, f.ovsService, f.appStateService, f.eventService, f.store, 0)
+		managers []string
+	)
+
+	f.ovsService.EXPECT().
+		Managers().
+		RunAndReturn(func() ([]string, error) {
+			return managers, nil
+		}).
+		Maybe()
+	f.ovsService.EXPECT().
+		SetupOVSManager(testControllerAddr).
+		Return(nil).
+		Times(5)
+
+	reconcile := func(times int) {
+		for range times {
+			_, err := service.Reconcile()
+			require.NoError(t, err)
+		}
+	}
+
+	// resolved drift starts from the first attempt when it appears again
+	reconcile(2)
+	managers = []string{"ptcp:6653"}
+	reconcile(1)
+	managers = nil
+	reconcile(3)
+
+	require.Zero(t, countEvents(f.events, "config_drift_remediation_failed"))
+}
+
+func countEvents(events []string, eventType string) (count int) {
+	for _, event := range events {
+		if event == eventType {
+			count++
+		}
+	}
+
+	return count
+}
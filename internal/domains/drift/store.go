@@ -0,0 +1,65 @@
+package drift
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const settingsKey = "drift:settings"
+
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	// Store keeps drift reconciler settings in badger.
+	Store struct {
+		db IBadgerDB
+	}
+)
+
+func NewStore(db IBadgerDB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Settings returns saved settings, empty settings (report only) if nothing is saved yet.
+func (s *Store) Settings() (settings entities.DriftSettings, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(settingsKey))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(value []byte) error {
+			return json.Unmarshal(value, &settings)
+		})
+	}); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return settings, fmt.Errorf("Settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings replaces drift reconciler settings.
+func (s *Store) SaveSettings(settings entities.DriftSettings) (err error) {
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("SaveSettings: %w", err)
+	}
+
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(settingsKey), value)
+	}); err != nil {
+		return fmt.Errorf("SaveSettings: %w", err)
+	}
+
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell/commands"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 )
 
 type (
@@ -25,19 +27,25 @@ type (
 		ExecWithStdout(command shell.ICommand, stdout io.Writer) (err error)
 	}
 
+	IPortStatsService interface {
+		PortStats(portName string) (stats entities.PortStats, found bool)
+	}
+
 	Service struct {
-		configService IConfigService
-		shellService  IShellService
+		configService    IConfigService
+		shellService     IShellService
+		portStatsService IPortStatsService
 
 		ipRouteCmd *commands.ListIPRoutesCmd
 		ipRuleCmd  *commands.CustomCmd
 	}
 )
 
-func NewService(configService IConfigService, shellService IShellService) *Service {
+func NewService(configService IConfigService, shellService IShellService, portStatsService IPortStatsService) *Service {
 	return &Service{
-		configService: configService,
-		shellService:  shellService,
+		configService:    configService,
+		shellService:     shellService,
+		portStatsService: portStatsService,
 
 		ipRouteCmd: commands.NewListIPRoutesCmd(),
 		ipRuleCmd:  commands.NewCustomCmd("ip rule"),
@@ -111,6 +119,12 @@ func (s *Service) DumpStats(dumpKey string) {
 				log.Error().Err(err).Msg("DumpStats: get ip address error")
 			}
 
+			// dump traffic counters and rates
+			if stats, found := s.portStatsService.PortStats(port.Name); found {
+				msg.Str(fmt.Sprintf("%d. counters %s", c.get(), port.Name), formatCounters(stats.Counters))
+				msg.Str(fmt.Sprintf("%d. rates %s", c.get(), port.Name), formatRates(stats.Rates))
+			}
+
 			// get ip route from tables
 			for _, tableID := range port.TableIDs {
 				buf.Reset()
@@ -153,6 +167,16 @@ func (s *Service) DumpStats(dumpKey string) {
 		Msg("DumpStats: system network dump")
 }
 
+func formatCounters(c entities.PortCounters) string {
+	return fmt.Sprintf("rx %d bytes %d packets %d errors %d dropped, tx %d bytes %d packets %d errors %d dropped",
+		c.RxBytes, c.RxPackets, c.RxErrors, c.RxDropped, c.TxBytes, c.TxPackets, c.TxErrors, c.TxDropped)
+}
+
+func formatRates(r entities.PortRates) string {
+	return fmt.Sprintf("rx %.0f B/s %.1f pkt/s %.1f err/s %.1f drop/s, tx %.0f B/s %.1f pkt/s %.1f err/s %.1f drop/s",
+		r.RxBytes, r.RxPackets, r.RxErrors, r.RxDropped, r.TxBytes, r.TxPackets, r.TxErrors, r.TxDropped)
+}
+
 func (s *Service) parseLineByLine(buf *bytes.Buffer, fn func(line string)) (err error) {
 	scanner := bufio.NewScanner(buf)
 	for scanner.Scan() {
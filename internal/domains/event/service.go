@@ -0,0 +1,154 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	maxPendingEvents = 200
+	sendTimeout      = 5 * time.Second
+	retryInterval    = 10 * time.Second
+)
+
+type (
+	IMessagePublisher interface {
+		IsActive() bool
+		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
+	}
+
+	// Service delivers agent events to orchestrator. Events are queued and sent in order,
+	// undelivered events are retried, the oldest events are dropped when queue is full.
+	Service struct {
+		messagePublisher IMessagePublisher
+
+		mx      sync.Mutex
+		seq     uint64
+		pending []pendingEvent
+		notify  chan struct{}
+	}
+
+	pendingEvent struct {
+		seq   uint64
+		event entities.AgentEvent
+	}
+)
+
+func NewService(messagePublisher IMessagePublisher) *Service {
+	return &Service{
+		messagePublisher: messagePublisher,
+		pending:          make([]pendingEvent, 0),
+		notify:           make(chan struct{}, 1),
+	}
+}
+
+// Report queues event for delivery, never blocks.
+func (s *Service) Report(event entities.AgentEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	log.Info().
+		Any("event", event).
+		Msg("Report: agent event")
+
+	s.mx.Lock()
+	if len(s.pending) >= maxPendingEvents {
+		log.Warn().
+			Any("event", s.pending[0].event).
+			Msg("Report: event queue is full, drop the oldest event")
+
+		s.pending = s.pending[1:]
+	}
+	s.seq++
+	s.pending = append(s.pending, pendingEvent{
+		seq:   s.seq,
+		event: event,
+	})
+	s.mx.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends queued events until context is done.
+func (s *Service) Start(ctx context.Context) {
+	for {
+		if err := s.flush(ctx); err != nil {
+			log.Debug().
+				Err(err).
+				Msg("Start: send events error, retrying later")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+		}
+	}
+}
+
+func (s *Service) flush(ctx context.Context) (err error) {
+	for ctx.Err() == nil {
+		s.mx.Lock()
+		if len(s.pending) == 0 {
+			s.mx.Unlock()
+			return nil
+		}
+		pending := s.pending[0]
+		s.mx.Unlock()
+
+		if err = s.send(pending.event); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+
+		s.mx.Lock()
+		// event could be dropped by Report while sending
+		if len(s.pending) > 0 && s.pending[0].seq == pending.seq {
+			s.pending = s.pending[1:]
+		}
+		s.mx.Unlock()
+	}
+
+	return nil
+}
+
+func (s *Service) send(event entities.AgentEvent) (err error) {
+	if !s.messagePublisher.IsActive() {
+		return fmt.Errorf("send: websocket connection is not active")
+	}
+
+	resp, err := s.messagePublisher.PublishRequest(constants.MethodAgentEvent, constants.OrchestratorWSID, event,
+		wschat.RequestOptions{
+			Timeout: lo.ToPtr(sendTimeout),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	if resp.IsErrorResponse() {
+		return fmt.Errorf("send: %w", resp.Error())
+	}
+
+	return nil
+}
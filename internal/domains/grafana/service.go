@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"text/template"
 
 	"github.com/rs/zerolog/log"
@@ -18,6 +19,16 @@ const (
 	templateName          = "grafanaTemplates"
 	grafanaServiceName    = "grafana-agent-flow"
 	grafanaConfigTemplate = "grafana_config.tpl"
+	scrapeConfigTemplate  = "agent_scrape.river.tpl"
+	importBlockTemplate   = "agent_import.river.tpl"
+
+	importBlockBegin = "// BEGIN sdwan-agent managed block"
+	importBlockEnd   = "// END sdwan-agent managed block"
+)
+
+var (
+	importBlockPattern = regexp.MustCompile(`(?s)\n*` + regexp.QuoteMeta(importBlockBegin) + `.*?` + regexp.QuoteMeta(importBlockEnd) + `\n?`)
+	remoteWritePattern = regexp.MustCompile(`(?m)^\s*prometheus\.remote_write\s+"([^"]+)"`)
 )
 
 //go:embed templates/*
@@ -25,13 +36,18 @@ var templatesFS embed.FS
 
 type Service struct {
 	grafanaConfigPath string
+	agentConfigPath   string
+	scrapeConfigPath  string
 	mimirPort         int
 	lokiPort          int
+	metricsAddr       string
+	metricsPath       string
 
 	templates *template.Template
 }
 
-func NewService(grafanaConfigPath string, mimirPort, lokiPort int) *Service {
+func NewService(grafanaConfigPath, agentConfigPath, scrapeConfigPath string, mimirPort, lokiPort int,
+	metricsAddr, metricsPath string) *Service {
 	templates, err := template.New(templateName).
 		ParseFS(templatesFS, "templates/*tpl")
 	if err != nil {
@@ -40,8 +56,12 @@ func NewService(grafanaConfigPath string, mimirPort, lokiPort int) *Service {
 
 	return &Service{
 		grafanaConfigPath: grafanaConfigPath,
+		agentConfigPath:   agentConfigPath,
+		scrapeConfigPath:  scrapeConfigPath,
 		mimirPort:         mimirPort,
 		lokiPort:          lokiPort,
+		metricsAddr:       metricsAddr,
+		metricsPath:       metricsPath,
 
 		templates: templates,
 	}
@@ -49,64 +69,46 @@ func NewService(grafanaConfigPath string, mimirPort, lokiPort int) *Service {
 
 // ConfigureAgent configures grafana agent if settings changed.
 func (s *Service) ConfigureAgent(serverAddr string) (err error) {
-	configDir := filepath.Dir(s.grafanaConfigPath)
-
-	// create directory if not exists
-	if _, dirErr := os.Stat(configDir); dirErr != nil {
-		if !os.IsNotExist(dirErr) {
-			return fmt.Errorf("ConfigureAgent: %w", dirErr)
-		}
-
-		if err = os.MkdirAll(configDir, os.ModePerm); err != nil {
-			return fmt.Errorf("ConfigureAgent: %w", err)
-		}
-	}
-
-	// write grafana config
-	var buffer bytes.Buffer
 	data := struct {
-		MIMIR string
-		LOKI  string
+		MIMIR              string
+		LOKI               string
+		AgentMetricsTarget string
+		AgentMetricsPath   string
 	}{
-		MIMIR: fmt.Sprintf("%s:%d/api/v1/push", serverAddr, s.mimirPort),
-		LOKI:  fmt.Sprintf("%s:%d/loki/api/v1/push", serverAddr, s.lokiPort),
-	}
-	if err = s.templates.ExecuteTemplate(&buffer, grafanaConfigTemplate, data); err != nil {
-		return fmt.Errorf("ConfigureAgent: %w", err)
+		MIMIR:              fmt.Sprintf("%s:%d/api/v1/push", serverAddr, s.mimirPort),
+		LOKI:               fmt.Sprintf("%s:%d/loki/api/v1/push", serverAddr, s.lokiPort),
+		AgentMetricsTarget: s.metricsAddr,
+		AgentMetricsPath:   s.metricsPath,
 	}
 
-	stat, fileErr := os.Stat(s.grafanaConfigPath)
-	if fileErr != nil {
-		if !os.IsNotExist(fileErr) {
-			return fmt.Errorf("ConfigureAgent: %w", fileErr)
-		}
-
-		if err = os.WriteFile(s.grafanaConfigPath, buffer.Bytes(), constants.FilePerm); err != nil {
+	var restartRequired bool
+	for path, templateName := range map[string]string{
+		s.grafanaConfigPath: grafanaConfigTemplate,
+		s.scrapeConfigPath:  scrapeConfigTemplate,
+	} {
+		var buffer bytes.Buffer
+		if err = s.templates.ExecuteTemplate(&buffer, templateName, data); err != nil {
 			return fmt.Errorf("ConfigureAgent: %w", err)
 		}
 
-		if err = s.restartGrafanaService(); err != nil {
-			return fmt.Errorf("ConfigureAgent: %w", err)
+		changed, wErr := writeIfChanged(path, buffer.Bytes())
+		if wErr != nil {
+			return fmt.Errorf("ConfigureAgent: %w", wErr)
 		}
 
-		return nil
+		restartRequired = restartRequired || changed
 	}
 
-	// compare diff
-	oldContent, err := os.ReadFile(s.grafanaConfigPath)
+	changed, err := s.importScrapeModule()
 	if err != nil {
 		return fmt.Errorf("ConfigureAgent: %w", err)
 	}
+	restartRequired = restartRequired || changed
 
-	if string(oldContent) == buffer.String() {
+	if !restartRequired {
 		return nil
 	}
 
-	// overwrite file
-	if err = os.WriteFile(s.grafanaConfigPath, buffer.Bytes(), stat.Mode().Perm()); err != nil {
-		return fmt.Errorf("ConfigureAgent: %w", fileErr)
-	}
-
 	if err = s.restartGrafanaService(); err != nil {
 		return fmt.Errorf("ConfigureAgent: %w", err)
 	}
@@ -114,6 +116,86 @@ func (s *Service) ConfigureAgent(serverAddr string) (err error) {
 	return nil
 }
 
+// importScrapeModule adds managed block importing scrape module to the main agent config,
+// agent metrics are forwarded to remote write component of the main config.
+func (s *Service) importScrapeModule() (changed bool, err error) {
+	agentConfig, err := os.ReadFile(s.agentConfigPath)
+	if err != nil {
+		return false, fmt.Errorf("importScrapeModule: %w", err)
+	}
+
+	content, err := s.withImportBlock(agentConfig)
+	if err != nil {
+		return false, fmt.Errorf("importScrapeModule: %w", err)
+	}
+
+	if changed, err = writeIfChanged(s.agentConfigPath, content); err != nil {
+		return false, fmt.Errorf("importScrapeModule: %w", err)
+	}
+
+	return changed, nil
+}
+
+// withImportBlock replaces managed block at the end of the agent config.
+func (s *Service) withImportBlock(agentConfig []byte) (content []byte, err error) {
+	content = importBlockPattern.ReplaceAll(agentConfig, nil)
+
+	match := remoteWritePattern.FindSubmatch(content)
+	if match == nil {
+		return nil, fmt.Errorf("withImportBlock: prometheus.remote_write component is not found in %s", s.agentConfigPath)
+	}
+
+	var block bytes.Buffer
+	if err = s.templates.ExecuteTemplate(&block, importBlockTemplate, struct {
+		BlockBegin       string
+		BlockEnd         string
+		ScrapeConfigPath string
+		RemoteWrite      string
+	}{
+		BlockBegin:       importBlockBegin,
+		BlockEnd:         importBlockEnd,
+		ScrapeConfigPath: s.scrapeConfigPath,
+		RemoteWrite:      string(match[1]),
+	}); err != nil {
+		return nil, fmt.Errorf("withImportBlock: %w", err)
+	}
+
+	content = append(bytes.TrimRight(content, "\n"), "\n\n"...)
+
+	return append(content, block.Bytes()...), nil
+}
+
+// writeIfChanged writes content to file (creating directory) when it differs from the current one.
+func writeIfChanged(path string, content []byte) (changed bool, err error) {
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return false, fmt.Errorf("writeIfChanged: %w", err)
+	}
+
+	perm := os.FileMode(constants.FilePerm)
+	stat, err := os.Stat(path)
+	switch {
+	case err == nil:
+		oldContent, rErr := os.ReadFile(path)
+		if rErr != nil {
+			return false, fmt.Errorf("writeIfChanged: %w", rErr)
+		}
+
+		if bytes.Equal(oldContent, content) {
+			return false, nil
+		}
+
+		perm = stat.Mode().Perm()
+	case !os.IsNotExist(err):
+		return false, fmt.Errorf("writeIfChanged: %w", err)
+	}
+
+	if err = os.WriteFile(path, content, perm); err != nil {
+		return false, fmt.Errorf("writeIfChanged: %w", err)
+	}
+
+	return true, nil
+}
+
 func (s *Service) restartGrafanaService() (err error) {
 	reloadCmd := exec.Command("systemctl", "daemon-reload")
 	if err = reloadCmd.Run(); err != nil {
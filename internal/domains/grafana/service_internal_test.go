@@ -0,0 +1,46 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_withImportBlock(t *testing.T) {
+	service := NewService("", "/etc/grafana-agent-flow.river", "/etc/grafana-agent-flow.d/sdwan-agent.river",
+		1082, 1081, "127.0.0.1:9100", "/metrics")
+
+	agentConfig := []byte(`prometheus.remote_write "mimir" {
+	endpoint {
+		url = env("MIMIR")
+	}
+}
+`)
+
+	content, err := service.withImportBlock(agentConfig)
+	require.NoError(t, err)
+	require.Equal(t, `prometheus.remote_write "mimir" {
+	endpoint {
+		url = env("MIMIR")
+	}
+}
+
+// BEGIN sdwan-agent managed block
+import.file "sdwan_agent" {
+	filename = "/etc/grafana-agent-flow.d/sdwan-agent.river"
+}
+
+sdwan_agent.scrape "metrics" {
+	forward_to = [prometheus.remote_write.mimir.receiver]
+}
+// END sdwan-agent managed block
+`, string(content))
+
+	// managed block is replaced, not duplicated
+	again, err := service.withImportBlock(content)
+	require.NoError(t, err)
+	require.Equal(t, string(content), string(again))
+
+	_, err = service.withImportBlock([]byte(`loki.write "default" {}`))
+	require.ErrorContains(t, err, "prometheus.remote_write")
+}
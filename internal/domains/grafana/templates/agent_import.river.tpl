@@ -0,0 +1,9 @@
+{{ .BlockBegin }}
+import.file "sdwan_agent" {
+	filename = "{{ .ScrapeConfigPath }}"
+}
+
+sdwan_agent.scrape "metrics" {
+	forward_to = [prometheus.remote_write.{{ .RemoteWrite }}.receiver]
+}
+{{ .BlockEnd }}
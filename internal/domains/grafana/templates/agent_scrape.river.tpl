@@ -0,0 +1,13 @@
+// Managed by sdwan-agent, changes will be overwritten.
+declare "scrape" {
+	argument "forward_to" {
+		comment = "Receivers of agent metrics."
+	}
+
+	prometheus.scrape "sdwan_agent" {
+		targets         = [{"__address__" = "{{ .AgentMetricsTarget }}"}]
+		metrics_path    = "{{ .AgentMetricsPath }}"
+		scrape_interval = "30s"
+		forward_to      = argument.forward_to.value
+	}
+}
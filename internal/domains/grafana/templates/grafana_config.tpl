@@ -3,3 +3,5 @@ StandardOutput=null
 StandardError=null
 Environment=MIMIR={{ .MIMIR }}
 Environment=LOKI={{ .LOKI }}
+Environment=SDWAN_AGENT_METRICS_TARGET={{ .AgentMetricsTarget }}
+Environment=SDWAN_AGENT_METRICS_PATH={{ .AgentMetricsPath }}
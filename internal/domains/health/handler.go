@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	IHealthService interface {
+		Check(ctx context.Context) (health entities.AgentHealth)
+	}
+
+	Handler struct {
+		healthService IHealthService
+	}
+)
+
+func NewHandler(healthService IHealthService) *Handler {
+	return &Handler{
+		healthService: healthService,
+	}
+}
+
+// ServeHealth serves agent health report, responds with 503 status when agent is not healthy.
+func (h *Handler) ServeHealth(w http.ResponseWriter, r *http.Request) {
+	health := h.healthService.Check(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !health.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Error().Err(err).Msg("ServeHealth")
+	}
+}
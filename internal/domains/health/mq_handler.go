@@ -0,0 +1,17 @@
+package health
+
+import (
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/nats-io/nats.go"
+)
+
+type MQHandler struct{}
+
+func NewMQHandler() *MQHandler {
+	return new(MQHandler)
+}
+
+// Ping responds to message broker health probe.
+func (h *MQHandler) Ping(_ *nats.Msg) (resp any) {
+	return mq.NewOkResponse()
+}
@@ -0,0 +1,212 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/activity"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/nats-io/nats.go"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	natsPingTimeout = time.Second
+	badgerProbeKey  = "agent:health:probe"
+	badgerProbeTTL  = time.Minute
+
+	// badgerProbeInterval limits probe writes: checks in between reuse the last result,
+	// so frequent health polling does not churn badger value log.
+	badgerProbeInterval = 30 * time.Second
+)
+
+type (
+	IAppStateService interface {
+		ActiveState() entities.AppState
+		GetStatus() (status entities.HealthcheckStatus)
+		RunningTransactionID() string
+	}
+
+	IConnectionService interface {
+		IsConnectionAlive() (isAlive bool)
+	}
+
+	IMQService interface {
+		Request(subject string, message any, timeout time.Duration, optionFuncs ...mq.RequestOption) (response *nats.Msg, err error)
+	}
+
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+	}
+
+	IStorageAdapter interface {
+		GetNotFinishedTransactions(ctx context.Context) (result activity.Transactions, err error)
+	}
+
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	Service struct {
+		appStateService   IAppStateService
+		connectionService IConnectionService
+		mqService         IMQService
+		db                IBadgerDB
+		storageAdapter    IStorageAdapter
+		configService     IConfigService
+
+		badgerProbeMx  sync.Mutex
+		badgerProbedAt time.Time
+		badgerProbeErr error
+	}
+)
+
+func NewService(
+	appStateService IAppStateService,
+	connectionService IConnectionService,
+	mqService IMQService,
+	db IBadgerDB,
+	storageAdapter IStorageAdapter,
+	configService IConfigService,
+) *Service {
+	return &Service{
+		appStateService:   appStateService,
+		connectionService: connectionService,
+		mqService:         mqService,
+		db:                db,
+		storageAdapter:    storageAdapter,
+		configService:     configService,
+	}
+}
+
+// Check collects agent health. Agent is healthy when it left boot state, connected to orchestrator
+// and message broker, storage is writable and the last state transition did not fail.
+func (s *Service) Check(ctx context.Context) (health entities.AgentHealth) {
+	health = entities.AgentHealth{
+		Problems:           make([]string, 0),
+		AppState:           s.appStateService.ActiveState(),
+		WebsocketConnected: s.connectionService.IsConnectionAlive(),
+		LastApply:          s.appStateService.GetStatus(),
+		Tunnels:            make([]entities.TunnelHealth, 0),
+		CheckedAt:          time.Now(),
+	}
+
+	if health.AppState == entities.AppStateBoot {
+		health.Problems = append(health.Problems, "agent is booting")
+	}
+
+	if !health.WebsocketConnected {
+		health.Problems = append(health.Problems, "orchestrator websocket is not connected")
+	}
+
+	if err := s.checkNATS(); err != nil {
+		health.Problems = append(health.Problems, err.Error())
+	} else {
+		health.NATSConnected = true
+	}
+
+	if err := s.checkBadger(); err != nil {
+		health.Problems = append(health.Problems, err.Error())
+	} else {
+		health.BadgerWritable = true
+	}
+
+	if health.LastApply.ExecFinished && health.LastApply.Error != "" {
+		health.Problems = append(health.Problems, fmt.Sprintf("last state transition failed on step %q", health.LastApply.ErrorStep))
+	}
+
+	// transaction of running transition is not finished too, leftovers of previous runs are counted at any time
+	transactions, err := s.storageAdapter.GetNotFinishedTransactions(ctx)
+	if err != nil {
+		health.Problems = append(health.Problems, fmt.Sprintf("fetch transactions: %s", err))
+	} else {
+		runningTxID := s.appStateService.RunningTransactionID()
+		health.DanglingTransactions = lo.CountBy(transactions, func(tx activity.Transaction) bool {
+			return tx.UUID != runningTxID
+		})
+	}
+
+	if health.DanglingTransactions > 0 {
+		health.Problems = append(health.Problems, strconv.Itoa(health.DanglingTransactions)+" dangling transactions found")
+	}
+
+	tunnels, err := s.tunnelsHealth()
+	if err != nil {
+		health.Problems = append(health.Problems, err.Error())
+	}
+	health.Tunnels = append(health.Tunnels, tunnels...)
+
+	health.Healthy = len(health.Problems) == 0
+
+	return health
+}
+
+// checkNATS sends ping request to the agent itself through message broker.
+func (s *Service) checkNATS() (err error) {
+	if _, err = s.mqService.Request(constants.MQAgentHealthPing, nil, natsPingTimeout, mq.NewRetryAmountOption(1)); err != nil {
+		return fmt.Errorf("checkNATS: %w", err)
+	}
+
+	return nil
+}
+
+// checkBadger writes expiring probe key to badger, result is cached for badgerProbeInterval.
+func (s *Service) checkBadger() (err error) {
+	s.badgerProbeMx.Lock()
+	defer s.badgerProbeMx.Unlock()
+
+	if !s.badgerProbedAt.IsZero() && time.Since(s.badgerProbedAt) < badgerProbeInterval {
+		return s.badgerProbeErr
+	}
+
+	s.badgerProbeErr = s.probeBadger()
+	s.badgerProbedAt = time.Now()
+
+	return s.badgerProbeErr
+}
+
+func (s *Service) probeBadger() (err error) {
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(
+			badger.NewEntry([]byte(badgerProbeKey), []byte(time.Now().Format(time.RFC3339))).
+				WithTTL(badgerProbeTTL),
+		)
+	}); err != nil {
+		return fmt.Errorf("probeBadger: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) tunnelsHealth() (tunnels []entities.TunnelHealth, err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return tunnels, fmt.Errorf("tunnelsHealth: %w", err)
+	}
+
+	if cfg.Pony == nil {
+		return tunnels, nil
+	}
+
+	for _, cluster := range cfg.Pony.Clusters {
+		for _, uplink := range cluster.Uplinks {
+			tunnels = append(tunnels, entities.TunnelHealth{
+				Network:  cluster.Network,
+				Tunnel:   uplink.MonitorAddr,
+				Up:       cluster.State.LocalStates[uplink.MonitorAddr],
+				RemoteUp: cluster.State.RemoteStates[uplink.MonitorAddr],
+				Active:   cluster.State.ActiveTunnel == uplink.MonitorAddr,
+			})
+		}
+	}
+
+	return tunnels, nil
+}
@@ -0,0 +1,128 @@
+package localserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	readHeaderTimeout = 5 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+const (
+	networkTCP  = "tcp"
+	networkUnix = "unix"
+
+	socketDirPerm = 0o750
+	socketPerm    = 0o600 // admin socket is available for root only
+)
+
+// Service serves local HTTP endpoints (metrics, health) for the device daemons.
+type Service struct {
+	network    string
+	listenAddr string
+	routes     map[string]http.HandlerFunc
+}
+
+func NewService(listenAddr string) *Service {
+	return &Service{
+		network:    networkTCP,
+		listenAddr: listenAddr,
+		routes:     make(map[string]http.HandlerFunc),
+	}
+}
+
+// NewUnixService serves endpoints on unix socket (admin commands of the local CLI).
+func NewUnixService(socketPath string) *Service {
+	return &Service{
+		network:    networkUnix,
+		listenAddr: socketPath,
+		routes:     make(map[string]http.HandlerFunc),
+	}
+}
+
+func (s *Service) SetRoutes(routes map[string]http.HandlerFunc) {
+	s.routes = routes
+}
+
+// Start serves requests until context is done.
+func (s *Service) Start(ctx context.Context) {
+	mux := http.NewServeMux()
+	for pattern, handler := range s.routes {
+		mux.HandleFunc(pattern, handler)
+	}
+
+	listener, err := s.listen()
+	if err != nil {
+		log.Error().Err(err).Str("addr", s.listenAddr).Msg("Start: listen local address error")
+		return
+	}
+
+	if err = s.serve(ctx, listener, mux); err != nil {
+		log.Error().Err(err).Msg("Start")
+	}
+}
+
+func (s *Service) listen() (listener net.Listener, err error) {
+	if s.network != networkUnix {
+		if listener, err = net.Listen(s.network, s.listenAddr); err != nil {
+			return nil, fmt.Errorf("listen: %w", err)
+		}
+
+		return listener, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.listenAddr), socketDirPerm); err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	// socket left by killed agent
+	if err = os.Remove(s.listenAddr); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	if listener, err = net.Listen(s.network, s.listenAddr); err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	if err = os.Chmod(s.listenAddr, socketPerm); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	return listener, nil
+}
+
+func (s *Service) serve(ctx context.Context, listener net.Listener, handler http.Handler) (err error) {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+			log.Error().Err(shutdownErr).Msg("serve: shutdown local server error")
+		}
+	}()
+
+	log.Info().Str("addr", listener.Addr().String()).Msg("serve: local server started")
+	if err = server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
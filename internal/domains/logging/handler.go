@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	publishChunkTimeout = 10 * time.Second
+)
+
+type (
+	IMessagePublisher interface {
+		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+	}
+
+	ILoggingService interface {
+		SetLevel(component, level string, ttl time.Duration) (err error)
+		ResetLevel(component string)
+		GetLevels() entities.LogLevels
+		Tail(request entities.TailLogRequest) (result entities.LogTail, err error)
+		Follow(ctx context.Context, request entities.TailLogRequest, publish func(tail entities.LogTail) error) (err error)
+	}
+
+	Handler struct {
+		messagePublisher IMessagePublisher
+		loggingService   ILoggingService
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, loggingService ILoggingService) *Handler {
+	return &Handler{
+		messagePublisher: messagePublisher,
+		loggingService:   loggingService,
+
+		validate: validator.New(),
+	}
+}
+
+// SetLogLevel changes log level globally or for the component.
+func (h *Handler) SetLogLevel(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.SetLogLevelRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SetLogLevel: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SetLogLevel: %w", err)
+	}
+
+	if err = h.loggingService.SetLevel(
+		requestBody.Component,
+		requestBody.Level,
+		time.Duration(requestBody.TTLSeconds)*time.Second,
+	); err != nil {
+		return fmt.Errorf("SetLogLevel: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, h.loggingService.GetLevels()); err != nil {
+		return fmt.Errorf("SetLogLevel: %w", err)
+	}
+
+	return nil
+}
+
+// ResetLogLevel reverts log level of the component (or global level) to the default.
+func (h *Handler) ResetLogLevel(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.ResetLogLevelRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("ResetLogLevel: %w", err)
+	}
+
+	h.loggingService.ResetLevel(requestBody.Component)
+
+	if err = h.messagePublisher.PublishResponse(request, h.loggingService.GetLevels()); err != nil {
+		return fmt.Errorf("ResetLogLevel: %w", err)
+	}
+
+	return nil
+}
+
+// GetLogLevels returns actual log levels.
+func (h *Handler) GetLogLevels(request wschat.WebsocketMessage) (err error) {
+	if err = h.messagePublisher.PublishResponse(request, h.loggingService.GetLevels()); err != nil {
+		return fmt.Errorf("GetLogLevels: %w", err)
+	}
+
+	return nil
+}
+
+// TailLog returns last lines of the agent log file. With followSeconds new lines are streamed
+// to the requester as tail_log_chunk requests, response returns stream id at once.
+func (h *Handler) TailLog(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.TailLogRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("TailLog: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("TailLog: %w", err)
+	}
+
+	result, err := h.loggingService.Tail(requestBody)
+	if err != nil {
+		return fmt.Errorf("TailLog: %w", err)
+	}
+
+	if requestBody.FollowSeconds == 0 {
+		if err = h.messagePublisher.PublishResponse(request, result); err != nil {
+			return fmt.Errorf("TailLog: %w", err)
+		}
+
+		return nil
+	}
+
+	chunk := entities.LogTailChunk{
+		StreamID: uuid.New().String(),
+		LogTail:  result,
+	}
+	if err = h.messagePublisher.PublishResponse(request, chunk); err != nil {
+		return fmt.Errorf("TailLog: %w", err)
+	}
+
+	// continue from the returned tail
+	requestBody.Cursor, requestBody.FileID = result.Cursor, result.FileID
+	go h.followLog(request.From, chunk.StreamID, requestBody)
+
+	return nil
+}
+
+func (h *Handler) followLog(to, streamID string, request entities.TailLogRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(request.FollowSeconds)*time.Second)
+	defer cancel()
+
+	err := h.loggingService.Follow(ctx, request, func(tail entities.LogTail) error {
+		resp, err := h.messagePublisher.PublishRequest(constants.MethodTailLogChunk, to, entities.LogTailChunk{
+			StreamID: streamID,
+			LogTail:  tail,
+		}, wschat.RequestOptions{
+			Timeout: lo.ToPtr(publishChunkTimeout),
+		})
+		if err == nil && resp.IsErrorResponse() {
+			err = resp.Error()
+		}
+
+		return err
+	})
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Str("stream", streamID).
+			Msg("followLog: log stream stopped")
+	}
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/go-playground/validator/v10"
+	"github.com/nats-io/nats.go"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type MQHandler struct {
+	loggingService ILoggingService
+
+	validate *validator.Validate
+}
+
+func NewMQHandler(loggingService ILoggingService) *MQHandler {
+	return &MQHandler{
+		loggingService: loggingService,
+
+		validate: validator.New(),
+	}
+}
+
+// SetLogLevel changes log level globally or for the component.
+func (h *MQHandler) SetLogLevel(message *nats.Msg) (resp any) {
+	var request entities.SetLogLevelRequest
+	if err := json.Unmarshal(message.Data, &request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.loggingService.SetLevel(
+		request.Component,
+		request.Level,
+		time.Duration(request.TTLSeconds)*time.Second,
+	); err != nil {
+		return mq.NewInternalErrorResponse(err.Error())
+	}
+
+	return h.levelsResponse()
+}
+
+// ResetLogLevel reverts log level of the component (or global level) to the default.
+func (h *MQHandler) ResetLogLevel(message *nats.Msg) (resp any) {
+	var request entities.ResetLogLevelRequest
+	if len(message.Data) > 0 {
+		if err := json.Unmarshal(message.Data, &request); err != nil {
+			return mq.NewBadRequestResponse(err.Error())
+		}
+	}
+
+	h.loggingService.ResetLevel(request.Component)
+
+	return h.levelsResponse()
+}
+
+// GetLogLevels returns actual log levels.
+func (h *MQHandler) GetLogLevels(_ *nats.Msg) (resp any) {
+	return h.levelsResponse()
+}
+
+// TailLog returns last lines of the agent log file, new lines are polled with returned cursor.
+func (h *MQHandler) TailLog(message *nats.Msg) (resp any) {
+	var request entities.TailLogRequest
+	if len(message.Data) > 0 {
+		if err := json.Unmarshal(message.Data, &request); err != nil {
+			return mq.NewBadRequestResponse(err.Error())
+		}
+	}
+
+	if request.FollowSeconds > 0 {
+		return mq.NewBadRequestResponse("follow is supported over websocket only, poll with cursor instead")
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	result, err := h.loggingService.Tail(request)
+	if err != nil {
+		return mq.NewInternalErrorResponse(err.Error())
+	}
+
+	response := struct {
+		mq.Response
+
+		Data entities.LogTail `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     result,
+	}
+
+	return response
+}
+
+func (h *MQHandler) levelsResponse() any {
+	response := struct {
+		mq.Response
+
+		Data entities.LogLevels `json:"data"`
+	}{
+		Response: mq.NewOkResponse(),
+		Data:     h.loggingService.GetLevels(),
+	}
+
+	return response
+}
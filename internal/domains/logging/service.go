@@ -0,0 +1,338 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	defaultTailLines  = 100
+	followPollPeriod  = 500 * time.Millisecond
+	tailScanBytes     = 8 << 20 // tail without cursor reads only the end of the log
+	maxFollowers      = 4
+	globalComponentID = ""
+)
+
+type (
+	ILevelWriter interface {
+		Level() zerolog.Level
+		SetLevel(level zerolog.Level)
+		ComponentLevels() map[string]zerolog.Level
+		SetComponentLevel(component string, level zerolog.Level)
+		DeleteComponentLevel(component string)
+	}
+
+	Service struct {
+		levelWriter  ILevelWriter
+		logFilePath  string
+		defaultLevel zerolog.Level
+
+		mu       sync.Mutex
+		timers   map[string]*time.Timer
+		expireAt map[string]time.Time
+
+		followers atomic.Int32
+	}
+)
+
+func NewService(levelWriter ILevelWriter, logFilePath string) *Service {
+	return &Service{
+		levelWriter:  levelWriter,
+		logFilePath:  logFilePath,
+		defaultLevel: levelWriter.Level(),
+
+		timers:   make(map[string]*time.Timer),
+		expireAt: make(map[string]time.Time),
+	}
+}
+
+// SetLevel changes log level globally (empty component) or for the single component.
+// Non-zero ttl reverts the change automatically.
+func (s *Service) SetLevel(component, level string, ttl time.Duration) (err error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("SetLevel: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopTimer(component)
+	if component == globalComponentID {
+		s.levelWriter.SetLevel(lvl)
+	} else {
+		s.levelWriter.SetComponentLevel(component, lvl)
+	}
+
+	if ttl > 0 {
+		expireAt := time.Now().Add(ttl)
+		s.expireAt[component] = expireAt
+		s.timers[component] = time.AfterFunc(ttl, func() {
+			s.revertLevel(component, expireAt)
+		})
+	}
+
+	log.Info().
+		Str("component", component).
+		Str("level", lvl.String()).
+		Dur("ttl", ttl).
+		Msg("SetLevel: log level changed")
+
+	return nil
+}
+
+// ResetLevel reverts log level of the component (or global level) to the default.
+func (s *Service) ResetLevel(component string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resetLevel(component)
+}
+
+// SetDefaultLevel replaces default global level (used on settings reload).
+func (s *Service) SetDefaultLevel(level string) (err error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("SetDefaultLevel: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultLevel = lvl
+	if _, ok := s.timers[globalComponentID]; !ok {
+		// global level is not temporarily overridden
+		s.levelWriter.SetLevel(lvl)
+	}
+
+	return nil
+}
+
+// GetLevels returns actual log levels.
+func (s *Service) GetLevels() entities.LogLevels {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	levels := entities.LogLevels{
+		Default: s.defaultLevel.String(),
+		Global: entities.LogLevel{
+			Level:     s.levelWriter.Level().String(),
+			ExpiresAt: s.expiration(globalComponentID),
+		},
+		Components: make([]entities.LogLevel, 0),
+	}
+
+	for component, level := range s.levelWriter.ComponentLevels() {
+		levels.Components = append(levels.Components, entities.LogLevel{
+			Component: component,
+			Level:     level.String(),
+			ExpiresAt: s.expiration(component),
+		})
+	}
+
+	sort.Slice(levels.Components, func(i, j int) bool {
+		return levels.Components[i].Component < levels.Components[j].Component
+	})
+
+	return levels
+}
+
+// Tail returns last lines of the log file filtered by level and substring. When cursor is set,
+// lines written after cursor are returned (polling), at most requested number of lines per call.
+func (s *Service) Tail(request entities.TailLogRequest) (result entities.LogTail, err error) {
+	match, err := lineMatcher(request)
+	if err != nil {
+		return result, fmt.Errorf("Tail: %w", err)
+	}
+
+	reader, err := openLogReader(s.logFilePath, request.FileID, request.Cursor)
+	if err != nil {
+		return result, fmt.Errorf("Tail: %w", err)
+	}
+	defer reader.close()
+
+	limit := tailLimit(request)
+	if request.Cursor > 0 || request.FileID != 0 {
+		if result.Lines, err = reader.next(limit, match); err != nil {
+			return result, fmt.Errorf("Tail: %w", err)
+		}
+
+		return newLogTail(reader, result.Lines), nil
+	}
+
+	// keep only last lines
+	var lines []string
+	for {
+		chunk, rErr := reader.next(limit, match)
+		if rErr != nil {
+			return result, fmt.Errorf("Tail: %w", rErr)
+		}
+
+		if len(chunk) == 0 {
+			break
+		}
+
+		lines = append(lines, chunk...)
+		lines = lines[max(0, len(lines)-limit):]
+	}
+
+	return newLogTail(reader, lines), nil
+}
+
+// Follow sends lines written after cursor to publish until ctx is done, log file is kept open
+// between polls and reopened after rotation. Publish error stops following.
+func (s *Service) Follow(ctx context.Context, request entities.TailLogRequest, publish func(tail entities.LogTail) error) (err error) {
+	match, err := lineMatcher(request)
+	if err != nil {
+		return fmt.Errorf("Follow: %w", err)
+	}
+
+	if s.followers.Add(1) > maxFollowers {
+		s.followers.Add(-1)
+		return fmt.Errorf("Follow: %w: %d are active", errs.ErrTooManyLogStreams, maxFollowers)
+	}
+	defer s.followers.Add(-1)
+
+	reader, err := openLogReader(s.logFilePath, request.FileID, request.Cursor)
+	if err != nil {
+		return fmt.Errorf("Follow: %w", err)
+	}
+	defer reader.close()
+
+	ticker := time.NewTicker(followPollPeriod)
+	defer ticker.Stop()
+
+	for {
+		for {
+			lines, rErr := reader.next(tailLimit(request), match)
+			if rErr != nil {
+				return fmt.Errorf("Follow: %w", rErr)
+			}
+
+			if len(lines) == 0 && !reader.rotated {
+				break
+			}
+
+			if err = publish(newLogTail(reader, lines)); err != nil {
+				return fmt.Errorf("Follow: %w", err)
+			}
+			reader.rotated = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// revertLevel is called on ttl expiration, skips revert if level was changed again after timer start.
+func (s *Service) revertLevel(component string, expireAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if actual, ok := s.expireAt[component]; !ok || !actual.Equal(expireAt) {
+		return
+	}
+
+	s.resetLevel(component)
+}
+
+func (s *Service) resetLevel(component string) {
+	s.stopTimer(component)
+	if component == globalComponentID {
+		s.levelWriter.SetLevel(s.defaultLevel)
+	} else {
+		s.levelWriter.DeleteComponentLevel(component)
+	}
+
+	log.Info().
+		Str("component", component).
+		Msg("resetLevel: log level reverted")
+}
+
+func (s *Service) stopTimer(component string) {
+	if timer, ok := s.timers[component]; ok {
+		timer.Stop()
+		delete(s.timers, component)
+		delete(s.expireAt, component)
+	}
+}
+
+func (s *Service) expiration(component string) *time.Time {
+	if expireAt, ok := s.expireAt[component]; ok {
+		return &expireAt
+	}
+
+	return nil
+}
+
+func newLogTail(reader *logReader, lines []string) entities.LogTail {
+	return entities.LogTail{
+		Lines:   lo.Ternary(lines == nil, []string{}, lines),
+		Cursor:  reader.offset,
+		FileID:  reader.fileID,
+		Rotated: reader.rotated,
+	}
+}
+
+func tailLimit(request entities.TailLogRequest) int {
+	if request.Lines == 0 {
+		return defaultTailLines
+	}
+
+	return request.Lines
+}
+
+func lineMatcher(request entities.TailLogRequest) (match func(line []byte) bool, err error) {
+	var minLevel zerolog.Level = zerolog.TraceLevel
+	if request.Level != "" {
+		if minLevel, err = zerolog.ParseLevel(request.Level); err != nil {
+			return nil, fmt.Errorf("lineMatcher: %w", err)
+		}
+	}
+
+	return func(line []byte) bool {
+		return matchLine(line, minLevel, request.Contains)
+	}, nil
+}
+
+func matchLine(line []byte, minLevel zerolog.Level, contains string) bool {
+	if contains != "" && !bytes.Contains(line, []byte(contains)) {
+		return false
+	}
+
+	if minLevel <= zerolog.TraceLevel {
+		return true
+	}
+
+	var entry struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		// not a structured line (e.g. panic trace), keep it
+		return true
+	}
+
+	lvl, err := zerolog.ParseLevel(strings.ToLower(entry.Level))
+	if err != nil {
+		return true
+	}
+
+	return lvl >= minLevel
+}
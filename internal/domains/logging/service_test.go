@@ -0,0 +1,114 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/logging"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func appendLog(t *testing.T, path string, lines ...string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.WriteString(strings.Join(lines, "\n") + "\n")
+	require.NoError(t, err)
+}
+
+func newLoggingService(t *testing.T) (service *logging.Service, logPath string) {
+	globalLevel := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(globalLevel) })
+
+	logPath = filepath.Join(t.TempDir(), "agent.log")
+	writer := logging.NewLevelWriter(zerolog.SyncWriter(&bytes.Buffer{}), zerolog.InfoLevel)
+
+	return logging.NewService(writer, logPath), logPath
+}
+
+func TestService_Tail(t *testing.T) {
+	service, logPath := newLoggingService(t)
+
+	appendLog(t, logPath,
+		`{"level":"info","message":"one"}`,
+		`{"level":"debug","message":"two"}`,
+		`{"level":"error","message":"three"}`,
+	)
+
+	result, err := service.Tail(entities.TailLogRequest{Lines: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"level":"debug","message":"two"}`, `{"level":"error","message":"three"}`}, result.Lines)
+
+	// polling with cursor returns only new lines
+	appendLog(t, logPath, `{"level":"warn","message":"four"}`)
+	next, err := service.Tail(entities.TailLogRequest{Level: "info", Cursor: result.Cursor, FileID: result.FileID})
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"level":"warn","message":"four"}`}, next.Lines)
+	require.False(t, next.Rotated)
+
+	// rotated log starts from the beginning of the new file
+	require.NoError(t, os.Rename(logPath, logPath+".1"))
+	appendLog(t, logPath, `{"level":"info","message":"five"}`)
+	rotated, err := service.Tail(entities.TailLogRequest{Cursor: next.Cursor, FileID: next.FileID})
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"level":"info","message":"five"}`}, rotated.Lines)
+	require.True(t, rotated.Rotated)
+	require.NotEqual(t, next.FileID, rotated.FileID)
+}
+
+func TestService_Follow(t *testing.T) {
+	service, logPath := newLoggingService(t)
+
+	appendLog(t, logPath, `{"level":"info","message":"one"}`)
+	result, err := service.Tail(entities.TailLogRequest{})
+	require.NoError(t, err)
+
+	var (
+		chunks      = make(chan entities.LogTail, 10)
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		done        = make(chan error)
+	)
+	defer cancel()
+
+	go func() {
+		done <- service.Follow(ctx, entities.TailLogRequest{Cursor: result.Cursor, FileID: result.FileID},
+			func(tail entities.LogTail) error {
+				chunks <- tail
+				return nil
+			})
+	}()
+
+	next := func() entities.LogTail {
+		select {
+		case chunk := <-chunks:
+			return chunk
+		case <-ctx.Done():
+			require.FailNow(t, "log chunk is not sent")
+			return entities.LogTail{}
+		}
+	}
+
+	appendLog(t, logPath, `{"level":"info","message":"two"}`)
+	require.Equal(t, []string{`{"level":"info","message":"two"}`}, next().Lines)
+
+	// lines of the rotated file are followed in the new file
+	require.NoError(t, os.Rename(logPath, logPath+".1"))
+	appendLog(t, logPath, `{"level":"info","message":"three"}`)
+	chunk := next()
+	require.True(t, chunk.Rotated)
+	require.Equal(t, []string{`{"level":"info","message":"three"}`}, chunk.Lines)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// logReader reads complete lines of the log file from the remembered offset. Rotated log file
+// (lumberjack renames it and creates a new one) is detected by inode change.
+type logReader struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	fileID  uint64
+	offset  int64  // offset of the first not returned line
+	pending []byte // incomplete last line, written partially
+	rotated bool
+}
+
+// openLogReader opens log file at cursor. Cursor of another file (rotated) or beyond file size
+// starts from the beginning, zero cursor without file id reads only the last tailScanBytes.
+func openLogReader(path string, fileID uint64, cursor int64) (r *logReader, err error) {
+	r = &logReader{path: path}
+	if err = r.open(); err != nil {
+		return nil, fmt.Errorf("openLogReader: %w", err)
+	}
+
+	info, err := r.file.Stat()
+	if err != nil {
+		r.close()
+		return nil, fmt.Errorf("openLogReader: %w", err)
+	}
+
+	skipPartial := false
+	switch {
+	case fileID == 0 && cursor == 0:
+		cursor = max(0, info.Size()-tailScanBytes)
+		skipPartial = cursor > 0
+	case fileID != 0 && fileID != r.fileID, cursor > info.Size():
+		cursor = 0
+		r.rotated = true
+	}
+
+	if err = r.seek(cursor); err != nil {
+		r.close()
+		return nil, fmt.Errorf("openLogReader: %w", err)
+	}
+
+	if skipPartial {
+		// starting in the middle of the line
+		skipped, rErr := r.reader.ReadBytes('\n')
+		r.offset += int64(len(skipped))
+		if rErr != nil && !errors.Is(rErr, io.EOF) {
+			r.close()
+			return nil, fmt.Errorf("openLogReader: %w", rErr)
+		}
+	}
+
+	return r, nil
+}
+
+// next returns up to limit matching lines written after the last call, new log file is opened after rotation.
+func (r *logReader) next(limit int, match func(line []byte) bool) (lines []string, err error) {
+	for len(lines) < limit {
+		line, rErr := r.reader.ReadBytes('\n')
+		if rErr != nil {
+			if !errors.Is(rErr, io.EOF) {
+				return lines, fmt.Errorf("next: %w", rErr)
+			}
+
+			r.pending = append(r.pending, line...)
+
+			reopened, sErr := r.checkRotation()
+			if sErr != nil {
+				return lines, fmt.Errorf("next: %w", sErr)
+			}
+
+			if !reopened {
+				return lines, nil
+			}
+
+			continue
+		}
+
+		if len(r.pending) > 0 {
+			line = append(r.pending, line...)
+			r.pending = nil
+		}
+		r.offset += int64(len(line))
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		if match(line) {
+			lines = append(lines, string(line))
+		}
+	}
+
+	return lines, nil
+}
+
+// checkRotation reopens log file if it was replaced or truncated, it is called when current file is read till the end.
+func (r *logReader) checkRotation() (reopened bool, err error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// new file is not created yet
+			return false, nil
+		}
+
+		return false, fmt.Errorf("checkRotation: %w", err)
+	}
+
+	switch {
+	case statFileID(info) != r.fileID:
+		r.close()
+		if err = r.open(); err != nil {
+			return false, fmt.Errorf("checkRotation: %w", err)
+		}
+
+	case info.Size() < r.offset+int64(len(r.pending)):
+		// truncated in place
+
+	default:
+		return false, nil
+	}
+
+	if err = r.seek(0); err != nil {
+		return false, fmt.Errorf("checkRotation: %w", err)
+	}
+	r.rotated = true
+
+	return true, nil
+}
+
+func (r *logReader) open() (err error) {
+	if r.file, err = os.Open(r.path); err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	info, err := r.file.Stat()
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	r.fileID = statFileID(info)
+
+	return nil
+}
+
+func (r *logReader) seek(offset int64) (err error) {
+	if _, err = r.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+
+	r.offset = offset
+	r.pending = nil
+	if r.reader == nil {
+		r.reader = bufio.NewReaderSize(r.file, 64*1024)
+	} else {
+		r.reader.Reset(r.file)
+	}
+
+	return nil
+}
+
+func (r *logReader) close() {
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+}
+
+// statFileID returns inode of the file, it changes when lumberjack rotates the log.
+func statFileID(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+
+	return 0
+}
@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const maxCallerDepth = 32
+
+// LevelWriter filters log events by the level configured for the package (component) which emitted them.
+// Component is resolved from the call stack only when component overrides are configured.
+type LevelWriter struct {
+	w io.Writer
+
+	mu         sync.RWMutex
+	level      zerolog.Level
+	components map[string]zerolog.Level
+}
+
+func NewLevelWriter(w io.Writer, level zerolog.Level) *LevelWriter {
+	lw := &LevelWriter{
+		w:          w,
+		level:      level,
+		components: make(map[string]zerolog.Level),
+	}
+	lw.updateGlobalLevel()
+
+	return lw
+}
+
+func (w *LevelWriter) Write(p []byte) (n int, err error) {
+	return w.w.Write(p)
+}
+
+func (w *LevelWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	if !w.enabled(level) {
+		return len(p), nil
+	}
+
+	return w.w.Write(p)
+}
+
+// Level returns global log level.
+func (w *LevelWriter) Level() zerolog.Level {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.level
+}
+
+// SetLevel sets global log level.
+func (w *LevelWriter) SetLevel(level zerolog.Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.level = level
+	w.updateGlobalLevel()
+}
+
+// ComponentLevels returns copy of component level overrides.
+func (w *LevelWriter) ComponentLevels() map[string]zerolog.Level {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	result := make(map[string]zerolog.Level, len(w.components))
+	for component, level := range w.components {
+		result[component] = level
+	}
+
+	return result
+}
+
+// SetComponentLevel overrides log level for component (package name or package path suffix).
+func (w *LevelWriter) SetComponentLevel(component string, level zerolog.Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.components[component] = level
+	w.updateGlobalLevel()
+}
+
+// DeleteComponentLevel removes component level override.
+func (w *LevelWriter) DeleteComponentLevel(component string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.components, component)
+	w.updateGlobalLevel()
+}
+
+// updateGlobalLevel lowers zerolog global level to the most verbose configured level,
+// so events needed by component overrides reach the writer. Must be called under lock.
+func (w *LevelWriter) updateGlobalLevel() {
+	minLevel := w.level
+	for _, level := range w.components {
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+
+	zerolog.SetGlobalLevel(minLevel)
+}
+
+func (w *LevelWriter) enabled(level zerolog.Level) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.components) == 0 {
+		return level >= w.level
+	}
+
+	// the most specific (longest) matching component wins
+	var (
+		pkgPath   = callerPackage()
+		matched   string
+		threshold = w.level
+	)
+	for component, componentLevel := range w.components {
+		if (pkgPath == component || strings.HasSuffix(pkgPath, "/"+component)) && len(component) > len(matched) {
+			matched, threshold = component, componentLevel
+		}
+	}
+
+	return level >= threshold
+}
+
+// callerPackage returns package path of the first frame outside of zerolog and this package.
+func callerPackage() string {
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		pkgPath := packagePath(frame.Function)
+		if pkgPath != "" &&
+			!strings.HasPrefix(pkgPath, "github.com/rs/zerolog") &&
+			!strings.HasSuffix(pkgPath, "/internal/domains/logging") {
+			return pkgPath
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packagePath extracts package path from fully qualified function name,
+// e.g. "github.com/org/repo/pkg.(*Service).Method" -> "github.com/org/repo/pkg".
+func packagePath(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+
+	dot := strings.Index(funcName[lastSlash:], ".")
+	if dot < 0 {
+		return funcName
+	}
+
+	return funcName[:lastSlash+dot]
+}
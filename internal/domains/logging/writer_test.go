@@ -0,0 +1,76 @@
+package logging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/logging"
+)
+
+func Test_LevelWriter(t *testing.T) {
+	// level writer lowers global level, restore it for other tests
+	globalLevel := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(globalLevel) })
+
+	testTable := []struct {
+		name            string
+		level           zerolog.Level
+		components      map[string]zerolog.Level
+		expectedWritten bool
+	}{
+		{
+			name:            "debug event filtered by global level",
+			level:           zerolog.InfoLevel,
+			expectedWritten: false,
+		},
+		{
+			name:            "debug event allowed by global level",
+			level:           zerolog.DebugLevel,
+			expectedWritten: true,
+		},
+		{
+			name:  "debug event allowed by component level",
+			level: zerolog.InfoLevel,
+			components: map[string]zerolog.Level{
+				"logging_test": zerolog.DebugLevel,
+			},
+			expectedWritten: true,
+		},
+		{
+			name:  "debug event filtered by component level",
+			level: zerolog.DebugLevel,
+			components: map[string]zerolog.Level{
+				"domains/logging_test": zerolog.WarnLevel,
+			},
+			expectedWritten: false,
+		},
+		{
+			name:  "other component override is ignored",
+			level: zerolog.InfoLevel,
+			components: map[string]zerolog.Level{
+				"lte": zerolog.TraceLevel,
+			},
+			expectedWritten: false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var (
+				buf    bytes.Buffer
+				writer = logging.NewLevelWriter(&buf, testCase.level)
+			)
+			for component, level := range testCase.components {
+				writer.SetComponentLevel(component, level)
+			}
+
+			logger := zerolog.New(writer).Level(zerolog.TraceLevel)
+			logger.Debug().Msg("test message")
+
+			require.Equal(t, testCase.expectedWritten, buf.Len() > 0)
+		})
+	}
+}
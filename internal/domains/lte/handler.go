@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
 	"github.com/go-playground/validator/v10"
@@ -21,20 +22,39 @@ type (
 	ILTEService interface {
 		CollectStats() (stats entities.LTEStats, err error)
 		ResetModem(modemSysPath string) (err error)
+		SIMStatuses() (statuses []entities.LTESIMStatus, err error)
+		SIMStatus(imei string) (status entities.LTESIMStatus, err error)
+		UnlockSIM(imei, pin, puk string) (iccid string, err error)
+		SelectSIMSlot(imei string, slot int) (err error)
+		ApplyAPNProfile(profile entities.APNProfile, connection string) (err error)
+	}
+
+	ILTEHistoryStore interface {
+		List(since time.Time, limit int) (samples []entities.LTESample, err error)
+	}
+
+	ILTESettingsStore interface {
+		Get() (settings entities.LTESettings, err error)
+		Update(fn func(settings *entities.LTESettings) error) (err error)
 	}
 
 	Handler struct {
 		messagePublisher IMessagePublisher
 		lteService       ILTEService
+		historyStore     ILTEHistoryStore
+		settingsStore    ILTESettingsStore
 
 		validate *validator.Validate
 	}
 )
 
-func NewHandler(messagePublisher IMessagePublisher, lteService ILTEService) *Handler {
+func NewHandler(messagePublisher IMessagePublisher, lteService ILTEService, historyStore ILTEHistoryStore,
+	settingsStore ILTESettingsStore) *Handler {
 	return &Handler{
 		messagePublisher: messagePublisher,
 		lteService:       lteService,
+		historyStore:     historyStore,
+		settingsStore:    settingsStore,
 
 		validate: validator.New(),
 	}
@@ -93,3 +113,34 @@ func (h *Handler) ResetModem(request wschat.WebsocketMessage) (err error) {
 
 	return nil
 }
+
+// FetchHistory returns LTE signal and state history collected by monitor.
+func (h *Handler) FetchHistory(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.LTEHistoryRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("FetchHistory: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("FetchHistory: %w", err)
+	}
+
+	samples, err := h.historyStore.List(requestBody.Since, requestBody.Limit)
+	if err != nil {
+		return fmt.Errorf("FetchHistory: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, samples); err != nil {
+		return fmt.Errorf("FetchHistory: %w", err)
+	}
+
+	return nil
+}
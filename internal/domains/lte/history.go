@@ -0,0 +1,102 @@
+package lte
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	historyKeyPrefix    = "lte:history:"
+	historyRetention    = 7 * 24 * time.Hour
+	defaultHistoryLimit = 1000
+)
+
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	// HistoryStore keeps LTE samples in badger, samples expire after retention period.
+	HistoryStore struct {
+		db IBadgerDB
+	}
+)
+
+func NewHistoryStore(db IBadgerDB) *HistoryStore {
+	return &HistoryStore{
+		db: db,
+	}
+}
+
+// Save saves samples to history.
+func (s *HistoryStore) Save(samples []entities.LTESample) (err error) {
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		for _, sample := range samples {
+			value, err := json.Marshal(sample)
+			if err != nil {
+				return err
+			}
+
+			entry := badger.NewEntry(historyKey(sample), value).WithTTL(historyRetention)
+			if err = txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+
+	return nil
+}
+
+// List returns samples saved after since (oldest first), at most limit samples.
+func (s *HistoryStore) List(since time.Time, limit int) (samples []entities.LTESample, err error) {
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+
+	samples = make([]entities.LTESample, 0)
+	if err = s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{
+			PrefetchValues: true,
+			PrefetchSize:   100,
+			Prefix:         []byte(historyKeyPrefix),
+		})
+		defer it.Close()
+
+		seekKey := []byte(historyKeyPrefix)
+		if !since.IsZero() {
+			seekKey = fmt.Appendf(nil, "%s%020d", historyKeyPrefix, since.UnixNano())
+		}
+
+		for it.Seek(seekKey); it.Valid() && len(samples) < limit; it.Next() {
+			var sample entities.LTESample
+			if err := it.Item().Value(func(value []byte) error {
+				return json.Unmarshal(value, &sample)
+			}); err != nil {
+				return err
+			}
+
+			samples = append(samples, sample)
+		}
+
+		return nil
+	}); err != nil {
+		return samples, fmt.Errorf("List: %w", err)
+	}
+
+	return samples, nil
+}
+
+// historyKey builds sortable by time key: prefix + zero padded unix nanoseconds + imei.
+func historyKey(sample entities.LTESample) []byte {
+	return fmt.Appendf(nil, "%s%020d:%s", historyKeyPrefix, sample.Timestamp.UnixNano(), sample.IMEI)
+}
@@ -0,0 +1,468 @@
+package lte
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	DefaultMonitorInterval = 30 * time.Second
+	DefaultStallTimeout    = 5 * time.Minute
+
+	modemStateConnected = "connected"
+	eventSource         = "lte"
+)
+
+const (
+	RecoveryReconnectBearer = "reconnect_bearer"
+	RecoveryUSBReset        = "usb_reset"
+	// RecoveryUSBReauthorize removes modem usb device from the bus and authorizes it back,
+	// modem firmware is reloaded on re-enumeration but its power is not cut.
+	RecoveryUSBReauthorize = "usb_reauthorize"
+	// RecoveryPowerCycle switches modem power off and on.
+	RecoveryPowerCycle = "power_cycle"
+)
+
+// recoverySteps escalation order of modem recovery actions, the last action is repeated.
+var recoverySteps = []string{
+	RecoveryReconnectBearer,
+	RecoveryUSBReset,
+	RecoveryUSBReauthorize,
+	RecoveryPowerCycle,
+}
+
+type (
+	IModemService interface {
+		CollectStats() (stats entities.LTEStats, err error)
+		ResetModem(modemSysPath string) (err error)
+		ReconnectModem(primaryPort string) (err error)
+		ReauthorizeModem(modemSysPath string) (err error)
+		PowerCycleModem(modemID, modemSysPath string) (err error)
+		SIMStatus(imei string) (status entities.LTESIMStatus, err error)
+		UnlockSIM(imei, pin, puk string) (iccid string, err error)
+		SelectSIMSlot(imei string, slot int) (err error)
+	}
+
+	ISettingsStore interface {
+		Get() (settings entities.LTESettings, err error)
+	}
+
+	IHistoryStore interface {
+		Save(samples []entities.LTESample) (err error)
+	}
+
+	IEventService interface {
+		Report(event entities.AgentEvent)
+	}
+
+	// Monitor samples modems periodically, saves samples to history and recovers stalled modems.
+	// It also unlocks sims with saved PIN and switches sim slot according to failover policy.
+	Monitor struct {
+		modemService  IModemService
+		historyStore  IHistoryStore
+		eventService  IEventService
+		settingsStore ISettingsStore
+		interval      time.Duration
+		stallTimeout  time.Duration
+
+		modems map[string]*modemState
+
+		statsMx   sync.RWMutex
+		lastStats entities.LTEStats
+	}
+
+	modemState struct {
+		lastStat     entities.LTEStat
+		stalledSince time.Time
+		step         int
+
+		unregisteredSince time.Time
+		unlockAttempted   bool // saved PIN is entered once per lock to not waste sim retries
+		recoveryTurn      bool // stall recovery goes before sim failover when both are due
+	}
+
+	recoveryEventData struct {
+		IMEI       string `json:"imei"`
+		DevicePath string `json:"devicePath"`
+		State      string `json:"state"`
+		Action     string `json:"action,omitempty"`
+		Attempt    int    `json:"attempt,omitempty"`
+		Error      string `json:"error,omitempty"`
+		FromSlot   int    `json:"fromSlot,omitempty"`
+		ToSlot     int    `json:"toSlot,omitempty"`
+	}
+)
+
+func NewMonitor(modemService IModemService, historyStore IHistoryStore, eventService IEventService,
+	settingsStore ISettingsStore, interval, stallTimeout time.Duration) *Monitor {
+	return &Monitor{
+		modemService:  modemService,
+		historyStore:  historyStore,
+		eventService:  eventService,
+		settingsStore: settingsStore,
+		interval:      interval,
+		stallTimeout:  stallTimeout,
+
+		modems: make(map[string]*modemState),
+	}
+}
+
+// Start starts monitoring, does nothing on devices without modem manager.
+func (m *Monitor) Start(ctx context.Context) {
+	if _, err := exec.LookPath("mmcli"); err != nil {
+		log.Info().Msg("Start: modem manager not found, LTE monitor disabled")
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(time.Now())
+		}
+	}
+}
+
+// LastStats returns modem stats collected on the last check.
+func (m *Monitor) LastStats() entities.LTEStats {
+	m.statsMx.RLock()
+	defer m.statsMx.RUnlock()
+
+	return m.lastStats
+}
+
+// Check runs single monitoring iteration at now.
+func (m *Monitor) Check(now time.Time) {
+	stats, err := m.modemService.CollectStats()
+	if err != nil {
+		log.Debug().Err(err).Msg("Check: collect LTE stats error")
+		return
+	}
+
+	m.statsMx.Lock()
+	m.lastStats = stats
+	m.statsMx.Unlock()
+
+	settings, err := m.settingsStore.Get()
+	if err != nil {
+		log.Error().Err(err).Msg("Check: get LTE settings error")
+	}
+
+	samples := make([]entities.LTESample, 0, len(stats))
+	seen := make(map[string]bool)
+	for _, stat := range stats {
+		samples = append(samples, newSample(now, stat))
+
+		key := modemKey(stat)
+		seen[key] = true
+
+		state, exists := m.modems[key]
+		if !exists {
+			state = new(modemState)
+			m.modems[key] = state
+		}
+		state.lastStat = stat
+
+		m.checkSIMLock(state, settings.SavedPINs)
+		m.checkRegistration(now, state, settings.Failover)
+	}
+
+	// modem disappeared from modem manager during recovery (e.g. stuck after reset) is stalled too,
+	// other disappeared modems (removed, re-keyed by IMEI) and modems lost after the last recovery step are forgotten
+	for key, state := range m.modems {
+		if seen[key] {
+			continue
+		}
+
+		if state.step == 0 || (state.step >= len(recoverySteps) && now.Sub(state.stalledSince) >= m.stallTimeout) {
+			if state.step > 0 {
+				log.Warn().
+					Str("imei", state.lastStat.IMEI).
+					Str("devicePath", state.lastStat.DevicePath).
+					Msg("Check: modem is lost after recovery")
+			}
+
+			delete(m.modems, key)
+			continue
+		}
+
+		m.checkModem(now, state, false)
+	}
+
+	if err = m.historyStore.Save(samples); err != nil {
+		log.Error().Err(err).Msg("Check: save LTE history error")
+	}
+}
+
+// checkRegistration runs sim failover and stall recovery. Both restart modem registration, so only one
+// of them acts per check, they take turns when both are due and the other one waits for its timeout again.
+func (m *Monitor) checkRegistration(now time.Time, state *modemState, policy entities.LTESIMFailover) {
+	var (
+		failover = func() bool {
+			if !m.checkSIMFailover(now, state, policy) {
+				return false
+			}
+
+			if !state.stalledSince.IsZero() {
+				state.stalledSince = now
+			}
+			state.recoveryTurn = true
+
+			return true
+		}
+		recovery = func() bool {
+			if !m.checkModem(now, state, isModemHealthy(state.lastStat)) {
+				return false
+			}
+
+			if !state.unregisteredSince.IsZero() {
+				state.unregisteredSince = now
+			}
+			state.recoveryTurn = false
+
+			return true
+		}
+	)
+
+	if state.recoveryTurn {
+		if !recovery() {
+			failover()
+		}
+		return
+	}
+
+	if !failover() {
+		recovery()
+	}
+}
+
+// checkModem escalates recovery of modem stalled for stall timeout, returns true when recovery action is performed.
+func (m *Monitor) checkModem(now time.Time, state *modemState, healthy bool) (recovered bool) {
+	if healthy {
+		if state.step > 0 {
+			m.report(entities.EventSeverityInfo, "lte_recovered", "LTE modem recovered", state, recoveryEventData{
+				Action:  recoverySteps[min(state.step, len(recoverySteps))-1],
+				Attempt: state.step,
+			})
+		}
+
+		state.stalledSince = time.Time{}
+		state.step = 0
+		return false
+	}
+
+	if state.stalledSince.IsZero() {
+		state.stalledSince = now
+		return false
+	}
+
+	if now.Sub(state.stalledSince) < m.stallTimeout {
+		return false
+	}
+
+	if state.step == 0 {
+		m.report(entities.EventSeverityWarning, "lte_stall_detected",
+			fmt.Sprintf("LTE modem is not connected for %s", now.Sub(state.stalledSince).Round(time.Second)),
+			state, recoveryEventData{},
+		)
+	}
+
+	action := recoverySteps[min(state.step, len(recoverySteps)-1)]
+	state.step++
+	// give modem time to recover after action
+	state.stalledSince = now
+
+	data := recoveryEventData{
+		Action:  action,
+		Attempt: state.step,
+	}
+	if err := m.recover(action, state.lastStat); err != nil {
+		data.Error = err.Error()
+		m.report(entities.EventSeverityError, "lte_recovery_action", "LTE modem recovery action failed", state, data)
+		return true
+	}
+
+	m.report(entities.EventSeverityWarning, "lte_recovery_action", "LTE modem recovery action performed", state, data)
+
+	return true
+}
+
+// checkSIMLock enters saved PIN once when modem sim is locked.
+func (m *Monitor) checkSIMLock(state *modemState, savedPINs map[string]string) {
+	if state.lastStat.State != modemStateLocked {
+		state.unlockAttempted = false
+		return
+	}
+
+	if state.unlockAttempted || len(savedPINs) == 0 {
+		return
+	}
+
+	status, err := m.modemService.SIMStatus(state.lastStat.IMEI)
+	if err != nil {
+		log.Debug().Err(err).Msg("checkSIMLock: get sim status error")
+		return
+	}
+
+	pin, exists := savedPINs[status.ICCID]
+	if status.UnlockRequired != unlockRequiredPIN || !exists {
+		return
+	}
+
+	state.unlockAttempted = true
+	if _, err = m.modemService.UnlockSIM(status.IMEI, pin, ""); err != nil {
+		m.report(entities.EventSeverityError, "lte_sim_unlock", "LTE sim unlock with saved PIN failed", state,
+			recoveryEventData{Error: err.Error()})
+		return
+	}
+
+	m.report(entities.EventSeverityInfo, "lte_sim_unlock", "LTE sim unlocked with saved PIN", state, recoveryEventData{})
+}
+
+// checkSIMFailover switches to the next sim slot of policy when modem is not registered for policy timeout,
+// returns true when switch is attempted.
+func (m *Monitor) checkSIMFailover(now time.Time, state *modemState, policy entities.LTESIMFailover) (switched bool) {
+	if !policy.Enabled || len(policy.Slots) < 2 || isModemRegistered(state.lastStat) {
+		state.unregisteredSince = time.Time{}
+		return false
+	}
+
+	if state.unregisteredSince.IsZero() {
+		state.unregisteredSince = now
+		return false
+	}
+
+	if now.Sub(state.unregisteredSince) < time.Duration(policy.RegistrationTimeoutMinutes)*time.Minute {
+		return false
+	}
+
+	// give new sim time to register
+	state.unregisteredSince = now
+
+	status, err := m.modemService.SIMStatus(state.lastStat.IMEI)
+	if err != nil {
+		log.Debug().Err(err).Msg("checkSIMFailover: get sim status error")
+		return false
+	}
+
+	data := recoveryEventData{
+		FromSlot: status.PrimarySlot,
+		ToSlot:   nextSlot(policy.Slots, status.PrimarySlot),
+	}
+	if err = m.modemService.SelectSIMSlot(status.IMEI, data.ToSlot); err != nil {
+		data.Error = err.Error()
+		m.report(entities.EventSeverityError, "lte_sim_failover", "LTE sim failover failed", state, data)
+		return true
+	}
+
+	m.report(entities.EventSeverityWarning, "lte_sim_failover",
+		fmt.Sprintf("LTE modem is not registered for %d minutes, sim slot switched", policy.RegistrationTimeoutMinutes),
+		state, data,
+	)
+
+	return true
+}
+
+func (m *Monitor) recover(action string, stat entities.LTEStat) (err error) {
+	switch action {
+	case RecoveryReconnectBearer:
+		if lo.IsEmpty(stat.PrimaryPort) {
+			return fmt.Errorf("recover: modem primary port is unknown")
+		}
+
+		err = m.modemService.ReconnectModem(stat.PrimaryPort)
+
+	case RecoveryUSBReset:
+		err = m.modemService.ResetModem(stat.DevicePath)
+
+	case RecoveryUSBReauthorize:
+		err = m.modemService.ReauthorizeModem(stat.DevicePath)
+
+	case RecoveryPowerCycle:
+		err = m.modemService.PowerCycleModem(stat.ModemID, stat.DevicePath)
+
+	default:
+		err = fmt.Errorf("unknown recovery action %s", action)
+	}
+	if err != nil {
+		return fmt.Errorf("recover: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Monitor) report(severity, eventType, message string, state *modemState, data recoveryEventData) {
+	data.IMEI = state.lastStat.IMEI
+	data.DevicePath = state.lastStat.DevicePath
+	data.State = state.lastStat.State
+
+	m.eventService.Report(entities.AgentEvent{
+		Source:   eventSource,
+		Type:     eventType,
+		Severity: severity,
+		Message:  message,
+		Data:     data,
+	})
+}
+
+func isModemHealthy(stat entities.LTEStat) bool {
+	return stat.State == modemStateConnected && len(stat.IPAddresses) > 0
+}
+
+func isModemRegistered(stat entities.LTEStat) bool {
+	return strings.HasPrefix(stat.Radio.RegistrationState, registrationStateHome) ||
+		strings.HasPrefix(stat.Radio.RegistrationState, registrationStateRoaming)
+}
+
+// nextSlot returns slot following current one in slots, the first slot if current is not in slots.
+func nextSlot(slots []int, current int) int {
+	_, index, found := lo.FindIndexOf(slots, func(slot int) bool {
+		return slot == current
+	})
+	if !found {
+		return slots[0]
+	}
+
+	return slots[(index+1)%len(slots)]
+}
+
+func modemKey(stat entities.LTEStat) string {
+	if !lo.IsEmpty(stat.IMEI) {
+		return stat.IMEI
+	}
+
+	return stat.DevicePath
+}
+
+func newSample(now time.Time, stat entities.LTEStat) entities.LTESample {
+	signalQuality, _ := strconv.Atoi(stat.SignalQuality.Value)
+
+	return entities.LTESample{
+		Timestamp:     now,
+		IMEI:          stat.IMEI,
+		State:         stat.State,
+		PowerState:    stat.PowerState,
+		OperatorName:  stat.OperatorName,
+		SignalQuality: signalQuality,
+		Port:          stat.Port,
+		HasIP:         len(stat.IPAddresses) > 0,
+		RSRP:          stat.Radio.RSRP,
+		RSRQ:          stat.Radio.RSRQ,
+		SINR:          stat.Radio.SINR,
+		CellID:        stat.Radio.CellID,
+	}
+}
@@ -0,0 +1,264 @@
+package lte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte/lte_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	testIMEI       = "1"
+	testModemID    = "0"
+	testICCID      = "8970101"
+	testDevicePath = "/sys/devices/usb1/1-1"
+)
+
+type monitorFields struct {
+	modemService  *lte_mocks.MockIModemService
+	historyStore  *lte_mocks.MockIHistoryStore
+	eventService  *lte_mocks.MockIEventService
+	settingsStore *lte_mocks.MockISettingsStore
+
+	stats  entities.LTEStats
+	events []string
+}
+
+func newMonitorFields(t *testing.T, stats entities.LTEStats, settings entities.LTESettings) *monitorFields {
+	f := &monitorFields{
+		modemService:  lte_mocks.NewMockIModemService(t),
+		historyStore:  lte_mocks.NewMockIHistoryStore(t),
+		eventService:  lte_mocks.NewMockIEventService(t),
+		settingsStore: lte_mocks.NewMockISettingsStore(t),
+
+		stats: stats,
+	}
+
+	f.modemService.EXPECT().
+		CollectStats().
+		RunAndReturn(func() (entities.LTEStats, error) {
+			return f.stats, nil
+		}).
+		Maybe()
+	f.settingsStore.EXPECT().
+		Get().
+		Return(settings, nil).
+		Maybe()
+	f.historyStore.EXPECT().
+		Save(mock.Anything).
+		Return(nil).
+		Maybe()
+	f.eventService.EXPECT().
+		Report(mock.Anything).
+		Run(func(event entities.AgentEvent) {
+			f.events = append(f.events, event.Type)
+		}).
+		Maybe()
+
+	return f
+}
+
+func (f *monitorFields) newMonitor(stallTimeout time.Duration) *lte.Monitor {
+	return lte.NewMonitor(f.modemService, f.historyStore, f.eventService, f.settingsStore, time.Minute, stallTimeout)
+}
+
+func TestMonitor_Check(t *testing.T) {
+	var (
+		f = newMonitorFields(t, entities.LTEStats{
+			{ModemID: testModemID, IMEI: testIMEI, State: "registered", PrimaryPort: "cdc-wdm0", DevicePath: testDevicePath},
+		}, entities.LTESettings{})
+		monitor = f.newMonitor(5 * time.Minute)
+		now     = time.Now()
+		actions []string
+	)
+
+	f.modemService.EXPECT().
+		ReconnectModem("cdc-wdm0").
+		RunAndReturn(func(string) error {
+			actions = append(actions, lte.RecoveryReconnectBearer)
+			return nil
+		}).
+		Once()
+	f.modemService.EXPECT().
+		ResetModem(testDevicePath).
+		RunAndReturn(func(string) error {
+			actions = append(actions, lte.RecoveryUSBReset)
+			return nil
+		}).
+		Once()
+	f.modemService.EXPECT().
+		ReauthorizeModem(testDevicePath).
+		RunAndReturn(func(string) error {
+			actions = append(actions, lte.RecoveryUSBReauthorize)
+			return nil
+		}).
+		Once()
+	f.modemService.EXPECT().
+		PowerCycleModem(testModemID, testDevicePath).
+		RunAndReturn(func(string, string) error {
+			actions = append(actions, lte.RecoveryPowerCycle)
+			return nil
+		}).
+		Twice()
+
+	// stalled modem: escalation every stall timeout, the last action is repeated
+	for i := range 6 {
+		monitor.Check(now.Add(time.Duration(i) * 5 * time.Minute))
+	}
+	require.Equal(t, []string{
+		lte.RecoveryReconnectBearer,
+		lte.RecoveryUSBReset,
+		lte.RecoveryUSBReauthorize,
+		lte.RecoveryPowerCycle,
+		lte.RecoveryPowerCycle,
+	}, actions)
+
+	// recovered modem
+	f.stats[0].State = "connected"
+	f.stats[0].IPAddresses = []string{"10.0.0.2/30"}
+	monitor.Check(now.Add(time.Hour))
+
+	require.Equal(t, "lte_stall_detected", f.events[0])
+	require.Equal(t, "lte_recovered", f.events[len(f.events)-1])
+}
+
+func TestMonitor_Check_disappearedModem(t *testing.T) {
+	var (
+		f = newMonitorFields(t, entities.LTEStats{
+			{ModemID: testModemID, IMEI: testIMEI, State: "registered", PrimaryPort: "cdc-wdm0", DevicePath: testDevicePath},
+		}, entities.LTESettings{})
+		monitor = f.newMonitor(5 * time.Minute)
+		now     = time.Now()
+	)
+
+	f.modemService.EXPECT().
+		ReconnectModem("cdc-wdm0").
+		Return(nil).
+		Once()
+	f.modemService.EXPECT().
+		ResetModem(testDevicePath).
+		Return(nil).
+		Once()
+	f.modemService.EXPECT().
+		ReauthorizeModem(testDevicePath).
+		Return(nil).
+		Once()
+	f.modemService.EXPECT().
+		PowerCycleModem(testModemID, testDevicePath).
+		Return(nil).
+		Once()
+
+	// modem disappeared during recovery is still recovered, it is forgotten after the last recovery step
+	monitor.Check(now)
+	monitor.Check(now.Add(5 * time.Minute))
+	f.stats = nil
+	for i := 2; i < 8; i++ {
+		monitor.Check(now.Add(time.Duration(i) * 5 * time.Minute))
+	}
+
+	// forgotten modem starts from the first recovery step
+	f.stats = entities.LTEStats{
+		{ModemID: testModemID, IMEI: testIMEI, State: "registered", PrimaryPort: "cdc-wdm0", DevicePath: testDevicePath},
+	}
+	f.modemService.EXPECT().
+		ReconnectModem("cdc-wdm0").
+		Return(nil).
+		Once()
+	monitor.Check(now.Add(2 * time.Hour))
+	monitor.Check(now.Add(3 * time.Hour))
+}
+
+func TestMonitor_Check_SIM(t *testing.T) {
+	var (
+		f = newMonitorFields(t, entities.LTEStats{
+			{IMEI: testIMEI, State: "locked", DevicePath: testDevicePath},
+		}, entities.LTESettings{
+			SavedPINs: map[string]string{testICCID: "1234"},
+			Failover: entities.LTESIMFailover{
+				Enabled:                    true,
+				RegistrationTimeoutMinutes: 10,
+				Slots:                      []int{1, 2},
+			},
+		})
+		monitor = f.newMonitor(time.Hour)
+		now     = time.Now()
+		slot    = 1
+	)
+
+	f.modemService.EXPECT().
+		SIMStatus(testIMEI).
+		RunAndReturn(func(imei string) (entities.LTESIMStatus, error) {
+			return entities.LTESIMStatus{IMEI: imei, PrimarySlot: slot, Slots: 2, ICCID: testICCID, UnlockRequired: "sim-pin"}, nil
+		}).
+		Maybe()
+	f.modemService.EXPECT().
+		SelectSIMSlot(testIMEI, mock.Anything).
+		RunAndReturn(func(_ string, toSlot int) error {
+			slot = toSlot
+			return nil
+		}).
+		Times(2)
+
+	// saved PIN is entered once per lock
+	f.modemService.EXPECT().
+		UnlockSIM(testIMEI, "1234", "").
+		Return(testICCID, nil).
+		Once()
+	monitor.Check(now)
+	monitor.Check(now.Add(time.Minute))
+
+	// not registered for policy timeout: switch to the next slot and back
+	monitor.Check(now.Add(11 * time.Minute))
+	require.Equal(t, 2, slot)
+	monitor.Check(now.Add(22 * time.Minute))
+	require.Equal(t, 1, slot)
+
+	// registered modem
+	f.stats[0].State = "registered"
+	f.stats[0].Radio.RegistrationState = "home"
+	monitor.Check(now.Add(33 * time.Minute))
+	monitor.Check(now.Add(44 * time.Minute))
+	require.Equal(t, 1, slot)
+	require.Contains(t, f.events, "lte_sim_failover")
+}
+
+func TestMonitor_Check_failoverAndRecovery(t *testing.T) {
+	var (
+		f = newMonitorFields(t, entities.LTEStats{
+			{IMEI: testIMEI, State: "searching", PrimaryPort: "cdc-wdm0", DevicePath: testDevicePath},
+		}, entities.LTESettings{
+			Failover: entities.LTESIMFailover{
+				Enabled:                    true,
+				RegistrationTimeoutMinutes: 5,
+				Slots:                      []int{1, 2},
+			},
+		})
+		monitor = f.newMonitor(5 * time.Minute)
+		now     = time.Now()
+	)
+
+	f.modemService.EXPECT().
+		SIMStatus(testIMEI).
+		Return(entities.LTESIMStatus{IMEI: testIMEI, PrimarySlot: 1, Slots: 2}, nil).
+		Maybe()
+
+	// both timeouts expire on the same check: sim is switched, recovery waits for stall timeout again
+	f.modemService.EXPECT().
+		SelectSIMSlot(testIMEI, 2).
+		Return(nil).
+		Once()
+	monitor.Check(now)
+	monitor.Check(now.Add(5 * time.Minute))
+
+	// both timeouts expire again: failover and recovery take turns
+	f.modemService.EXPECT().
+		ReconnectModem("cdc-wdm0").
+		Return(nil).
+		Once()
+	monitor.Check(now.Add(10 * time.Minute))
+}
@@ -0,0 +1,262 @@
+package lte
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell/commands"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	signalRefreshRateSeconds = 10
+	registrationStateHome    = "home"
+	registrationStateRoaming = "roaming"
+)
+
+type (
+	signalInfo struct {
+		Modem struct {
+			Signal struct {
+				LTE  signalValues `json:"lte"`
+				NR5G signalValues `json:"5g"`
+			} `json:"signal"`
+		} `json:"modem"`
+	}
+
+	signalValues struct {
+		RSRP string `json:"rsrp"`
+		RSRQ string `json:"rsrq"`
+		RSSI string `json:"rssi"`
+		SNR  string `json:"snr"`
+	}
+
+	cellInfo struct {
+		Modem struct {
+			CellInfo []string `json:"cell-info"` //nolint:tagliatelle // network manager API
+		} `json:"modem"`
+	}
+
+	// earfcnRange downlink EARFCN range of LTE band (3GPP TS 36.101).
+	earfcnRange struct {
+		band     int
+		low, top int
+	}
+
+	locationInfo struct {
+		Modem struct {
+			Location struct {
+				Field3GPP struct {
+					CID string `json:"cid"`
+					TAC string `json:"tac"`
+					LAC string `json:"lac"`
+				} `json:"3gpp"`
+			} `json:"location"`
+		} `json:"modem"`
+	}
+)
+
+// earfcnRanges used to resolve serving band, current bands of modem are only allowed ones.
+var earfcnRanges = []earfcnRange{
+	{band: 1, low: 0, top: 599},
+	{band: 2, low: 600, top: 1199},
+	{band: 3, low: 1200, top: 1949},
+	{band: 4, low: 1950, top: 2399},
+	{band: 5, low: 2400, top: 2649},
+	{band: 7, low: 2750, top: 3449},
+	{band: 8, low: 3450, top: 3799},
+	{band: 11, low: 4750, top: 4949},
+	{band: 12, low: 5010, top: 5179},
+	{band: 13, low: 5180, top: 5279},
+	{band: 14, low: 5280, top: 5379},
+	{band: 17, low: 5730, top: 5849},
+	{band: 18, low: 5850, top: 5999},
+	{band: 19, low: 6000, top: 6149},
+	{band: 20, low: 6150, top: 6449},
+	{band: 21, low: 6450, top: 6599},
+	{band: 25, low: 8040, top: 8689},
+	{band: 26, low: 8690, top: 9039},
+	{band: 28, low: 9210, top: 9659},
+	{band: 29, low: 9660, top: 9769},
+	{band: 30, low: 9770, top: 9869},
+	{band: 31, low: 9870, top: 9919},
+	{band: 32, low: 9920, top: 10359},
+	{band: 38, low: 37750, top: 38249},
+	{band: 39, low: 38250, top: 38649},
+	{band: 40, low: 38650, top: 39649},
+	{band: 41, low: 39650, top: 41589},
+	{band: 42, low: 41590, top: 43589},
+	{band: 43, low: 43590, top: 45589},
+	{band: 46, low: 46790, top: 54539},
+	{band: 48, low: 55240, top: 56739},
+	{band: 66, low: 66436, top: 67335},
+	{band: 71, low: 68586, top: 68935},
+}
+
+// collectRadioStats collects extended signal values, serving cell location and band of modem.
+func (s *Service) collectRadioStats(modemID string, radio *entities.LTERadioStat) {
+	s.setupModem(modemID)
+
+	output, err := s.shellService.ExecOutput(
+		commands.NewCustomCmd(fmt.Sprintf("mmcli --modem=%s --signal-get --output-json", modemID)),
+	)
+	if err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: get signal error")
+	} else if err = parseSignalInfo(output, radio); err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: parse signal error")
+	}
+
+	output, err = s.shellService.ExecOutput(
+		commands.NewCustomCmd(fmt.Sprintf("mmcli --modem=%s --location-get --output-json", modemID)),
+	)
+	if err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: get location error")
+		return
+	}
+
+	if err = parseLocationInfo(output, radio); err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: parse location error")
+	}
+
+	// cell info is available since modem manager 1.20
+	output, err = s.shellService.ExecOutput(
+		commands.NewCustomCmd(fmt.Sprintf("mmcli --modem=%s --get-cell-info --output-json", modemID)),
+	)
+	if err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: get cell info error")
+		return
+	}
+
+	if err = parseCellInfo(output, radio); err != nil {
+		log.Debug().Err(err).Str("modem", modemID).Msg("collectRadioStats: parse cell info error")
+	}
+}
+
+// setupModem enables extended signal refresh and 3gpp location gathering once per modem.
+// Not all modems support them, errors are only logged.
+func (s *Service) setupModem(modemID string) {
+	s.modemSetupMx.Lock()
+	defer s.modemSetupMx.Unlock()
+
+	if s.modemSetup[modemID] {
+		return
+	}
+
+	for _, cmd := range []string{
+		fmt.Sprintf("mmcli --modem=%s --signal-setup=%d", modemID, signalRefreshRateSeconds),
+		fmt.Sprintf("mmcli --modem=%s --location-enable-3gpp", modemID),
+	} {
+		if err := s.shellService.Exec(commands.NewCustomCmd(cmd)); err != nil {
+			log.Debug().Err(err).Str("modem", modemID).Msg("setupModem: modem setup error")
+		}
+	}
+
+	s.modemSetup[modemID] = true
+}
+
+func parseSignalInfo(output []byte, radio *entities.LTERadioStat) (err error) {
+	var info signalInfo
+	if err = json.Unmarshal(output, &info); err != nil {
+		return fmt.Errorf("parseSignalInfo: %w", err)
+	}
+
+	// prefer LTE values, fallback to 5G for NR modems
+	for _, values := range []signalValues{info.Modem.Signal.LTE, info.Modem.Signal.NR5G} {
+		if radio.RSRP == nil {
+			radio.RSRP = parseSignalValue(values.RSRP)
+		}
+		if radio.RSRQ == nil {
+			radio.RSRQ = parseSignalValue(values.RSRQ)
+		}
+		if radio.SINR == nil {
+			radio.SINR = parseSignalValue(values.SNR)
+		}
+		if radio.RSSI == nil {
+			radio.RSSI = parseSignalValue(values.RSSI)
+		}
+	}
+
+	return nil
+}
+
+func parseLocationInfo(output []byte, radio *entities.LTERadioStat) (err error) {
+	var info locationInfo
+	if err = json.Unmarshal(output, &info); err != nil {
+		return fmt.Errorf("parseLocationInfo: %w", err)
+	}
+
+	location := info.Modem.Location.Field3GPP
+	radio.CellID = emptyIfUnknown(location.CID)
+	radio.TAC = emptyIfUnknown(location.TAC)
+	if radio.TAC == "" {
+		radio.TAC = emptyIfUnknown(location.LAC)
+	}
+
+	return nil
+}
+
+// parseCellInfo resolves band of serving LTE cell by its EARFCN, band stays empty when it is unknown.
+// Every cell is reported by mmcli as "cell type: lte, serving: yes, ..., earfcn: 1300, ..." string.
+func parseCellInfo(output []byte, radio *entities.LTERadioStat) (err error) {
+	var info cellInfo
+	if err = json.Unmarshal(output, &info); err != nil {
+		return fmt.Errorf("parseCellInfo: %w", err)
+	}
+
+	for _, cell := range info.Modem.CellInfo {
+		fields := make(map[string]string)
+		for _, field := range strings.Split(cell, ",") {
+			if key, value, found := strings.Cut(field, ":"); found {
+				fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+
+		if fields["cell type"] != "lte" || fields["serving"] != "yes" {
+			continue
+		}
+
+		earfcn, convErr := strconv.Atoi(fields["earfcn"])
+		if convErr != nil {
+			return nil
+		}
+
+		for _, r := range earfcnRanges {
+			if earfcn >= r.low && earfcn <= r.top {
+				radio.Band = fmt.Sprintf("eutran-%d", r.band)
+				return nil
+			}
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// parseSignalValue parses mmcli signal value, "--" means value is not available.
+func parseSignalValue(value string) *float64 {
+	value = emptyIfUnknown(value)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &parsed
+}
+
+func emptyIfUnknown(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "--" {
+		return ""
+	}
+
+	return value
+}
@@ -0,0 +1,48 @@
+package lte
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func Test_parseRadioInfo(t *testing.T) {
+	signalOutput := []byte(`{"modem":{"signal":{"refresh":{"rate":"10"},
+"lte":{"error-rate":"--","rsrp":"-95.00","rsrq":"-11.00","rssi":"-65.00","snr":"9.40"},
+"5g":{"error-rate":"--","rsrp":"--","rsrq":"--","snr":"--"}}}}`)
+	locationOutput := []byte(`{"modem":{"location":{"3gpp":{"cid":"01A2B3C","lac":"FFFE","mcc":"250","mnc":"01","tac":"00ABCD"},
+"gps":{"altitude":"--","latitude":"--","longitude":"--"}}}}`)
+
+	var radio entities.LTERadioStat
+	require.NoError(t, parseSignalInfo(signalOutput, &radio))
+	require.NoError(t, parseLocationInfo(locationOutput, &radio))
+
+	require.InDelta(t, -95.0, *radio.RSRP, 0.001)
+	require.InDelta(t, -11.0, *radio.RSRQ, 0.001)
+	require.InDelta(t, 9.4, *radio.SINR, 0.001)
+	require.InDelta(t, -65.0, *radio.RSSI, 0.001)
+	require.Equal(t, "01A2B3C", radio.CellID)
+	require.Equal(t, "00ABCD", radio.TAC)
+
+	// values are not available
+	radio = entities.LTERadioStat{}
+	require.NoError(t, parseSignalInfo([]byte(`{"modem":{"signal":{"lte":{"rsrp":"--"}}}}`), &radio))
+	require.Nil(t, radio.RSRP)
+}
+
+func Test_parseCellInfo(t *testing.T) {
+	output := []byte(`{"modem":{"cell-info":[
+"cell type: lte, serving: no, pci: 12, earfcn: 6300",
+"cell type: lte, serving: yes, ci: 01A2B3C, pci: 301, earfcn: 1300, rsrp: -95.00, tac: 00ABCD"]}}`)
+
+	var radio entities.LTERadioStat
+	require.NoError(t, parseCellInfo(output, &radio))
+	require.Equal(t, "eutran-3", radio.Band)
+
+	// serving cell is not reported
+	radio = entities.LTERadioStat{}
+	require.NoError(t, parseCellInfo([]byte(`{"modem":{"cell-info":["cell type: lte, serving: no, earfcn: 1300"]}}`), &radio))
+	require.Empty(t, radio.Band)
+}
@@ -9,17 +9,25 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell/commands"
 	"github.com/samber/lo"
 
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/shellcmd"
 )
 
 const (
-	idVendorFile  = "idVendor"
-	idProductFile = "idProduct"
+	idVendorFile   = "idVendor"
+	idProductFile  = "idProduct"
+	authorizedFile = "authorized"
+
+	reauthorizeDelay = 5 * time.Second
+	powerOffSeconds  = "5"
 )
 
 type (
@@ -30,44 +38,33 @@ type (
 
 	Service struct {
 		shellService IShellService
+
+		modemSetupMx sync.Mutex
+		modemSetup   map[string]bool // modems with enabled extended signal and location
 	}
 )
 
 func NewService(shellService IShellService) *Service {
 	return &Service{
 		shellService: shellService,
+		modemSetup:   make(map[string]bool),
 	}
 }
 
 func (s *Service) CollectStats() (stats entities.LTEStats, err error) {
 	// get list of modems
-	modemListCmd := commands.NewCustomCmd("mmcli -L --output-json")
-	output, err := s.shellService.ExecOutput(modemListCmd)
+	modemIDs, err := s.listModemIDs()
 	if err != nil {
 		return stats, fmt.Errorf("CollectStats: %w", err)
 	}
 
-	var modemList struct {
-		ModemList []string `json:"modem-list"` //nolint:tagliatelle // network manager API
-	}
-	if err = json.Unmarshal(output, &modemList); err != nil {
-		return stats, fmt.Errorf("CollectStats: %w", err)
-	}
-
-	modemIDs := make([]string, 0, len(modemList.ModemList))
-	for _, modem := range modemList.ModemList {
-		parts := strings.Split(modem, "/")
-		if len(parts) > 0 {
-			modemIDs = append(modemIDs, parts[len(parts)-1])
-		}
-	}
-
 	var modemInfo struct {
 		Modem struct {
 			Field3GPP struct {
-				IMEI         string `json:"imei"`
-				OperatorCode string `json:"operator-code"` //nolint:tagliatelle // network manager API
-				OperatorName string `json:"operator-name"` //nolint:tagliatelle // network manager API
+				IMEI              string `json:"imei"`
+				OperatorCode      string `json:"operator-code"`      //nolint:tagliatelle // network manager API
+				OperatorName      string `json:"operator-name"`      //nolint:tagliatelle // network manager API
+				RegistrationState string `json:"registration-state"` //nolint:tagliatelle // network manager API
 			} `json:"3gpp"`
 			Generic struct {
 				Model         string   `json:"model"`
@@ -79,11 +76,18 @@ func (s *Service) CollectStats() (stats entities.LTEStats, err error) {
 					Recent string `json:"recent"`
 					Value  string `json:"value"`
 				} `json:"signal-quality"` //nolint:tagliatelle // network manager API
-				PrimaryPort string `json:"primary-port"` //nolint:tagliatelle // network manager API
+				PrimaryPort        string   `json:"primary-port"`        //nolint:tagliatelle // network manager API
+				AccessTechnologies []string `json:"access-technologies"` //nolint:tagliatelle // network manager API
+				CurrentBands       []string `json:"current-bands"`       //nolint:tagliatelle // network manager API
 			} `json:"generic"`
 		} `json:"modem"`
 	}
+	var output []byte
 	for _, modemID := range modemIDs {
+		modemInfo.Modem.Generic.Ports = nil
+		modemInfo.Modem.Generic.AccessTechnologies = nil
+		modemInfo.Modem.Generic.CurrentBands = nil
+
 		// get modem info
 		modemInfoCmd := commands.NewCustomCmd(fmt.Sprintf("mmcli --modem=%s --output-json", modemID))
 		if output, err = s.shellService.ExecOutput(modemInfoCmd); err != nil {
@@ -106,6 +110,7 @@ func (s *Service) CollectStats() (stats entities.LTEStats, err error) {
 
 		// save modem info
 		stat := entities.LTEStat{
+			ModemID:      modemID,
 			IMEI:         modemInfo.Modem.Field3GPP.IMEI,
 			OperatorCode: modemInfo.Modem.Field3GPP.OperatorCode,
 			OperatorName: modemInfo.Modem.Field3GPP.OperatorName,
@@ -114,12 +119,22 @@ func (s *Service) CollectStats() (stats entities.LTEStats, err error) {
 			State:        modemInfo.Modem.Generic.State,
 			DevicePath:   modemInfo.Modem.Generic.Device,
 			Port:         port,
+			PrimaryPort:  modemInfo.Modem.Generic.PrimaryPort,
 			SignalQuality: entities.SignalQualityStat{
 				Recent: modemInfo.Modem.Generic.SignalQuality.Recent,
 				Value:  modemInfo.Modem.Generic.SignalQuality.Value,
 			},
+			Radio: entities.LTERadioStat{
+				AccessTechnology:  strings.Join(modemInfo.Modem.Generic.AccessTechnologies, ","),
+				RegistrationState: modemInfo.Modem.Field3GPP.RegistrationState,
+				Roaming:           modemInfo.Modem.Field3GPP.RegistrationState == registrationStateRoaming,
+				Bands:             modemInfo.Modem.Generic.CurrentBands,
+			},
 		}
 
+		// get extended signal and serving cell info, not supported by some modems
+		s.collectRadioStats(modemID, &stat.Radio)
+
 		// get interface info
 		primaryPort := modemInfo.Modem.Generic.PrimaryPort
 		if !lo.IsEmpty(primaryPort) {
@@ -167,6 +182,74 @@ func (s *Service) ResetModem(modemSysPath string) (err error) {
 	return nil
 }
 
+// ReconnectModem reconnects modem data bearer through network manager.
+func (s *Service) ReconnectModem(primaryPort string) (err error) {
+	// device may be already disconnected
+	_ = s.shellService.Exec(commands.NewCustomCmd(fmt.Sprintf("nmcli device disconnect %s", primaryPort)))
+
+	if err = s.shellService.Exec(commands.NewCustomCmd(fmt.Sprintf("nmcli device connect %s", primaryPort))); err != nil {
+		return fmt.Errorf("ReconnectModem: %w", err)
+	}
+
+	return nil
+}
+
+// ReauthorizeModem de-authorizes modem usb device (driver is unbound and device is removed from the bus)
+// and authorizes it back. It is not a power cycle: modem stays powered by the usb port.
+func (s *Service) ReauthorizeModem(modemSysPath string) (err error) {
+	authorizedPath := filepath.Join(modemSysPath, authorizedFile)
+	if err = os.WriteFile(authorizedPath, []byte("0"), constants.FilePerm); err != nil {
+		return fmt.Errorf("ReauthorizeModem: %w", err)
+	}
+
+	time.Sleep(reauthorizeDelay)
+
+	if err = os.WriteFile(authorizedPath, []byte("1"), constants.FilePerm); err != nil {
+		return fmt.Errorf("ReauthorizeModem: %w", err)
+	}
+
+	return nil
+}
+
+// PowerCycleModem cuts modem power. Power of the usb port is switched off and on by uhubctl, it works
+// for modems gone from modem manager too. Hubs without per-port power switching fall back to modem
+// power state switched through modem manager.
+func (s *Service) PowerCycleModem(modemID, modemSysPath string) (err error) {
+	hub, port, err := usbPortLocation(modemSysPath)
+	if err == nil {
+		cycleCmd := shellcmd.New("uhubctl", "--location", hub, "--ports", port, "--action", "cycle", "--delay", powerOffSeconds)
+		if err = s.shellService.Exec(cycleCmd); err == nil {
+			return nil
+		}
+	}
+
+	if lo.IsEmpty(modemID) {
+		return fmt.Errorf("PowerCycleModem: %w", err)
+	}
+
+	if err = s.shellService.Exec(shellcmd.New("mmcli", "--modem="+modemID, "--set-power-state-off")); err != nil {
+		return fmt.Errorf("PowerCycleModem: %w", err)
+	}
+
+	if err = s.shellService.Exec(shellcmd.New("mmcli", "--modem="+modemID, "--set-power-state-on")); err != nil {
+		return fmt.Errorf("PowerCycleModem: %w", err)
+	}
+
+	return nil
+}
+
+// usbPortLocation returns hub location and port of usb device by its sysfs path,
+// e.g. device 1-1.4 is connected to port 4 of hub 1-1 and device 1-2 to port 2 of root hub 1.
+func usbPortLocation(modemSysPath string) (hub, port string, err error) {
+	device := filepath.Base(modemSysPath)
+	separator := strings.LastIndexAny(device, "-.")
+	if separator <= 0 || separator == len(device)-1 {
+		return "", "", fmt.Errorf("usbPortLocation: %s is not usb port device", device)
+	}
+
+	return device[:separator], device[separator+1:], nil
+}
+
 func (s *Service) parseInterfaceInfo(cmdOutput []byte, stat *entities.LTEStat) (err error) {
 	scanner := bufio.NewScanner(bytes.NewReader(cmdOutput))
 	for scanner.Scan() {
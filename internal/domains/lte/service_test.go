@@ -0,0 +1,128 @@
+package lte_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte/lte_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func commandLine(cmd shell.ICommand) string {
+	return strings.Join(append([]string{cmd.Name()}, cmd.Args()...), " ")
+}
+
+func TestService_PowerCycleModem(t *testing.T) {
+	var (
+		shellService = lte_mocks.NewMockIShellService(t)
+		service      = lte.NewService(shellService)
+		commands     []string
+		uhubctlErr   error
+	)
+
+	shellService.EXPECT().
+		Exec(mock.Anything).
+		RunAndReturn(func(cmd shell.ICommand) error {
+			commands = append(commands, commandLine(cmd))
+			if cmd.Name() == "uhubctl" {
+				return uhubctlErr
+			}
+			return nil
+		})
+
+	// usb port power is cycled
+	require.NoError(t, service.PowerCycleModem("0", "/sys/devices/platform/usb1/1-1/1-1.4"))
+	require.Equal(t, []string{"uhubctl --location 1-1 --ports 4 --action cycle --delay 5"}, commands)
+
+	// hub without power switching: modem power state is switched by modem manager
+	commands = nil
+	uhubctlErr = errors.New("no compatible devices detected")
+	require.NoError(t, service.PowerCycleModem("0", "/sys/devices/platform/usb1/1-2"))
+	require.Equal(t, []string{
+		"uhubctl --location 1 --ports 2 --action cycle --delay 5",
+		"mmcli --modem=0 --set-power-state-off",
+		"mmcli --modem=0 --set-power-state-on",
+	}, commands)
+
+	// modem is gone from modem manager
+	commands = nil
+	require.Error(t, service.PowerCycleModem("", "/sys/devices/platform/usb1/1-2"))
+	require.Len(t, commands, 1)
+}
+
+func TestService_UnlockSIM(t *testing.T) {
+	var (
+		shellService = lte_mocks.NewMockIShellService(t)
+		service      = lte.NewService(shellService)
+		outputs      = map[string]string{
+			"mmcli -L --output-json": `{"modem-list":["/org/freedesktop/ModemManager1/Modem/0"]}`,
+			"mmcli --modem=0 --output-json": `{"modem":{"3gpp":{"imei":"1"},"generic":{"sim":"/org/freedesktop/ModemManager1/SIM/3",
+"unlock-required":"sim-pin"}}}`,
+			"mmcli --sim=3 --output-json": `{"sim":{"properties":{"iccid":"8970101","imsi":"--"}}}`,
+		}
+	)
+
+	shellService.EXPECT().
+		ExecOutput(mock.Anything).
+		RunAndReturn(func(cmd shell.ICommand) ([]byte, error) {
+			return []byte(outputs[commandLine(cmd)]), nil
+		})
+
+	// PIN is passed as a single argument
+	shellService.EXPECT().
+		Exec(mock.MatchedBy(func(cmd shell.ICommand) bool {
+			return cmd.Name() == "mmcli" && slices.Equal(cmd.Args(), []string{"--sim=3", "--puk=12345678", "--pin=1 2"})
+		})).
+		Return(nil).
+		Once()
+
+	iccid, err := service.UnlockSIM("1", "1 2", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, "8970101", iccid)
+}
+
+func TestService_ApplyAPNProfile(t *testing.T) {
+	var (
+		shellService = lte_mocks.NewMockIShellService(t)
+		service      = lte.NewService(shellService)
+		commands     [][]string
+	)
+
+	shellService.EXPECT().
+		ExecOutput(mock.Anything).
+		Return([]byte("Wired connection 1:802-3-ethernet\nlte\\:main:gsm\n"), nil).
+		Once()
+	shellService.EXPECT().
+		Exec(mock.Anything).
+		RunAndReturn(func(cmd shell.ICommand) error {
+			commands = append(commands, append([]string{cmd.Name()}, cmd.Args()...))
+			return nil
+		})
+
+	require.NoError(t, service.ApplyAPNProfile(entities.APNProfile{
+		Name:     "main",
+		APN:      "internet",
+		User:     "user",
+		Password: "pass word",
+		IPType:   entities.APNIPTypeIPv4,
+	}, ""))
+	require.Equal(t, [][]string{
+		{
+			"nmcli", "connection", "modify", "lte:main",
+			"gsm.apn", "internet",
+			"gsm.username", "user",
+			"gsm.password", "pass word",
+			"gsm.password-flags", "0",
+			"ipv4.method", "auto",
+			"ipv6.method", "ignore",
+		},
+		{"nmcli", "connection", "up", "lte:main"},
+	}, commands)
+}
@@ -0,0 +1,235 @@
+package lte
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	settingsKey = "lte:settings"
+	pinKeySize  = 32 // AES-256
+	pinKeyPerm  = 0600
+)
+
+// SettingsStore keeps agent side LTE settings (APN profiles, saved PINs, sim failover policy) in badger.
+// They are not part of device config: config schema is shared with orchestrator through sdwan-lib and
+// describes LTE port by APN server only, while these settings belong to the sims inserted in this device
+// (PINs are bound to sim ICCID) and must survive config replacement and drift remediation. Active sim slot
+// is not stored, it is kept by modem. Saved PINs and APN passwords are encrypted with device local key,
+// so they are not readable from badger files.
+type SettingsStore struct {
+	db         IBadgerDB
+	pinKeyPath string
+
+	updateMx sync.Mutex
+
+	pinKeyMx sync.Mutex
+	pinKey   []byte
+}
+
+func NewSettingsStore(db IBadgerDB, pinKeyPath string) *SettingsStore {
+	return &SettingsStore{
+		db:         db,
+		pinKeyPath: pinKeyPath,
+	}
+}
+
+// Get returns saved settings, empty settings if nothing is saved yet.
+func (s *SettingsStore) Get() (settings entities.LTESettings, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(settingsKey))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(value []byte) error {
+			return json.Unmarshal(value, &settings)
+		})
+	}); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return settings, fmt.Errorf("Get: %w", err)
+	}
+
+	if err = s.convertSecrets(&settings, s.decrypt); err != nil {
+		return settings, fmt.Errorf("Get: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Update applies fn to saved settings and saves the result.
+func (s *SettingsStore) Update(fn func(settings *entities.LTESettings) error) (err error) {
+	s.updateMx.Lock()
+	defer s.updateMx.Unlock()
+
+	settings, err := s.Get()
+	if err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	if err = fn(&settings); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	if err = s.convertSecrets(&settings, s.encrypt); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(settingsKey), value)
+	}); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	return nil
+}
+
+// convertSecrets replaces saved PINs and APN passwords of settings with their copies converted by fn.
+func (s *SettingsStore) convertSecrets(settings *entities.LTESettings, fn func(key []byte, value string) (string, error)) (
+	err error) {
+	hasPasswords := lo.ContainsBy(settings.APNProfiles, func(profile entities.APNProfile) bool {
+		return !lo.IsEmpty(profile.Password)
+	})
+	if len(settings.SavedPINs) == 0 && !hasPasswords {
+		return nil
+	}
+
+	key, err := s.loadPINKey()
+	if err != nil {
+		return fmt.Errorf("convertSecrets: %w", err)
+	}
+
+	var pins map[string]string
+	if len(settings.SavedPINs) > 0 {
+		pins = make(map[string]string, len(settings.SavedPINs))
+	}
+	for iccid, pin := range settings.SavedPINs {
+		if pins[iccid], err = fn(key, pin); err != nil {
+			return fmt.Errorf("convertSecrets: sim %s: %w", iccid, err)
+		}
+	}
+
+	profiles := make([]entities.APNProfile, 0, len(settings.APNProfiles))
+	for _, profile := range settings.APNProfiles {
+		if !lo.IsEmpty(profile.Password) {
+			if profile.Password, err = fn(key, profile.Password); err != nil {
+				return fmt.Errorf("convertSecrets: APN profile %s: %w", profile.Name, err)
+			}
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	settings.SavedPINs = pins
+	settings.APNProfiles = profiles
+
+	return nil
+}
+
+// loadPINKey reads PIN encryption key, the key is generated on first use.
+func (s *SettingsStore) loadPINKey() (key []byte, err error) {
+	s.pinKeyMx.Lock()
+	defer s.pinKeyMx.Unlock()
+
+	if s.pinKey != nil {
+		return s.pinKey, nil
+	}
+
+	key, err = os.ReadFile(s.pinKeyPath)
+	switch {
+	case err == nil:
+		if len(key) != pinKeySize {
+			return nil, fmt.Errorf("loadPINKey: invalid key size %d", len(key))
+		}
+
+	case os.IsNotExist(err):
+		key = make([]byte, pinKeySize)
+		if _, err = io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("loadPINKey: %w", err)
+		}
+
+		if err = os.MkdirAll(filepath.Dir(s.pinKeyPath), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("loadPINKey: %w", err)
+		}
+
+		if err = os.WriteFile(s.pinKeyPath, key, pinKeyPerm); err != nil {
+			return nil, fmt.Errorf("loadPINKey: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("loadPINKey: %w", err)
+	}
+
+	s.pinKey = key
+
+	return key, nil
+}
+
+// encrypt seals value with AES-GCM, result is base64 of nonce and ciphertext.
+func (s *SettingsStore) encrypt(key []byte, value string) (encrypted string, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", fmt.Errorf("encrypt: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("encrypt: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+func (s *SettingsStore) decrypt(key []byte, encrypted string) (value string, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("decrypt: ciphertext is too short")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (gcm cipher.AEAD, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("newGCM: %w", err)
+	}
+
+	if gcm, err = cipher.NewGCM(block); err != nil {
+		return nil, fmt.Errorf("newGCM: %w", err)
+	}
+
+	return gcm, nil
+}
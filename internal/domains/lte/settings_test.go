@@ -0,0 +1,50 @@
+package lte_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/lte"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func TestSettingsStore_secrets(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	keyPath := filepath.Join(t.TempDir(), "lte-pin.key")
+	store := lte.NewSettingsStore(db, keyPath)
+	require.NoError(t, store.Update(func(settings *entities.LTESettings) error {
+		settings.SavedPINs = map[string]string{testICCID: "1234"}
+		settings.APNProfiles = []entities.APNProfile{{Name: "main", APN: "internet", Password: "secret"}}
+		return nil
+	}))
+
+	// PIN and APN password are not saved as plain text
+	require.NoError(t, db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte("lte:settings"))
+		if err != nil {
+			return err
+		}
+
+		value, err := item.ValueCopy(nil)
+		require.NotContains(t, string(value), `"1234"`)
+		require.NotContains(t, string(value), `"secret"`)
+
+		return err
+	}))
+
+	settings, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{testICCID: "1234"}, settings.SavedPINs)
+	require.Equal(t, "secret", settings.APNProfiles[0].Password)
+
+	// key is reused by new store
+	settings, err = lte.NewSettingsStore(db, keyPath).Get()
+	require.NoError(t, err)
+	require.Equal(t, "1234", settings.SavedPINs[testICCID])
+}
@@ -0,0 +1,283 @@
+package lte
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell/commands"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/shellcmd"
+)
+
+const (
+	modemStateLocked     = "locked"
+	unlockRequiredPIN    = "sim-pin"
+	gsmConnectionType    = "gsm"
+	emptySIMSlotDBusPath = "/"
+)
+
+type (
+	modemSIMInfo struct {
+		Modem struct {
+			Field3GPP struct {
+				IMEI string `json:"imei"`
+			} `json:"3gpp"`
+			Generic struct {
+				SIM            string   `json:"sim"`
+				SIMSlots       []string `json:"sim-slots"`        //nolint:tagliatelle // modem manager API
+				PrimarySIMSlot string   `json:"primary-sim-slot"` //nolint:tagliatelle // modem manager API
+				UnlockRequired string   `json:"unlock-required"`  //nolint:tagliatelle // modem manager API
+				UnlockRetries  []string `json:"unlock-retries"`   //nolint:tagliatelle // modem manager API
+			} `json:"generic"`
+		} `json:"modem"`
+	}
+
+	simInfo struct {
+		SIM struct {
+			Properties struct {
+				ICCID string `json:"iccid"`
+				IMSI  string `json:"imsi"`
+			} `json:"properties"`
+		} `json:"sim"`
+	}
+)
+
+// SIMStatuses returns sim status of all modems.
+func (s *Service) SIMStatuses() (statuses []entities.LTESIMStatus, err error) {
+	modemIDs, err := s.listModemIDs()
+	if err != nil {
+		return nil, fmt.Errorf("SIMStatuses: %w", err)
+	}
+
+	statuses = make([]entities.LTESIMStatus, 0, len(modemIDs))
+	for _, modemID := range modemIDs {
+		status, err := s.simStatus(modemID)
+		if err != nil {
+			return nil, fmt.Errorf("SIMStatuses: %w", err)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// SIMStatus returns sim status of modem with specified IMEI.
+func (s *Service) SIMStatus(imei string) (status entities.LTESIMStatus, err error) {
+	statuses, err := s.SIMStatuses()
+	if err != nil {
+		return status, fmt.Errorf("SIMStatus: %w", err)
+	}
+
+	status, found := lo.Find(statuses, func(item entities.LTESIMStatus) bool {
+		return item.IMEI == imei
+	})
+	if !found {
+		return status, fmt.Errorf("SIMStatus: %w: %s", errs.ErrModemNotFound, imei)
+	}
+
+	return status, nil
+}
+
+// UnlockSIM enters sim PIN, or PUK and new PIN if PUK is set. Returns ICCID of unlocked sim.
+func (s *Service) UnlockSIM(imei, pin, puk string) (iccid string, err error) {
+	status, err := s.SIMStatus(imei)
+	if err != nil {
+		return "", fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	if lo.IsEmpty(status.SIMPath) {
+		return "", fmt.Errorf("UnlockSIM: sim is not inserted")
+	}
+
+	args := []string{"--sim=" + path.Base(status.SIMPath), "--pin=" + pin}
+	if !lo.IsEmpty(puk) {
+		args = []string{"--sim=" + path.Base(status.SIMPath), "--puk=" + puk, "--pin=" + pin}
+	}
+
+	// PIN is passed as a separate argument, errors contain command output only
+	if err = s.shellService.Exec(shellcmd.New("mmcli", args...)); err != nil {
+		return "", fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	return status.ICCID, nil
+}
+
+// SelectSIMSlot switches primary sim slot of dual sim modem, modem is reprobed by modem manager after switch.
+func (s *Service) SelectSIMSlot(imei string, slot int) (err error) {
+	status, err := s.SIMStatus(imei)
+	if err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	if slot > status.Slots {
+		return fmt.Errorf("SelectSIMSlot: modem has %d sim slots", status.Slots)
+	}
+
+	if slot == status.PrimarySlot {
+		return nil
+	}
+
+	cmd := fmt.Sprintf("mmcli --modem=%s --set-primary-sim-slot=%d", status.ModemID, slot)
+	if err = s.shellService.Exec(commands.NewCustomCmd(cmd)); err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyAPNProfile sets APN settings to network manager gsm connection and reactivates it.
+// If connection is empty the single gsm connection is used.
+func (s *Service) ApplyAPNProfile(profile entities.APNProfile, connection string) (err error) {
+	if lo.IsEmpty(connection) {
+		output, err := s.shellService.ExecOutput(commands.NewCustomCmd("nmcli --terse --fields NAME,TYPE connection show"))
+		if err != nil {
+			return fmt.Errorf("ApplyAPNProfile: %w", err)
+		}
+
+		connections := parseGSMConnections(output)
+		if len(connections) != 1 {
+			return fmt.Errorf("ApplyAPNProfile: %w: found %d", errs.ErrGSMConnection, len(connections))
+		}
+
+		connection = connections[0]
+	}
+
+	ipv4Method, ipv6Method := "auto", "auto"
+	switch profile.IPType {
+	case entities.APNIPTypeIPv4:
+		ipv6Method = "ignore"
+	case entities.APNIPTypeIPv6:
+		ipv4Method = "disabled"
+	}
+
+	if err = s.shellService.Exec(shellcmd.New("nmcli", "connection", "modify", connection,
+		"gsm.apn", profile.APN,
+		"gsm.username", profile.User,
+		"gsm.password", profile.Password,
+		"gsm.password-flags", "0",
+		"ipv4.method", ipv4Method,
+		"ipv6.method", ipv6Method,
+	)); err != nil {
+		return fmt.Errorf("ApplyAPNProfile: %w", err)
+	}
+
+	if err = s.shellService.Exec(shellcmd.New("nmcli", "connection", "up", connection)); err != nil {
+		return fmt.Errorf("ApplyAPNProfile: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) listModemIDs() (modemIDs []string, err error) {
+	output, err := s.shellService.ExecOutput(commands.NewCustomCmd("mmcli -L --output-json"))
+	if err != nil {
+		return nil, fmt.Errorf("listModemIDs: %w", err)
+	}
+
+	var modemList struct {
+		ModemList []string `json:"modem-list"` //nolint:tagliatelle // network manager API
+	}
+	if err = json.Unmarshal(output, &modemList); err != nil {
+		return nil, fmt.Errorf("listModemIDs: %w", err)
+	}
+
+	modemIDs = make([]string, 0, len(modemList.ModemList))
+	for _, modem := range modemList.ModemList {
+		modemIDs = append(modemIDs, path.Base(modem))
+	}
+
+	return modemIDs, nil
+}
+
+func (s *Service) simStatus(modemID string) (status entities.LTESIMStatus, err error) {
+	output, err := s.shellService.ExecOutput(commands.NewCustomCmd(fmt.Sprintf("mmcli --modem=%s --output-json", modemID)))
+	if err != nil {
+		return status, fmt.Errorf("simStatus: %w", err)
+	}
+
+	if status, err = parseModemSIMInfo(output); err != nil {
+		return status, fmt.Errorf("simStatus: %w", err)
+	}
+	status.ModemID = modemID
+
+	if lo.IsEmpty(status.SIMPath) {
+		return status, nil
+	}
+
+	// sim properties may be unavailable while sim is locked
+	output, err = s.shellService.ExecOutput(
+		commands.NewCustomCmd(fmt.Sprintf("mmcli --sim=%s --output-json", path.Base(status.SIMPath))),
+	)
+	if err != nil {
+		return status, nil //nolint:nilerr // sim properties are optional
+	}
+
+	var sim simInfo
+	if err = json.Unmarshal(output, &sim); err != nil {
+		return status, fmt.Errorf("simStatus: %w", err)
+	}
+
+	status.ICCID = emptyIfUnknown(sim.SIM.Properties.ICCID)
+	status.IMSI = emptyIfUnknown(sim.SIM.Properties.IMSI)
+
+	return status, nil
+}
+
+func parseModemSIMInfo(output []byte) (status entities.LTESIMStatus, err error) {
+	var info modemSIMInfo
+	if err = json.Unmarshal(output, &info); err != nil {
+		return status, fmt.Errorf("parseModemSIMInfo: %w", err)
+	}
+
+	generic := info.Modem.Generic
+	status = entities.LTESIMStatus{
+		IMEI:           info.Modem.Field3GPP.IMEI,
+		UnlockRequired: emptyIfUnknown(generic.UnlockRequired),
+		UnlockRetries:  generic.UnlockRetries,
+		Slots:          max(len(generic.SIMSlots), 1),
+		PrimarySlot:    1,
+	}
+
+	if sim := emptyIfUnknown(generic.SIM); sim != emptySIMSlotDBusPath {
+		status.SIMPath = sim
+	}
+
+	if slot, err := strconv.Atoi(emptyIfUnknown(generic.PrimarySIMSlot)); err == nil {
+		status.PrimarySlot = slot
+	}
+
+	return status, nil
+}
+
+// parseGSMConnections parses terse NAME,TYPE connection list and returns gsm connection names.
+func parseGSMConnections(output []byte) (connections []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		name, connType, found := cutLast(line, ":")
+		if !found || connType != gsmConnectionType {
+			continue
+		}
+
+		connections = append(connections, strings.ReplaceAll(name, `\:`, ":"))
+	}
+
+	return connections
+}
+
+func cutLast(value, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(value, sep); i >= 0 {
+		return value[:i], value[i+len(sep):], true
+	}
+
+	return value, "", false
+}
@@ -0,0 +1,219 @@
+package lte
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+// GetSIMSettings returns APN profiles (without passwords), sim failover policy and sim status of modems.
+func (h *Handler) GetSIMSettings(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	settings, err := h.settingsStore.Get()
+	if err != nil {
+		return fmt.Errorf("GetSIMSettings: %w", err)
+	}
+
+	statuses, err := h.lteService.SIMStatuses()
+	if err != nil {
+		return fmt.Errorf("GetSIMSettings: %w", err)
+	}
+
+	response := entities.LTESIMSettings{
+		APNProfiles: lo.Map(settings.APNProfiles, func(profile entities.APNProfile, _ int) entities.APNProfile {
+			profile.Password = ""
+			return profile
+		}),
+		ActiveProfile: settings.ActiveProfile,
+		Failover:      settings.Failover,
+		Statuses:      statuses,
+	}
+	if err = h.messagePublisher.PublishResponse(request, response); err != nil {
+		return fmt.Errorf("GetSIMSettings: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockSIM enters sim PIN (or PUK and new PIN), optionally saves PIN to unlock sim after modem reset.
+func (h *Handler) UnlockSIM(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.LTEUnlockSIMRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	iccid, err := h.lteService.UnlockSIM(requestBody.IMEI, requestBody.PIN, requestBody.PUK)
+	if err != nil {
+		return fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	if lo.IsEmpty(iccid) {
+		// ICCID may be unavailable while sim is locked
+		status, err := h.lteService.SIMStatus(requestBody.IMEI)
+		if err != nil {
+			return fmt.Errorf("UnlockSIM: %w", err)
+		}
+
+		iccid = status.ICCID
+	}
+
+	if !lo.IsEmpty(iccid) {
+		if err = h.settingsStore.Update(func(settings *entities.LTESettings) error {
+			if !requestBody.SavePIN {
+				delete(settings.SavedPINs, iccid)
+				return nil
+			}
+
+			if settings.SavedPINs == nil {
+				settings.SavedPINs = make(map[string]string)
+			}
+			settings.SavedPINs[iccid] = requestBody.PIN
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("UnlockSIM: %w", err)
+		}
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("UnlockSIM: %w", err)
+	}
+
+	return nil
+}
+
+// SelectSIMSlot switches active sim slot of dual sim modem.
+func (h *Handler) SelectSIMSlot(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.LTESelectSIMSlotRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	if err = h.lteService.SelectSIMSlot(requestBody.IMEI, requestBody.Slot); err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("SelectSIMSlot: %w", err)
+	}
+
+	return nil
+}
+
+// SetAPNProfiles saves APN profiles and applies active profile to gsm connection.
+func (h *Handler) SetAPNProfiles(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.LTESetAPNProfilesRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SetAPNProfiles: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SetAPNProfiles: %w", err)
+	}
+
+	if !lo.IsEmpty(requestBody.ActiveProfile) {
+		profile, found := lo.Find(requestBody.Profiles, func(item entities.APNProfile) bool {
+			return item.Name == requestBody.ActiveProfile
+		})
+		if !found {
+			return fmt.Errorf("SetAPNProfiles: %w: %s", errs.ErrAPNProfileNotFound, requestBody.ActiveProfile)
+		}
+
+		if err = h.lteService.ApplyAPNProfile(profile, requestBody.Connection); err != nil {
+			return fmt.Errorf("SetAPNProfiles: %w", err)
+		}
+	}
+
+	if err = h.settingsStore.Update(func(settings *entities.LTESettings) error {
+		settings.APNProfiles = requestBody.Profiles
+		settings.ActiveProfile = requestBody.ActiveProfile
+		return nil
+	}); err != nil {
+		return fmt.Errorf("SetAPNProfiles: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("SetAPNProfiles: %w", err)
+	}
+
+	return nil
+}
+
+// SetSIMFailover saves automatic sim failover policy used by LTE monitor.
+func (h *Handler) SetSIMFailover(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.LTESetSIMFailoverRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("SetSIMFailover: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("SetSIMFailover: %w", err)
+	}
+
+	if err = h.settingsStore.Update(func(settings *entities.LTESettings) error {
+		settings.Failover = requestBody.Failover
+		return nil
+	}); err != nil {
+		return fmt.Errorf("SetSIMFailover: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("SetSIMFailover: %w", err)
+	}
+
+	return nil
+}
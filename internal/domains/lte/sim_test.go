@@ -0,0 +1,86 @@
+package lte
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func Test_parseModemSIMInfo(t *testing.T) {
+	output := []byte(`{"modem":{"3gpp":{"imei":"861234567890123"},"generic":{"primary-sim-slot":"2",
+"sim":"/org/freedesktop/ModemManager1/SIM/3","sim-slots":["/","/org/freedesktop/ModemManager1/SIM/3"],
+"unlock-required":"sim-pin","unlock-retries":["sim-pin (3)","sim-puk (10)"]}}}`)
+
+	status, err := parseModemSIMInfo(output)
+	require.NoError(t, err)
+	require.Equal(t, entities.LTESIMStatus{
+		IMEI:           "861234567890123",
+		SIMPath:        "/org/freedesktop/ModemManager1/SIM/3",
+		PrimarySlot:    2,
+		Slots:          2,
+		UnlockRequired: unlockRequiredPIN,
+		UnlockRetries:  []string{"sim-pin (3)", "sim-puk (10)"},
+	}, status)
+
+	// single sim modem without sim
+	status, err = parseModemSIMInfo([]byte(`{"modem":{"generic":{"sim":"--","unlock-required":"--"}}}`))
+	require.NoError(t, err)
+	require.Empty(t, status.SIMPath)
+	require.Empty(t, status.UnlockRequired)
+	require.Equal(t, 1, status.Slots)
+	require.Equal(t, 1, status.PrimarySlot)
+}
+
+func Test_parseGSMConnections(t *testing.T) {
+	output := []byte("Wired connection 1:802-3-ethernet\nlte\\:main:gsm\nwg0:wireguard\n")
+	require.Equal(t, []string{"lte:main"}, parseGSMConnections(output))
+}
+
+func Test_validateSIMRequests(t *testing.T) {
+	validate := validator.New()
+
+	tests := []struct {
+		name    string
+		request any
+		wantErr bool
+	}{
+		{
+			name:    "failover disabled",
+			request: entities.LTESetSIMFailoverRequest{},
+		},
+		{
+			name: "failover without slots",
+			request: entities.LTESetSIMFailoverRequest{
+				Failover: entities.LTESIMFailover{Enabled: true, RegistrationTimeoutMinutes: 5},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated APN profiles",
+			request: entities.LTESetAPNProfilesRequest{
+				Profiles: []entities.APNProfile{{Name: "a", APN: "internet"}, {Name: "a", APN: "iot"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "PUK without PIN",
+			request: entities.LTEUnlockSIMRequest{IMEI: "1", PUK: "12345678"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.Struct(tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	typeGauge     = "gauge"
+	typeCounter   = "counter"
+	typeHistogram = "histogram"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+func newLabel(name, value string) label {
+	return label{name: name, value: value}
+}
+
+// encoder writes metrics in OpenMetrics text format.
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newEncoder(w io.Writer) *encoder {
+	return &encoder{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// family writes metric family header.
+func (e *encoder) family(name, metricType, help string) {
+	e.printf("# TYPE %s %s\n", name, metricType)
+	e.printf("# HELP %s %s\n", name, escapeHelp(help))
+}
+
+// gauge writes gauge sample.
+func (e *encoder) gauge(name string, value float64, labels ...label) {
+	e.sample(name, value, labels...)
+}
+
+// counter writes counter sample (family name without _total suffix).
+func (e *encoder) counter(name string, value uint64, labels ...label) {
+	e.sample(name+"_total", float64(value), labels...)
+}
+
+// histogram writes histogram samples.
+func (e *encoder) histogram(name string, h entities.Histogram, labels ...label) {
+	var cumulative uint64
+	for i, bound := range h.Buckets {
+		cumulative += h.Counts[i]
+		e.sample(name+"_bucket", float64(cumulative), withLabel(labels, newLabel("le", formatFloat(bound)))...)
+	}
+
+	e.sample(name+"_bucket", float64(h.Count), withLabel(labels, newLabel("le", "+Inf"))...)
+	e.sample(name+"_count", float64(h.Count), labels...)
+	e.sample(name+"_sum", h.Sum, labels...)
+}
+
+// finish writes EOF marker and flushes buffer.
+func (e *encoder) finish() error {
+	e.printf("# EOF\n")
+	if e.err != nil {
+		return fmt.Errorf("finish: %w", e.err)
+	}
+
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("finish: %w", err)
+	}
+
+	return nil
+}
+
+func (e *encoder) sample(name string, value float64, labels ...label) {
+	e.printf("%s", name)
+	if len(labels) > 0 {
+		e.printf("{")
+		for i, l := range labels {
+			if i > 0 {
+				e.printf(",")
+			}
+			e.printf("%s=\"%s\"", l.name, escapeLabelValue(l.value))
+		}
+		e.printf("}")
+	}
+	e.printf(" %s\n", formatFloat(value))
+}
+
+func (e *encoder) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+// withLabel returns copy of labels with extra label appended.
+func withLabel(labels []label, extra label) []label {
+	result := make([]label, 0, len(labels)+1)
+	result = append(result, labels...)
+
+	return append(result, extra)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
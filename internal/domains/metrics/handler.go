@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+const contentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type (
+	IMetricsService interface {
+		WriteMetrics(ctx context.Context, w io.Writer) (err error)
+	}
+
+	Handler struct {
+		metricsService IMetricsService
+	}
+)
+
+func NewHandler(metricsService IMetricsService) *Handler {
+	return &Handler{
+		metricsService: metricsService,
+	}
+}
+
+// ServeMetrics serves agent metrics in OpenMetrics format.
+func (h *Handler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := h.metricsService.WriteMetrics(r.Context(), &buf); err != nil {
+		log.Error().Err(err).Msg("ServeMetrics")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Error().Err(err).Msg("ServeMetrics")
+	}
+}
@@ -0,0 +1,365 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/activity"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const metricPrefix = "sdwan_agent_"
+
+var appStates = []entities.AppState{
+	entities.AppStateBoot,
+	entities.AppStateInit,
+	entities.AppStateActive,
+	entities.AppStateUpdateConfig,
+	entities.AppStateMaintenance,
+	entities.AppStateZTPSetup,
+	entities.AppStateReset,
+}
+
+type (
+	IAppStateService interface {
+		Stats() entities.AppStateStats
+	}
+
+	IWebsocketService interface {
+		Stats() entities.WebsocketStats
+	}
+
+	IDiscoveryService interface {
+		Stats() entities.DiscoveryStats
+	}
+
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	IBadgerDB interface {
+		Size() (lsm, vlog int64)
+	}
+
+	IStorageAdapter interface {
+		GetNotFinishedTransactions(ctx context.Context) (result activity.Transactions, err error)
+	}
+
+	ILTEMonitor interface {
+		LastStats() entities.LTEStats
+	}
+
+	IPortStatsService interface {
+		Stats() (stats []entities.PortStats)
+	}
+
+	Service struct {
+		appStateService  IAppStateService
+		websocketService IWebsocketService
+		discoveryService IDiscoveryService
+		configService    IConfigService
+		db               IBadgerDB
+		storageAdapter   IStorageAdapter
+		lteMonitor       ILTEMonitor
+		portStatsService IPortStatsService
+	}
+)
+
+func NewService(
+	appStateService IAppStateService,
+	websocketService IWebsocketService,
+	discoveryService IDiscoveryService,
+	configService IConfigService,
+	db IBadgerDB,
+	storageAdapter IStorageAdapter,
+	lteMonitor ILTEMonitor,
+	portStatsService IPortStatsService,
+) *Service {
+	return &Service{
+		appStateService:  appStateService,
+		websocketService: websocketService,
+		discoveryService: discoveryService,
+		configService:    configService,
+		db:               db,
+		storageAdapter:   storageAdapter,
+		lteMonitor:       lteMonitor,
+		portStatsService: portStatsService,
+	}
+}
+
+// WriteMetrics writes actual agent metrics in OpenMetrics text format.
+func (s *Service) WriteMetrics(ctx context.Context, w io.Writer) (err error) {
+	enc := newEncoder(w)
+
+	s.writeAppStateMetrics(enc)
+	s.writeWebsocketMetrics(enc)
+	s.writeDiscoveryMetrics(enc)
+	s.writeLTEMetrics(enc)
+	s.writePortMetrics(enc)
+
+	if err = s.writePonyMetrics(enc); err != nil {
+		return fmt.Errorf("WriteMetrics: %w", err)
+	}
+
+	if err = s.writeStorageMetrics(ctx, enc); err != nil {
+		return fmt.Errorf("WriteMetrics: %w", err)
+	}
+
+	if err = enc.finish(); err != nil {
+		return fmt.Errorf("WriteMetrics: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) writeAppStateMetrics(enc *encoder) {
+	stats := s.appStateService.Stats()
+
+	enc.family(metricPrefix+"app_state", typeGauge, "Current agent app state.")
+	for _, state := range appStates {
+		enc.gauge(metricPrefix+"app_state", boolToFloat(state == stats.State), newLabel("state", state.String()))
+	}
+
+	enc.family(metricPrefix+"app_state_duration_seconds", typeGauge, "Time spent in current app state.")
+	enc.gauge(metricPrefix+"app_state_duration_seconds", time.Since(stats.StateSince).Seconds())
+
+	enc.family(metricPrefix+"state_transitions", typeCounter, "Count of performed state transitions.")
+	for _, transition := range stats.Transitions {
+		enc.counter(metricPrefix+"state_transitions", transition.Duration.Count,
+			newLabel("from", transition.From.String()),
+			newLabel("to", transition.To.String()),
+		)
+	}
+
+	enc.family(metricPrefix+"state_transition_failures", typeCounter, "Count of failed state transitions.")
+	for _, transition := range stats.Transitions {
+		enc.counter(metricPrefix+"state_transition_failures", transition.Failures,
+			newLabel("from", transition.From.String()),
+			newLabel("to", transition.To.String()),
+		)
+	}
+
+	enc.family(metricPrefix+"state_transition_duration_seconds", typeHistogram, "Duration of state transitions.")
+	for _, transition := range stats.Transitions {
+		enc.histogram(metricPrefix+"state_transition_duration_seconds", transition.Duration,
+			newLabel("from", transition.From.String()),
+			newLabel("to", transition.To.String()),
+		)
+	}
+}
+
+func (s *Service) writeWebsocketMetrics(enc *encoder) {
+	stats := s.websocketService.Stats()
+
+	enc.family(metricPrefix+"websocket_connected", typeGauge, "Websocket connection to orchestrator is active.")
+	enc.gauge(metricPrefix+"websocket_connected", boolToFloat(stats.Connected))
+
+	enc.family(metricPrefix+"websocket_connects", typeCounter, "Count of established websocket connections.")
+	enc.counter(metricPrefix+"websocket_connects", stats.Connects)
+
+	enc.family(metricPrefix+"websocket_disconnects", typeCounter, "Count of closed websocket connections.")
+	enc.counter(metricPrefix+"websocket_disconnects", stats.Disconnects)
+
+	enc.family(metricPrefix+"websocket_handler_errors", typeCounter, "Count of websocket handler errors.")
+	for _, handler := range stats.Handlers {
+		enc.counter(metricPrefix+"websocket_handler_errors", handler.Errors, newLabel("method", handler.Method))
+	}
+
+	enc.family(metricPrefix+"websocket_handler_duration_seconds", typeHistogram, "Duration of websocket handlers.")
+	for _, handler := range stats.Handlers {
+		enc.histogram(metricPrefix+"websocket_handler_duration_seconds", handler.Duration, newLabel("method", handler.Method))
+	}
+}
+
+func (s *Service) writeDiscoveryMetrics(enc *encoder) {
+	stats := s.discoveryService.Stats()
+
+	enc.family(metricPrefix+"discovery_checks", typeCounter, "Count of primary orchestrator discovery checks.")
+	for result, count := range stats.Checks {
+		enc.counter(metricPrefix+"discovery_checks", count, newLabel("result", result))
+	}
+
+	enc.family(metricPrefix+"discovery_host_primary", typeGauge, "Orchestrator host reported itself as primary on last check.")
+	for _, host := range stats.Hosts {
+		enc.gauge(metricPrefix+"discovery_host_primary", boolToFloat(host.IsPrimary), newLabel("host", host.Host))
+	}
+
+	enc.family(metricPrefix+"discovery_host_failed", typeGauge, "Orchestrator host check failed on last check.")
+	for _, host := range stats.Hosts {
+		enc.gauge(metricPrefix+"discovery_host_failed", boolToFloat(host.Failed), newLabel("host", host.Host))
+	}
+
+	if !stats.CheckedAt.IsZero() {
+		enc.family(metricPrefix+"discovery_last_check_timestamp_seconds", typeGauge, "Time of the last discovery check.")
+		enc.gauge(metricPrefix+"discovery_last_check_timestamp_seconds", float64(stats.CheckedAt.Unix()))
+	}
+}
+
+func (s *Service) writeLTEMetrics(enc *encoder) {
+	stats := s.lteMonitor.LastStats()
+	if len(stats) == 0 {
+		return
+	}
+
+	enc.family(metricPrefix+"lte_info", typeGauge, "LTE modem radio info.")
+	for _, stat := range stats {
+		labels := []label{
+			newLabel("imei", stat.IMEI),
+			newLabel("operator", stat.OperatorName),
+			newLabel("access_technology", stat.Radio.AccessTechnology),
+			newLabel("cell_id", stat.Radio.CellID),
+			newLabel("tac", stat.Radio.TAC),
+		}
+		// serving band is not reported by all modems
+		if stat.Radio.Band != "" {
+			labels = append(labels, newLabel("band", stat.Radio.Band))
+		}
+
+		enc.gauge(metricPrefix+"lte_info", 1, labels...)
+	}
+
+	enc.family(metricPrefix+"lte_connected", typeGauge, "LTE modem is connected.")
+	for _, stat := range stats {
+		enc.gauge(metricPrefix+"lte_connected", boolToFloat(stat.State == "connected"), newLabel("imei", stat.IMEI))
+	}
+
+	enc.family(metricPrefix+"lte_roaming", typeGauge, "LTE modem is registered in roaming network.")
+	for _, stat := range stats {
+		enc.gauge(metricPrefix+"lte_roaming", boolToFloat(stat.Radio.Roaming), newLabel("imei", stat.IMEI))
+	}
+
+	enc.family(metricPrefix+"lte_signal_quality_percent", typeGauge, "LTE modem signal quality.")
+	for _, stat := range stats {
+		if quality, err := strconv.ParseFloat(stat.SignalQuality.Value, 64); err == nil {
+			enc.gauge(metricPrefix+"lte_signal_quality_percent", quality, newLabel("imei", stat.IMEI))
+		}
+	}
+
+	for _, signal := range []struct {
+		name  string
+		help  string
+		value func(radio entities.LTERadioStat) *float64
+	}{
+		{"lte_rsrp_dbm", "LTE reference signal received power.", func(radio entities.LTERadioStat) *float64 { return radio.RSRP }},
+		{"lte_rsrq_db", "LTE reference signal received quality.", func(radio entities.LTERadioStat) *float64 { return radio.RSRQ }},
+		{"lte_sinr_db", "LTE signal to interference plus noise ratio.", func(radio entities.LTERadioStat) *float64 { return radio.SINR }},
+		{"lte_rssi_dbm", "LTE received signal strength indicator.", func(radio entities.LTERadioStat) *float64 { return radio.RSSI }},
+	} {
+		enc.family(metricPrefix+signal.name, typeGauge, signal.help)
+		for _, stat := range stats {
+			if value := signal.value(stat.Radio); value != nil {
+				enc.gauge(metricPrefix+signal.name, *value, newLabel("imei", stat.IMEI))
+			}
+		}
+	}
+}
+
+func (s *Service) writePortMetrics(enc *encoder) {
+	stats := s.portStatsService.Stats()
+	if len(stats) == 0 {
+		return
+	}
+
+	for _, metric := range []struct {
+		name    string
+		help    string
+		counter func(counters entities.PortCounters) uint64
+		rate    func(rates entities.PortRates) float64
+	}{
+		{"port_rx_bytes", "bytes received by port", func(c entities.PortCounters) uint64 { return c.RxBytes }, func(r entities.PortRates) float64 { return r.RxBytes }},
+		{"port_tx_bytes", "bytes sent by port", func(c entities.PortCounters) uint64 { return c.TxBytes }, func(r entities.PortRates) float64 { return r.TxBytes }},
+		{"port_rx_packets", "packets received by port", func(c entities.PortCounters) uint64 { return c.RxPackets }, func(r entities.PortRates) float64 { return r.RxPackets }},
+		{"port_tx_packets", "packets sent by port", func(c entities.PortCounters) uint64 { return c.TxPackets }, func(r entities.PortRates) float64 { return r.TxPackets }},
+		{"port_rx_errors", "receive errors of port", func(c entities.PortCounters) uint64 { return c.RxErrors }, func(r entities.PortRates) float64 { return r.RxErrors }},
+		{"port_tx_errors", "transmit errors of port", func(c entities.PortCounters) uint64 { return c.TxErrors }, func(r entities.PortRates) float64 { return r.TxErrors }},
+		{"port_rx_dropped", "received packets dropped by port", func(c entities.PortCounters) uint64 { return c.RxDropped }, func(r entities.PortRates) float64 { return r.RxDropped }},
+		{"port_tx_dropped", "sent packets dropped by port", func(c entities.PortCounters) uint64 { return c.TxDropped }, func(r entities.PortRates) float64 { return r.TxDropped }},
+	} {
+		enc.family(metricPrefix+metric.name, typeCounter, "Count of "+metric.help+".")
+		for _, stat := range stats {
+			enc.counter(metricPrefix+metric.name, metric.counter(stat.Counters), newLabel("port", stat.PortName))
+		}
+
+		enc.family(metricPrefix+metric.name+"_per_second", typeGauge, "Rate of "+metric.help+" over sliding window.")
+		for _, stat := range stats {
+			enc.gauge(metricPrefix+metric.name+"_per_second", metric.rate(stat.Rates), newLabel("port", stat.PortName))
+		}
+	}
+}
+
+func (s *Service) writePonyMetrics(enc *encoder) (err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("writePonyMetrics: %w", err)
+	}
+
+	if cfg.Pony == nil {
+		return nil
+	}
+
+	type tunnel struct {
+		labels       []label
+		localUp      bool
+		remoteUp     bool
+		activeTunnel bool
+	}
+
+	tunnels := make([]tunnel, 0)
+	for _, cluster := range cfg.Pony.Clusters {
+		for _, uplink := range cluster.Uplinks {
+			tunnels = append(tunnels, tunnel{
+				labels: []label{
+					newLabel("network", cluster.Network),
+					newLabel("tunnel", uplink.MonitorAddr),
+					newLabel("hub_serial", uplink.HubSerial),
+					newLabel("table_id", strconv.Itoa(uplink.TableID)),
+				},
+				localUp:      cluster.State.LocalStates[uplink.MonitorAddr],
+				remoteUp:     cluster.State.RemoteStates[uplink.MonitorAddr],
+				activeTunnel: cluster.State.ActiveTunnel == uplink.MonitorAddr,
+			})
+		}
+	}
+
+	enc.family(metricPrefix+"pony_tunnel_up", typeGauge, "Tunnel state detected by local pony monitor.")
+	for _, t := range tunnels {
+		enc.gauge(metricPrefix+"pony_tunnel_up", boolToFloat(t.localUp), t.labels...)
+	}
+
+	enc.family(metricPrefix+"pony_tunnel_remote_up", typeGauge, "Tunnel state reported by remote side.")
+	for _, t := range tunnels {
+		enc.gauge(metricPrefix+"pony_tunnel_remote_up", boolToFloat(t.remoteUp), t.labels...)
+	}
+
+	enc.family(metricPrefix+"pony_tunnel_active", typeGauge, "Tunnel is selected as active in cluster.")
+	for _, t := range tunnels {
+		enc.gauge(metricPrefix+"pony_tunnel_active", boolToFloat(t.activeTunnel), t.labels...)
+	}
+
+	return nil
+}
+
+func (s *Service) writeStorageMetrics(ctx context.Context, enc *encoder) (err error) {
+	lsm, vlog := s.db.Size()
+
+	enc.family(metricPrefix+"badger_lsm_size_bytes", typeGauge, "Badger LSM tree size.")
+	enc.gauge(metricPrefix+"badger_lsm_size_bytes", float64(lsm))
+
+	enc.family(metricPrefix+"badger_vlog_size_bytes", typeGauge, "Badger value log size.")
+	enc.gauge(metricPrefix+"badger_vlog_size_bytes", float64(vlog))
+
+	transactions, err := s.storageAdapter.GetNotFinishedTransactions(ctx)
+	if err != nil {
+		return fmt.Errorf("writeStorageMetrics: %w", err)
+	}
+
+	enc.family(metricPrefix+"unfinished_transactions", typeGauge, "Count of not finished (dangling or in progress) activity transactions.")
+	enc.gauge(metricPrefix+"unfinished_transactions", float64(len(transactions)))
+
+	return nil
+}
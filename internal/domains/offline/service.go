@@ -0,0 +1,360 @@
+package offline
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/validator"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/updatemanager"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	DefaultInterval = 10 * time.Second
+	// settleTime bundle is processed when it was not modified for settle time, so partially copied files are skipped
+	settleTime    = 5 * time.Second
+	maxBundleSize = 4 << 20 // 4MB
+	eventSource   = "offline_bundle"
+	// maxBundleAge older bundles are rejected, so lost or copied bundle cannot be replayed later
+	maxBundleAge = 30 * 24 * time.Hour
+	// maxClockSkew bundle made ahead of device clock is accepted within skew
+	maxClockSkew = 24 * time.Hour
+)
+
+var errBundleRejected = errors.New("bundle rejected")
+
+type (
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	IAppStateService interface {
+		ActiveState() entities.AppState
+		Perform(transition common.IStateTransition) (err error)
+	}
+
+	IDeviceInitService interface {
+		InitDevice(initConfig entities.InitConfig) (err error)
+		IsInitializing() bool
+	}
+
+	IEventService interface {
+		Report(event entities.AgentEvent)
+	}
+
+	IStore interface {
+		IsApplied(digest string) (applied bool, err error)
+		LastAppliedAt() (createdAt time.Time, err error)
+		SaveApplied(digest string, createdAt time.Time, ttl time.Duration) (err error)
+	}
+
+	// Service watches incoming directories (local or mounted usb) for signed offline bundles and applies them
+	// with the same transitions as ZTP first setup and device init. Result is written next to the bundle.
+	Service struct {
+		configService     IConfigService
+		appStateService   IAppStateService
+		deviceInitService IDeviceInitService
+		eventService      IEventService
+		store             IStore
+		dirs              []string
+		trustedKeysDir    string
+		interval          time.Duration
+
+		processed map[string]time.Time // bundle modification time by path, result may be not writable (read only usb)
+	}
+)
+
+func NewService(configService IConfigService, appStateService IAppStateService, deviceInitService IDeviceInitService,
+	eventService IEventService, store IStore, dirs []string, trustedKeysDir string, interval time.Duration) *Service {
+	return &Service{
+		configService:     configService,
+		appStateService:   appStateService,
+		deviceInitService: deviceInitService,
+		eventService:      eventService,
+		store:             store,
+		dirs:              dirs,
+		trustedKeysDir:    trustedKeysDir,
+		interval:          interval,
+
+		processed: make(map[string]time.Time),
+	}
+}
+
+// Start starts watching incoming directories.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, dir := range s.dirs {
+				s.Scan(dir, time.Now())
+			}
+		}
+	}
+}
+
+// Scan processes settled bundles of directory which have no result yet.
+func (s *Service) Scan(dir string, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// usb stick is not mounted or directory is not created
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Error().Err(err).Str("dir", dir).Msg("Scan: read incoming directory error")
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, constants.OfflineBundleFileSuffix) ||
+			strings.HasSuffix(name, constants.OfflineBundleResultSuffix) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if _, err = os.Stat(resultPath(path)); err == nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < settleTime {
+			continue
+		}
+
+		if modTime, found := s.processed[path]; found && modTime.Equal(info.ModTime()) {
+			continue
+		}
+		s.processed[path] = info.ModTime()
+
+		result := s.process(path, info.Size())
+		if err = writeResult(path, result); err != nil {
+			log.Error().Err(err).Str("file", path).Msg("Scan: write bundle result error")
+		}
+	}
+}
+
+func (s *Service) process(path string, size int64) (result entities.OfflineBundleResult) {
+	result = entities.OfflineBundleResult{
+		File:   filepath.Base(path),
+		Status: entities.OfflineBundleStatusApplied,
+	}
+
+	log.Info().Str("file", path).Msg("process: offline bundle found")
+
+	bundle, digest, err := s.readBundle(path, size)
+	if err == nil {
+		result.Kind = bundle.Kind
+		err = s.checkReplay(bundle, digest, time.Now())
+	}
+	if err == nil {
+		err = s.apply(bundle)
+	}
+	if err == nil {
+		if saveErr := s.store.SaveApplied(digest, bundle.CreatedAt, maxBundleAge+maxClockSkew); saveErr != nil {
+			log.Error().Err(saveErr).Str("file", path).Msg("process: save applied bundle error")
+		}
+	}
+	result.ProcessedAt = time.Now()
+
+	event := entities.AgentEvent{
+		Source:   eventSource,
+		Type:     "offline_bundle_applied",
+		Severity: entities.EventSeverityInfo,
+		Message:  fmt.Sprintf("offline bundle %s applied", result.File),
+		Data:     result,
+	}
+
+	if err != nil {
+		result.Status = entities.OfflineBundleStatusFailed
+		if errors.Is(err, errBundleRejected) {
+			result.Status = entities.OfflineBundleStatusRejected
+		}
+		result.Error = err.Error()
+
+		log.Error().Err(err).Str("file", path).Msg("process: offline bundle is not applied")
+
+		event.Type = "offline_bundle_failed"
+		event.Severity = entities.EventSeverityError
+		event.Message = fmt.Sprintf("offline bundle %s is not applied", result.File)
+		event.Data = result
+	}
+
+	s.eventService.Report(event)
+
+	return result
+}
+
+// readBundle reads bundle file and verifies its signature and content, returns bundle with hex sha256 of its json.
+func (s *Service) readBundle(path string, size int64) (bundle entities.OfflineBundle, digest string, err error) {
+	if size > maxBundleSize {
+		return bundle, "", fmt.Errorf("readBundle: %w: file is too large", errBundleRejected)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w", err)
+	}
+
+	var signed entities.SignedOfflineBundle
+	if err = json.Unmarshal(data, &signed); err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w: %w", errBundleRejected, err)
+	}
+
+	keys, err := updatemanager.LoadTrustedKeys(s.trustedKeysDir)
+	if err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w: %w", errBundleRejected, err)
+	}
+
+	digest, err = verifyBundle(keys, signed)
+	if err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w: %w", errBundleRejected, err)
+	}
+
+	if err = json.Unmarshal(signed.Bundle, &bundle); err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w: %w", errBundleRejected, err)
+	}
+
+	if err = validator.Validator.Struct(bundle); err != nil {
+		return bundle, "", fmt.Errorf("readBundle: %w: %w", errBundleRejected, err)
+	}
+
+	return bundle, digest, nil
+}
+
+// checkReplay rejects expired bundle, bundle which is applied already or made before the last applied one.
+func (s *Service) checkReplay(bundle entities.OfflineBundle, digest string, now time.Time) (err error) {
+	if now.Sub(bundle.CreatedAt) > maxBundleAge {
+		return fmt.Errorf("checkReplay: %w: bundle is older than %s", errBundleRejected, maxBundleAge)
+	}
+
+	if bundle.CreatedAt.Sub(now) > maxClockSkew {
+		return fmt.Errorf("checkReplay: %w: bundle is made in future", errBundleRejected)
+	}
+
+	applied, err := s.store.IsApplied(digest)
+	if err != nil {
+		return fmt.Errorf("checkReplay: %w", err)
+	}
+
+	if applied {
+		return fmt.Errorf("checkReplay: %w: bundle is applied already", errBundleRejected)
+	}
+
+	lastAppliedAt, err := s.store.LastAppliedAt()
+	if err != nil {
+		return fmt.Errorf("checkReplay: %w", err)
+	}
+
+	if !bundle.CreatedAt.After(lastAppliedAt) {
+		return fmt.Errorf("checkReplay: %w: bundle is not newer than the last applied one made at %s",
+			errBundleRejected, lastAppliedAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func (s *Service) apply(bundle entities.OfflineBundle) (err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	var serialNumber string
+	if cfg.App != nil {
+		serialNumber = cfg.App.SerialNumber
+	}
+
+	switch bundle.Kind {
+	case entities.OfflineBundleKindZTP:
+		// device which passed first setup is not set up again by bundle of another device
+		if !lo.IsEmpty(serialNumber) && serialNumber != bundle.ZTP.SerialNumber {
+			return fmt.Errorf("apply: %w: device serial number is %s", errBundleRejected, serialNumber)
+		}
+
+		if len(bundle.ZTP.PortConfigs) > 0 {
+			if err = s.appStateService.Perform(entities.NewOnZTPSetupConfig(config.Config{
+				Port: &config.PortSection{
+					PortConfigs: bundle.ZTP.PortConfigs,
+				},
+			})); err != nil {
+				return fmt.Errorf("apply: %w", err)
+			}
+		}
+
+		if err = s.appStateService.Perform(
+			entities.NewOnFirstSetup(bundle.ZTP.SerialNumber, bundle.ZTP.OrchestratorAddrs),
+		); err != nil {
+			return fmt.Errorf("apply: %w", err)
+		}
+
+	case entities.OfflineBundleKindInit:
+		if serialNumber != bundle.SerialNumber {
+			return fmt.Errorf("apply: %w: bundle is made for %s", errBundleRejected, bundle.SerialNumber)
+		}
+
+		if activeState := s.appStateService.ActiveState(); activeState != entities.AppStateActive {
+			return fmt.Errorf("apply: device not in active state (current state: %s)", activeState)
+		}
+
+		if s.deviceInitService.IsInitializing() {
+			return fmt.Errorf("apply: device already initializing")
+		}
+
+		if err = s.deviceInitService.InitDevice(*bundle.Init); err != nil {
+			return fmt.Errorf("apply: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// verifyBundle verifies bundle signature, returns hex sha256 of bundle json.
+func verifyBundle(keys []ed25519.PublicKey, signed entities.SignedOfflineBundle) (digest string, err error) {
+	if lo.IsEmpty(signed.Signature) || len(signed.Bundle) == 0 {
+		return "", fmt.Errorf("verifyBundle: bundle is not signed")
+	}
+
+	sum := sha256.Sum256(signed.Bundle)
+	if err = updatemanager.VerifySignature(keys, sum[:], signed.Signature); err != nil {
+		return "", fmt.Errorf("verifyBundle: %w", err)
+	}
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeResult(bundlePath string, result entities.OfflineBundleResult) (err error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("writeResult: %w", err)
+	}
+
+	if err = os.WriteFile(resultPath(bundlePath), data, 0o644); err != nil { //nolint:gosec // result is read by technician
+		return fmt.Errorf("writeResult: %w", err)
+	}
+
+	return nil
+}
+
+func resultPath(bundlePath string) string {
+	return strings.TrimSuffix(bundlePath, constants.OfflineBundleFileSuffix) + constants.OfflineBundleResultSuffix
+}
@@ -0,0 +1,208 @@
+package offline_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/offline"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/offline/offline_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type serviceFields struct {
+	configService     *offline_mocks.MockIConfigService
+	appStateService   *offline_mocks.MockIAppStateService
+	deviceInitService *offline_mocks.MockIDeviceInitService
+	eventService      *offline_mocks.MockIEventService
+	store             *offline_mocks.MockIStore
+
+	incomingDir string
+	keysDir     string
+	privateKey  ed25519.PrivateKey
+
+	transitions   []common.IStateTransition
+	applied       map[string]bool
+	lastAppliedAt time.Time
+}
+
+func newServiceFields(t *testing.T, serialNumber string) *serviceFields {
+	f := &serviceFields{
+		configService:     offline_mocks.NewMockIConfigService(t),
+		appStateService:   offline_mocks.NewMockIAppStateService(t),
+		deviceInitService: offline_mocks.NewMockIDeviceInitService(t),
+		eventService:      offline_mocks.NewMockIEventService(t),
+		store:             offline_mocks.NewMockIStore(t),
+
+		incomingDir: t.TempDir(),
+		keysDir:     t.TempDir(),
+		applied:     make(map[string]bool),
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(f.keysDir, "staging.pub"),
+		[]byte(base64.StdEncoding.EncodeToString(publicKey)), 0o600))
+	f.privateKey = privateKey
+
+	f.configService.EXPECT().
+		GetConfig().
+		Return(config.Config{App: &config.AppSection{SerialNumber: serialNumber}}, nil).
+		Maybe()
+	f.appStateService.EXPECT().
+		Perform(mock.Anything).
+		RunAndReturn(func(transition common.IStateTransition) error {
+			f.transitions = append(f.transitions, transition)
+			return nil
+		}).
+		Maybe()
+	f.eventService.EXPECT().
+		Report(mock.Anything).
+		Maybe()
+	f.store.EXPECT().
+		IsApplied(mock.Anything).
+		RunAndReturn(func(digest string) (bool, error) {
+			return f.applied[digest], nil
+		}).
+		Maybe()
+	f.store.EXPECT().
+		LastAppliedAt().
+		RunAndReturn(func() (time.Time, error) {
+			return f.lastAppliedAt, nil
+		}).
+		Maybe()
+	f.store.EXPECT().
+		SaveApplied(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(digest string, createdAt time.Time, _ time.Duration) error {
+			f.applied[digest] = true
+			f.lastAppliedAt = createdAt
+			return nil
+		}).
+		Maybe()
+
+	return f
+}
+
+func (f *serviceFields) newService() *offline.Service {
+	return offline.NewService(f.configService, f.appStateService, f.deviceInitService, f.eventService, f.store,
+		[]string{f.incomingDir, filepath.Join(f.incomingDir, "not-mounted")}, f.keysDir, offline.DefaultInterval)
+}
+
+func (f *serviceFields) writeBundle(t *testing.T, name string, bundle entities.OfflineBundle, tamper bool) {
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(data)
+	signed := entities.SignedOfflineBundle{
+		Bundle:    data,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(f.privateKey, digest[:])),
+	}
+	if tamper {
+		signed.Bundle = bytes.ReplaceAll(data, []byte("orch.example.com"), []byte("evil.example.com"))
+	}
+
+	data, err = json.Marshal(signed)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(f.incomingDir, name), data, 0o600))
+}
+
+func (f *serviceFields) readResult(t *testing.T, name string) (result entities.OfflineBundleResult) {
+	data, err := os.ReadFile(filepath.Join(f.incomingDir, name))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	return result
+}
+
+func newZTPBundle(serialNumber string, createdAt time.Time) entities.OfflineBundle {
+	return entities.OfflineBundle{
+		FormatVersion: entities.OfflineBundleFormatVersion,
+		Kind:          entities.OfflineBundleKindZTP,
+		CreatedAt:     createdAt,
+		ZTP: &entities.OfflineZTPConfig{
+			PortConfigs:       []config.PortConfig{{Name: "wan1", Type: "wan", TableIDs: []int{100}, Wan: &config.WanConfig{Mode: "dhcp"}}},
+			SerialNumber:      serialNumber,
+			OrchestratorAddrs: []string{"orch.example.com"},
+		},
+	}
+}
+
+func TestService_Scan(t *testing.T) {
+	var (
+		f       = newServiceFields(t, "CPE-0001")
+		service = f.newService()
+		now     = time.Now()
+	)
+
+	f.writeBundle(t, "ztp.json", newZTPBundle("CPE-0001", now.Add(-time.Hour)), false)
+	f.writeBundle(t, "tampered.json", newZTPBundle("CPE-0001", now.Add(-time.Hour)), true)
+	f.writeBundle(t, "other.json", newZTPBundle("CPE-0002", now.Add(-time.Hour)), false)
+
+	// bundles are not settled yet
+	service.Scan(f.incomingDir, now)
+	require.NoFileExists(t, filepath.Join(f.incomingDir, "ztp.result.json"))
+
+	service.Scan(f.incomingDir, now.Add(time.Minute))
+	require.Len(t, f.transitions, 2)
+	require.IsType(t, new(entities.OnZTPSetupConfig), f.transitions[0])
+	require.Equal(t, entities.NewOnFirstSetup("CPE-0001", []string{"orch.example.com"}), f.transitions[1])
+
+	require.Equal(t, entities.OfflineBundleStatusApplied, f.readResult(t, "ztp.result.json").Status)
+	require.Equal(t, entities.OfflineBundleStatusRejected, f.readResult(t, "tampered.result.json").Status)
+	require.Equal(t, entities.OfflineBundleStatusRejected, f.readResult(t, "other.result.json").Status)
+
+	// processed bundles are not applied again
+	service.Scan(f.incomingDir, now.Add(time.Minute))
+	require.Len(t, f.transitions, 2)
+}
+
+func TestService_Scan_replay(t *testing.T) {
+	var (
+		f       = newServiceFields(t, "CPE-0001")
+		service = f.newService()
+		now     = time.Now()
+		bundle  = newZTPBundle("CPE-0001", now.Add(-time.Hour))
+	)
+
+	f.writeBundle(t, "ztp.json", bundle, false)
+	service.Scan(f.incomingDir, now.Add(time.Minute))
+	require.Equal(t, entities.OfflineBundleStatusApplied, f.readResult(t, "ztp.result.json").Status)
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+	}{
+		{name: "same bundle", createdAt: bundle.CreatedAt},
+		{name: "older bundle", createdAt: now.Add(-2 * time.Hour)},
+		{name: "expired bundle", createdAt: now.Add(-60 * 24 * time.Hour)},
+		{name: "bundle from future", createdAt: now.Add(48 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// copy of bundle is dropped with another name, e.g. after device restart
+			replay := bundle
+			replay.CreatedAt = tt.createdAt
+			f.writeBundle(t, "replay.json", replay, false)
+			f.newService().Scan(f.incomingDir, now.Add(time.Minute))
+
+			result := f.readResult(t, "replay.result.json")
+			require.Equal(t, entities.OfflineBundleStatusRejected, result.Status, result.Error)
+			require.NoError(t, os.Remove(filepath.Join(f.incomingDir, "replay.result.json")))
+		})
+	}
+
+	require.Len(t, f.transitions, 2)
+}
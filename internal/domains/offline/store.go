@@ -0,0 +1,87 @@
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+const (
+	lastAppliedKey   = "offline:last_applied"
+	appliedKeyPrefix = "offline:applied:"
+)
+
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	// Store keeps digests of applied bundles and creation time of the last applied one in badger,
+	// so bundle is not replayed after reboot.
+	Store struct {
+		db IBadgerDB
+	}
+)
+
+func NewStore(db IBadgerDB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// IsApplied reports whether bundle with digest was applied.
+func (s *Store) IsApplied(digest string) (applied bool, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(appliedKeyPrefix + digest))
+		return err
+	}); err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("IsApplied: %w", err)
+	}
+
+	return true, nil
+}
+
+// LastAppliedAt returns creation time of the last applied bundle, zero time if nothing is applied yet.
+func (s *Store) LastAppliedAt() (createdAt time.Time, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(lastAppliedKey))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(value []byte) error {
+			return createdAt.UnmarshalText(value)
+		})
+	}); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return createdAt, fmt.Errorf("LastAppliedAt: %w", err)
+	}
+
+	return createdAt, nil
+}
+
+// SaveApplied saves digest of applied bundle, digest is kept for ttl, and bundle creation time.
+func (s *Store) SaveApplied(digest string, createdAt time.Time, ttl time.Duration) (err error) {
+	value, err := createdAt.MarshalText()
+	if err != nil {
+		return fmt.Errorf("SaveApplied: %w", err)
+	}
+
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.SetEntry(badger.NewEntry([]byte(appliedKeyPrefix+digest), value).WithTTL(ttl)); err != nil {
+			return err
+		}
+
+		return txn.Set([]byte(lastAppliedKey), value)
+	}); err != nil {
+		return fmt.Errorf("SaveApplied: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package offline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/offline"
+)
+
+func TestStore(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := offline.NewStore(db)
+	lastAppliedAt, err := store.LastAppliedAt()
+	require.NoError(t, err)
+	require.True(t, lastAppliedAt.IsZero())
+
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveApplied("digest", createdAt, time.Hour))
+
+	applied, err := store.IsApplied("digest")
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	applied, err = store.IsApplied("other")
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	lastAppliedAt, err = store.LastAppliedAt()
+	require.NoError(t, err)
+	require.True(t, createdAt.Equal(lastAppliedAt))
+}
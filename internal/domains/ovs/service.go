@@ -1,23 +1,37 @@
 package ovs
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 
+	"github.com/dgraph-io/badger/v4"
 	"github.com/rs/zerolog/log"
 )
 
-type Service struct{}
+const managerKey = "ovs:manager"
 
-func NewService() *Service {
-	return new(Service)
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	Service struct {
+		db IBadgerDB
+	}
+)
+
+func NewService(db IBadgerDB) *Service {
+	return &Service{
+		db: db,
+	}
 }
 
 func (s *Service) SetupOVSManager(ofControllerAddr string) (err error) {
 	// example: ovs-vsctl set-manager ptcp:6640
-	listenPort := fmt.Sprintf("ptcp:%s", strings.Split(ofControllerAddr, ":")[1])
-	setManagerCmd := exec.Command("ovs-vsctl", "set-manager", listenPort)
+	setManagerCmd := exec.Command("ovs-vsctl", "set-manager", ManagerTarget(ofControllerAddr))
 	log.Debug().Msgf("Executing cmd: %s", setManagerCmd.String())
 
 	output, err := setManagerCmd.Output()
@@ -25,6 +39,54 @@ func (s *Service) SetupOVSManager(ofControllerAddr string) (err error) {
 		return fmt.Errorf("SetupOVSManager: %w: output: %s", err, string(output))
 	}
 
+	// remember controller address to check manager drift later
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(managerKey), []byte(ofControllerAddr))
+	}); err != nil {
+		return fmt.Errorf("SetupOVSManager: %w", err)
+	}
+
 	log.Debug().Msg("Manager successfully set!")
 	return nil
 }
+
+// ControllerAddr returns controller address of the last manager setup, empty if manager was never set up.
+func (s *Service) ControllerAddr() (ofControllerAddr string, err error) {
+	if err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(managerKey))
+		if err != nil {
+			return err
+		}
+
+		value, err := item.ValueCopy(nil)
+		ofControllerAddr = string(value)
+
+		return err
+	}); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return "", fmt.Errorf("ControllerAddr: %w", err)
+	}
+
+	return ofControllerAddr, nil
+}
+
+// ManagerTarget returns manager target for controller address, e.g. ptcp:6640.
+func ManagerTarget(ofControllerAddr string) string {
+	_, port, _ := strings.Cut(ofControllerAddr, ":")
+	return "ptcp:" + port
+}
+
+// Managers returns manager targets configured in OVS.
+func (s *Service) Managers() (targets []string, err error) {
+	output, err := exec.Command("ovs-vsctl", "get-manager").Output()
+	if err != nil {
+		return nil, fmt.Errorf("Managers: %w", err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,65 @@
+package pony
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	// tunnel monitoring loop reads config every second
+	monitorStallTimeout = 2 * time.Minute
+)
+
+type (
+	IMonitorConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+		UpdateConfig(ctx context.Context, cfg config.Config, updateFuncs ...config.UpdateOption) (err error)
+	}
+
+	IAppStateService interface {
+		IsTransitionRunning() bool
+	}
+
+	// ProgressConfigService is config service of the tunnel monitoring loop (sdwan-lib pony service).
+	// Every active loop iteration reads config, so config reads are used as loop heartbeat.
+	ProgressConfigService struct {
+		IMonitorConfigService
+		appStateService IAppStateService
+		heartbeat       *entities.Heartbeat
+	}
+)
+
+func NewProgressConfigService(configService IMonitorConfigService, appStateService IAppStateService) *ProgressConfigService {
+	return &ProgressConfigService{
+		IMonitorConfigService: configService,
+		appStateService:       appStateService,
+		heartbeat:             entities.NewHeartbeat(),
+	}
+}
+
+// GetConfig returns config and records monitoring loop heartbeat.
+func (s *ProgressConfigService) GetConfig() (cfg config.Config, err error) {
+	s.heartbeat.Beat()
+	return s.IMonitorConfigService.GetConfig()
+}
+
+// CheckProgress returns error if monitoring loop is stuck.
+// Monitoring is paused during state transitions, stall timeout is counted from the end of transition.
+func (s *ProgressConfigService) CheckProgress() (err error) {
+	if s.appStateService.IsTransitionRunning() {
+		s.heartbeat.Beat()
+		return nil
+	}
+
+	if since := s.heartbeat.Since(); since > monitorStallTimeout {
+		return fmt.Errorf("CheckProgress: %w: no heartbeat for %s", errs.ErrLoopStalled, since.Round(time.Second))
+	}
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/appstate/common"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/objects/bo"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/objects/dto"
 )
@@ -43,24 +44,30 @@ type (
 	IAppStateService interface {
 		Perform(transition common.IStateTransition) (err error)
 	}
+
+	IPortStatsService interface {
+		PortStats(portName string) (stats entities.PortStats, found bool)
+	}
 )
 
 type Service struct {
-	configService   IConfigService
-	netService      INetService
-	ponyService     IPonyService
-	shellService    IShellService
-	appStateService IAppStateService
+	configService    IConfigService
+	netService       INetService
+	ponyService      IPonyService
+	shellService     IShellService
+	appStateService  IAppStateService
+	portStatsService IPortStatsService
 }
 
 func NewService(configService IConfigService, netService INetService, ponyService IPonyService,
-	shellService IShellService, appStateService IAppStateService) *Service {
+	shellService IShellService, appStateService IAppStateService, portStatsService IPortStatsService) *Service {
 	return &Service{
-		configService:   configService,
-		netService:      netService,
-		ponyService:     ponyService,
-		shellService:    shellService,
-		appStateService: appStateService,
+		configService:    configService,
+		netService:       netService,
+		ponyService:      ponyService,
+		shellService:     shellService,
+		appStateService:  appStateService,
+		portStatsService: portStatsService,
 	}
 }
 
@@ -80,6 +87,7 @@ func (s *Service) GetPortRuntimeData() (ports bo.DevicePorts, err error) {
 
 	ports = bo.NewDevicePortsFromLinuxInterfaces(deviceInterfaces)
 	s.fillGatewaysAndDNS(ports)
+	s.fillStats(ports)
 
 	return ports, nil
 }
@@ -194,6 +202,15 @@ func (s *Service) fillGatewaysAndDNS(ports bo.DevicePorts) {
 	}
 }
 
+// fillStats adds sampled traffic counters and rates.
+func (s *Service) fillStats(ports bo.DevicePorts) {
+	for i, port := range ports {
+		if stats, found := s.portStatsService.PortStats(port.Name); found {
+			ports[i].Stats = &stats
+		}
+	}
+}
+
 func execCommand(cmd *exec.Cmd) (err error) {
 	if output, err := cmd.CombinedOutput(); err != nil {
 		log.Error().Err(err).Msgf("execCommand: exec error, output: %s", string(output))
@@ -0,0 +1,180 @@
+package portstats
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/netstats"
+)
+
+const (
+	DefaultInterval = 5 * time.Second
+	DefaultWindow   = time.Minute
+)
+
+type (
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	sample struct {
+		at       time.Time
+		counters entities.PortCounters
+	}
+
+	// Service samples interface counters of configured ports and computes rates over sliding window.
+	Service struct {
+		configService   IConfigService
+		sysClassNetPath string
+		interval        time.Duration
+		window          time.Duration
+
+		mx      sync.RWMutex
+		samples map[string][]sample // port name -> samples within window, oldest first
+	}
+)
+
+func NewService(configService IConfigService, sysClassNetPath string, interval, window time.Duration) *Service {
+	return &Service{
+		configService:   configService,
+		sysClassNetPath: sysClassNetPath,
+		interval:        interval,
+		window:          window,
+		samples:         make(map[string][]sample),
+	}
+}
+
+// Start starts periodic sampling of port counters.
+func (s *Service) Start(ctx context.Context) {
+	if err := s.Collect(time.Now()); err != nil {
+		log.Error().Err(err).Msg("Start: collect port counters error")
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Collect(time.Now()); err != nil {
+				log.Error().Err(err).Msg("Start: collect port counters error")
+			}
+		}
+	}
+}
+
+// Stats returns last counters and rates of all sampled ports sorted by name.
+func (s *Service) Stats() (stats []entities.PortStats) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	stats = make([]entities.PortStats, 0, len(s.samples))
+	for portName := range s.samples {
+		stats = append(stats, s.portStats(portName))
+	}
+
+	slices.SortFunc(stats, func(a, b entities.PortStats) int {
+		return strings.Compare(a.PortName, b.PortName)
+	})
+
+	return stats
+}
+
+// PortStats returns last counters and rates of the port.
+func (s *Service) PortStats(portName string) (stats entities.PortStats, found bool) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if _, found = s.samples[portName]; !found {
+		return stats, false
+	}
+
+	return s.portStats(portName), true
+}
+
+func (s *Service) portStats(portName string) entities.PortStats {
+	samples := s.samples[portName]
+	first, last := samples[0], samples[len(samples)-1]
+
+	stats := entities.PortStats{
+		PortName:  portName,
+		Counters:  last.counters,
+		SampledAt: last.at,
+	}
+	if elapsed := last.at.Sub(first.at); elapsed > 0 {
+		stats.Rates = last.counters.RatesSince(first.counters, elapsed)
+	}
+
+	return stats
+}
+
+// Collect samples counters of configured ports, ports without network interface are skipped.
+func (s *Service) Collect(now time.Time) (err error) {
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("Collect: %w", err)
+	}
+
+	current := make(map[string]entities.PortCounters)
+	for _, portName := range configuredPorts(cfg) {
+		counters, rErr := netstats.ReadCounters(s.sysClassNetPath, portName)
+		if rErr != nil {
+			// interface may be absent or removed while reading
+			log.Debug().Err(rErr).Str("port", portName).Msg("Collect: read port counters error")
+			continue
+		}
+
+		current[portName] = counters
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for portName := range s.samples {
+		if _, exists := current[portName]; !exists {
+			delete(s.samples, portName)
+		}
+	}
+
+	for portName, counters := range current {
+		samples := s.samples[portName]
+		if len(samples) > 0 && counters.Less(samples[len(samples)-1].counters) {
+			// interface recreated, counters started from zero
+			samples = nil
+		}
+
+		samples = append(samples, sample{at: now, counters: counters})
+
+		// keep the newest sample older than window as rate baseline
+		for len(samples) > 1 && now.Sub(samples[1].at) >= s.window {
+			samples = samples[1:]
+		}
+
+		s.samples[portName] = samples
+	}
+
+	return nil
+}
+
+func configuredPorts(cfg config.Config) []string {
+	if cfg.Port == nil {
+		return nil
+	}
+
+	ports := make([]string, 0, len(cfg.Port.PortConfigs))
+	for _, portConfig := range cfg.Port.PortConfigs {
+		ports = append(ports, portConfig.Name)
+	}
+
+	return ports
+}
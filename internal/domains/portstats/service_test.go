@@ -0,0 +1,99 @@
+package portstats_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/portstats"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/portstats/portstats_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func writeCounters(t *testing.T, sysPath, port string, counters entities.PortCounters) {
+	t.Helper()
+
+	statisticsPath := filepath.Join(sysPath, port, "statistics")
+	require.NoError(t, os.MkdirAll(statisticsPath, 0o755))
+	for name, value := range map[string]uint64{
+		"rx_bytes":   counters.RxBytes,
+		"tx_bytes":   counters.TxBytes,
+		"rx_packets": counters.RxPackets,
+		"tx_packets": counters.TxPackets,
+		"rx_errors":  counters.RxErrors,
+		"tx_errors":  counters.TxErrors,
+		"rx_dropped": counters.RxDropped,
+		"tx_dropped": counters.TxDropped,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(statisticsPath, name), []byte(strconv.FormatUint(value, 10)+"\n"), 0o600))
+	}
+}
+
+func TestService_Collect(t *testing.T) {
+	var (
+		sysPath       = t.TempDir()
+		configService = portstats_mocks.NewMockIConfigService(t)
+		service       = portstats.NewService(configService, sysPath, portstats.DefaultInterval, 20*time.Second)
+		now           = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	)
+
+	configService.EXPECT().
+		GetConfig().
+		Return(config.Config{
+			Port: &config.PortSection{
+				PortConfigs: config.PortConfigs{
+					{Name: "port1"},
+					{Name: "port2"},
+					{Name: "port3"}, // no network interface
+				},
+			},
+		}, nil).
+		Maybe()
+
+	writeCounters(t, sysPath, "port1", entities.PortCounters{RxBytes: 1000, TxBytes: 500, RxPackets: 10})
+	writeCounters(t, sysPath, "port2", entities.PortCounters{})
+	writeCounters(t, sysPath, "veth1", entities.PortCounters{RxBytes: 1}) // not configured
+	require.NoError(t, service.Collect(now))
+
+	// only configured ports with network interface are sampled
+	require.Len(t, service.Stats(), 2)
+	_, found := service.PortStats("veth1")
+	require.False(t, found)
+
+	// single sample, no rates yet
+	stats, found := service.PortStats("port1")
+	require.True(t, found)
+	require.Equal(t, uint64(1000), stats.Counters.RxBytes)
+	require.Equal(t, entities.PortRates{}, stats.Rates)
+
+	writeCounters(t, sysPath, "port1", entities.PortCounters{RxBytes: 11000, TxBytes: 2500, RxPackets: 110, RxDropped: 10})
+	require.NoError(t, service.Collect(now.Add(10*time.Second)))
+
+	stats, _ = service.PortStats("port1")
+	require.Equal(t, entities.PortRates{RxBytes: 1000, TxBytes: 200, RxPackets: 10, RxDropped: 1}, stats.Rates)
+
+	// rate is computed over window, oldest samples are dropped
+	writeCounters(t, sysPath, "port1", entities.PortCounters{RxBytes: 11000, TxBytes: 2500, RxPackets: 110, RxDropped: 10})
+	require.NoError(t, service.Collect(now.Add(20*time.Second)))
+	require.NoError(t, service.Collect(now.Add(30*time.Second)))
+
+	stats, _ = service.PortStats("port1")
+	require.Equal(t, entities.PortRates{}, stats.Rates)
+	require.Equal(t, now.Add(30*time.Second), stats.SampledAt)
+
+	// recreated interface restarts window, removed interface is forgotten
+	writeCounters(t, sysPath, "port1", entities.PortCounters{RxBytes: 100})
+	require.NoError(t, os.RemoveAll(filepath.Join(sysPath, "port2")))
+	require.NoError(t, service.Collect(now.Add(40*time.Second)))
+
+	all := service.Stats()
+	require.Len(t, all, 1)
+	require.Equal(t, "port1", all[0].PortName)
+	require.Equal(t, uint64(100), all[0].Counters.RxBytes)
+	require.Equal(t, entities.PortRates{}, all[0].Rates)
+}
@@ -0,0 +1,135 @@
+package reachability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	IMessagePublisher interface {
+		PublishResponse(sourceMessage wschat.WebsocketMessage, body any) (err error)
+		PublishErrorResponse(sourceMessage wschat.WebsocketMessage, statusCode int, errMsg string) (err error)
+	}
+
+	IReachabilityService interface {
+		Ping(request entities.PingRequest) (results []entities.PingResult, err error)
+		Traceroute(request entities.TracerouteRequest) (results []entities.TracerouteResult, err error)
+		DiscoverPathMTU(request entities.PathMTURequest) (results []entities.PathMTUResult, err error)
+	}
+
+	Handler struct {
+		messagePublisher    IMessagePublisher
+		reachabilityService IReachabilityService
+
+		validate *validator.Validate
+	}
+)
+
+func NewHandler(messagePublisher IMessagePublisher, reachabilityService IReachabilityService) *Handler {
+	return &Handler{
+		messagePublisher:    messagePublisher,
+		reachabilityService: reachabilityService,
+
+		validate: validator.New(),
+	}
+}
+
+// Ping pings destination from device.
+func (h *Handler) Ping(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.PingRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+
+	results, err := h.reachabilityService.Ping(requestBody)
+	if err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, results); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+
+	return nil
+}
+
+// Traceroute traces route to destination from device.
+func (h *Handler) Traceroute(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.TracerouteRequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("Traceroute: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("Traceroute: %w", err)
+	}
+
+	results, err := h.reachabilityService.Traceroute(requestBody)
+	if err != nil {
+		return fmt.Errorf("Traceroute: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, results); err != nil {
+		return fmt.Errorf("Traceroute: %w", err)
+	}
+
+	return nil
+}
+
+// DiscoverPathMTU searches path MTU to destination.
+func (h *Handler) DiscoverPathMTU(request wschat.WebsocketMessage) (err error) {
+	defer func() {
+		if err != nil {
+			if sendErr := h.messagePublisher.PublishErrorResponse(request, http.StatusInternalServerError, err.Error()); sendErr != nil {
+				err = errors.Join(sendErr, err)
+			}
+		}
+	}()
+
+	var requestBody entities.PathMTURequest
+	if err = json.Unmarshal(request.Body, &requestBody); err != nil {
+		return fmt.Errorf("DiscoverPathMTU: %w", err)
+	}
+
+	if err = h.validate.Struct(requestBody); err != nil {
+		return fmt.Errorf("DiscoverPathMTU: %w", err)
+	}
+
+	results, err := h.reachabilityService.DiscoverPathMTU(requestBody)
+	if err != nil {
+		return fmt.Errorf("DiscoverPathMTU: %w", err)
+	}
+
+	if err = h.messagePublisher.PublishResponse(request, results); err != nil {
+		return fmt.Errorf("DiscoverPathMTU: %w", err)
+	}
+
+	return nil
+}
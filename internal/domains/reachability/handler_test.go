@@ -0,0 +1,41 @@
+package reachability
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func Test_validateProbePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    entities.ProbePath
+		wantErr bool
+	}{
+		{name: "empty", path: entities.ProbePath{}},
+		{name: "interface and vrf", path: entities.ProbePath{SourceInterface: "eth0.100", VRF: "vrf-blue"}},
+		{name: "option interface", path: entities.ProbePath{SourceInterface: "-f"}, wantErr: true},
+		{name: "option vrf", path: entities.ProbePath{VRF: "--help"}, wantErr: true},
+		{name: "interface with space", path: entities.ProbePath{SourceInterface: "eth0 -f"}, wantErr: true},
+		{name: "vrf with pipe", path: entities.ProbePath{VRF: "a|b"}, wantErr: true},
+		{name: "vrf with semicolon", path: entities.ProbePath{VRF: "a;reboot"}, wantErr: true},
+		{name: "long interface", path: entities.ProbePath{SourceInterface: "interface-name-16"}, wantErr: true},
+	}
+
+	validate := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := entities.PingRequest{ProbePath: tt.path, Destination: "10.0.0.1"}
+			err := validate.Struct(request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package reachability
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+var (
+	pingReplyRegexp   = regexp.MustCompile(`icmp_seq=(\d+)\s+ttl=(\d+)\s+time=([\d.]+)\s*ms`)
+	pingSummaryRegexp = regexp.MustCompile(`(\d+) packets transmitted, (\d+) (?:packets )?received`)
+	pingRTTRegexp     = regexp.MustCompile(`= ([\d.]+)/([\d.]+)/([\d.]+)/[\d.]+ ms`)
+	traceHopRegexp    = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+)
+
+// parsePingOutput parses iputils ping output. Probes without reply are marked as lost.
+func parsePingOutput(output []byte, count int) (result entities.PingResult) {
+	replies := make(map[int]entities.PingProbe)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := pingReplyRegexp.FindStringSubmatch(line); match != nil {
+			seq, _ := strconv.Atoi(match[1])
+			ttl, _ := strconv.Atoi(match[2])
+			rtt, _ := strconv.ParseFloat(match[3], 64)
+			replies[seq] = entities.PingProbe{
+				Seq:   seq,
+				TTL:   ttl,
+				RTTMs: rtt,
+			}
+
+			continue
+		}
+
+		if match := pingSummaryRegexp.FindStringSubmatch(line); match != nil {
+			result.Transmitted, _ = strconv.Atoi(match[1])
+			result.Received, _ = strconv.Atoi(match[2])
+
+			continue
+		}
+
+		if match := pingRTTRegexp.FindStringSubmatch(line); match != nil {
+			result.MinRTTMs, _ = strconv.ParseFloat(match[1], 64)
+			result.AvgRTTMs, _ = strconv.ParseFloat(match[2], 64)
+			result.MaxRTTMs, _ = strconv.ParseFloat(match[3], 64)
+		}
+	}
+
+	if result.Transmitted == 0 {
+		result.Transmitted = count
+	}
+
+	result.Probes = make([]entities.PingProbe, 0, result.Transmitted)
+	for seq := 1; seq <= result.Transmitted; seq++ {
+		probe, ok := replies[seq]
+		if !ok {
+			probe = entities.PingProbe{
+				Seq:  seq,
+				Lost: true,
+			}
+		}
+
+		result.Probes = append(result.Probes, probe)
+	}
+
+	if result.Transmitted > 0 {
+		result.LossPercent = float64(result.Transmitted-result.Received) * 100 / float64(result.Transmitted)
+	}
+
+	return result
+}
+
+// parseTracerouteOutput parses numeric (-n) traceroute output, e.g.:
+//
+//	1  10.0.0.1  0.512 ms  0.470 ms *
+//	2  10.1.0.1  1.201 ms 10.1.0.2  1.304 ms  1.250 ms
+func parseTracerouteOutput(output []byte) (hops []entities.TraceHop) {
+	hops = make([]entities.TraceHop, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := traceHopRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			// header or empty line
+			continue
+		}
+
+		hopNumber, _ := strconv.Atoi(match[1])
+		hop := entities.TraceHop{
+			Hop:    hopNumber,
+			Probes: make([]entities.TraceProbe, 0),
+		}
+
+		var (
+			addr   string
+			fields = strings.Fields(match[2])
+		)
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			switch {
+			case field == "*":
+				hop.Probes = append(hop.Probes, entities.TraceProbe{Lost: true})
+
+			case net.ParseIP(field) != nil:
+				addr = field
+
+			case i+1 < len(fields) && fields[i+1] == "ms":
+				rtt, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					continue
+				}
+
+				hop.Probes = append(hop.Probes, entities.TraceProbe{
+					Addr:  addr,
+					RTTMs: rtt,
+				})
+				i++ // skip "ms"
+			}
+			// annotations like !H, !N are skipped
+		}
+
+		hop.LossPercent, hop.AvgRTTMs = traceHopStats(hop.Probes)
+		hops = append(hops, hop)
+	}
+
+	return hops
+}
+
+func traceHopStats(probes []entities.TraceProbe) (lossPercent, avgRTT float64) {
+	if len(probes) == 0 {
+		return 100, 0
+	}
+
+	var (
+		lost int
+		sum  float64
+	)
+	for _, probe := range probes {
+		if probe.Lost {
+			lost++
+			continue
+		}
+
+		sum += probe.RTTMs
+	}
+
+	if received := len(probes) - lost; received > 0 {
+		avgRTT = sum / float64(received)
+	}
+
+	return float64(lost) * 100 / float64(len(probes)), avgRTT
+}
@@ -0,0 +1,64 @@
+package reachability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+func Test_parsePingOutput(t *testing.T) {
+	output := []byte(`PING 10.223.1.1 (10.223.1.1) 56(84) bytes of data.
+64 bytes from 10.223.1.1: icmp_seq=1 ttl=64 time=0.512 ms
+64 bytes from 10.223.1.1: icmp_seq=3 ttl=64 time=1.25 ms
+
+--- 10.223.1.1 ping statistics ---
+3 packets transmitted, 2 received, 33.3333% packet loss, time 2003ms
+rtt min/avg/max/mdev = 0.512/0.881/1.250/0.369 ms
+`)
+
+	result := parsePingOutput(output, 3)
+	require.Equal(t, 3, result.Transmitted)
+	require.Equal(t, 2, result.Received)
+	require.InDelta(t, 33.33, result.LossPercent, 0.01)
+	require.InDelta(t, 0.881, result.AvgRTTMs, 0.0001)
+	require.Equal(t, []entities.PingProbe{
+		{Seq: 1, TTL: 64, RTTMs: 0.512},
+		{Seq: 2, Lost: true},
+		{Seq: 3, TTL: 64, RTTMs: 1.25},
+	}, result.Probes)
+}
+
+func Test_parsePingOutputNoReply(t *testing.T) {
+	result := parsePingOutput(nil, 2)
+	require.Equal(t, 2, result.Transmitted)
+	require.Equal(t, float64(100), result.LossPercent)
+	require.Len(t, result.Probes, 2)
+}
+
+func Test_parseTracerouteOutput(t *testing.T) {
+	output := []byte(`traceroute to 8.8.8.8 (8.8.8.8), 30 hops max, 60 byte packets
+ 1  192.168.1.1  0.512 ms  0.470 ms *
+ 2  10.1.0.1  2.000 ms 10.1.0.2  4.000 ms !H  3.000 ms
+ 3  * * *
+`)
+
+	hops := parseTracerouteOutput(output)
+	require.Len(t, hops, 3)
+
+	require.Equal(t, 1, hops[0].Hop)
+	require.Len(t, hops[0].Probes, 3)
+	require.Equal(t, "192.168.1.1", hops[0].Probes[0].Addr)
+	require.True(t, hops[0].Probes[2].Lost)
+	require.InDelta(t, 33.33, hops[0].LossPercent, 0.01)
+
+	require.Equal(t, []entities.TraceProbe{
+		{Addr: "10.1.0.1", RTTMs: 2},
+		{Addr: "10.1.0.2", RTTMs: 4},
+		{Addr: "10.1.0.2", RTTMs: 3},
+	}, hops[1].Probes)
+	require.InDelta(t, 3, hops[1].AvgRTTMs, 0.0001)
+
+	require.Equal(t, float64(100), hops[2].LossPercent)
+}
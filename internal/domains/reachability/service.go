@@ -0,0 +1,326 @@
+package reachability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/shellcmd"
+)
+
+const (
+	defaultPingCount        = 5
+	defaultPingIntervalMs   = 1000
+	defaultTimeoutMs        = 1000
+	defaultTraceMaxHops     = 30
+	defaultTraceProbes      = 3
+	defaultMinMTU           = 576
+	defaultMaxMTU           = 1500
+	ipICMPHeadersSize       = 28 // ipv4 (20) + icmp (8) headers
+	maxParallelProbePaths   = 8
+	probeCommandOutputLimit = 1 << 20
+)
+
+type (
+	IShellService interface {
+		ExecOutput(command shell.ICommand) (output []byte, err error)
+	}
+
+	IConfigService interface {
+		GetConfig() (cfg config.Config, err error)
+	}
+
+	Service struct {
+		shellService  IShellService
+		configService IConfigService
+	}
+)
+
+func NewService(shellService IShellService, configService IConfigService) *Service {
+	return &Service{
+		shellService:  shellService,
+		configService: configService,
+	}
+}
+
+// Ping sends icmp echo requests through every requested path.
+func (s *Service) Ping(request entities.PingRequest) (results []entities.PingResult, err error) {
+	count := valueOrDefault(request.Count, defaultPingCount)
+	paths, err := s.resolvePaths(request.ProbePath, request.Destination)
+	if err != nil {
+		return results, fmt.Errorf("Ping: %w", err)
+	}
+
+	results = make([]entities.PingResult, len(paths))
+	s.forEachPath(paths, func(i int, path entities.ProbePathInfo) {
+		args := []string{
+			"ping", "-n",
+			"-c", strconv.Itoa(count),
+			"-i", formatSeconds(valueOrDefault(request.IntervalMs, defaultPingIntervalMs)),
+			"-W", formatSeconds(valueOrDefault(request.TimeoutMs, defaultTimeoutMs)),
+		}
+		if request.PacketSize > 0 {
+			args = append(args, "-s", strconv.Itoa(request.PacketSize))
+		}
+		if path.Interface != "" {
+			args = append(args, "-I", path.Interface)
+		}
+		args = append(args, request.Destination)
+
+		output, _ := s.exec(path, args) // ping exits with non-zero code when packets are lost
+		results[i] = parsePingOutput(output, count)
+		results[i].Path = path
+	})
+
+	return results, nil
+}
+
+// Traceroute traces route to destination through every requested path.
+func (s *Service) Traceroute(request entities.TracerouteRequest) (results []entities.TracerouteResult, err error) {
+	paths, err := s.resolvePaths(request.ProbePath, request.Destination)
+	if err != nil {
+		return results, fmt.Errorf("Traceroute: %w", err)
+	}
+
+	results = make([]entities.TracerouteResult, len(paths))
+	s.forEachPath(paths, func(i int, path entities.ProbePathInfo) {
+		args := []string{
+			"traceroute", "-n",
+			"-m", strconv.Itoa(valueOrDefault(request.MaxHops, defaultTraceMaxHops)),
+			"-q", strconv.Itoa(valueOrDefault(request.Probes, defaultTraceProbes)),
+			"-w", formatSeconds(valueOrDefault(request.TimeoutMs, defaultTimeoutMs)),
+		}
+		if path.Interface != "" {
+			args = append(args, "-i", path.Interface)
+		}
+		if path.Source != "" {
+			args = append(args, "-s", path.Source)
+		}
+		args = append(args, request.Destination)
+
+		results[i].Path = path
+		output, execErr := s.exec(path, args)
+		if execErr != nil && len(output) == 0 {
+			results[i].Path.Error = execErr.Error()
+			return
+		}
+
+		results[i].Hops = parseTracerouteOutput(output)
+	})
+
+	return results, nil
+}
+
+// DiscoverPathMTU searches maximum MTU passing to destination without fragmentation (binary search).
+func (s *Service) DiscoverPathMTU(request entities.PathMTURequest) (results []entities.PathMTUResult, err error) {
+	var (
+		minMTU = valueOrDefault(request.MinMTU, defaultMinMTU)
+		maxMTU = valueOrDefault(request.MaxMTU, defaultMaxMTU)
+	)
+	if minMTU > maxMTU {
+		return results, fmt.Errorf("DiscoverPathMTU: min mtu %d is greater than max mtu %d", minMTU, maxMTU)
+	}
+
+	paths, err := s.resolvePaths(request.ProbePath, request.Destination)
+	if err != nil {
+		return results, fmt.Errorf("DiscoverPathMTU: %w", err)
+	}
+
+	results = make([]entities.PathMTUResult, len(paths))
+	s.forEachPath(paths, func(i int, path entities.ProbePathInfo) {
+		results[i].Path = path
+		results[i].Probes = make([]entities.MTUProbe, 0)
+
+		probe := func(mtu int) bool {
+			args := []string{
+				"ping", "-n", "-c", "1",
+				"-M", "do",
+				"-W", formatSeconds(valueOrDefault(request.TimeoutMs, defaultTimeoutMs)),
+				"-s", strconv.Itoa(mtu - ipICMPHeadersSize),
+			}
+			if path.Interface != "" {
+				args = append(args, "-I", path.Interface)
+			}
+			args = append(args, request.Destination)
+
+			output, _ := s.exec(path, args)
+			passed := parsePingOutput(output, 1).Received > 0
+			results[i].Probes = append(results[i].Probes, entities.MTUProbe{
+				MTU:    mtu,
+				Passed: passed,
+			})
+
+			return passed
+		}
+
+		low, high := minMTU, maxMTU
+		if !probe(low) {
+			results[i].Path.Error = fmt.Sprintf("destination is not reachable with minimal mtu %d", low)
+			return
+		}
+
+		for low < high {
+			mid := (low + high + 1) / 2
+			if probe(mid) {
+				low = mid
+			} else {
+				high = mid - 1
+			}
+		}
+
+		results[i].PathMTU = low
+	})
+
+	return results, nil
+}
+
+// resolvePaths builds probe paths from request: one path per pony tunnel or single path otherwise.
+func (s *Service) resolvePaths(probePath entities.ProbePath, destination string) (paths []entities.ProbePathInfo, err error) {
+	if len(probePath.Tunnels) == 0 {
+		path := entities.ProbePathInfo{
+			TableID:   probePath.TableID,
+			VRF:       probePath.VRF,
+			Interface: probePath.SourceInterface,
+		}
+		if probePath.TableID > 0 && path.Interface == "" {
+			path = s.resolveTablePath(path, destination)
+		}
+
+		return []entities.ProbePathInfo{path}, nil
+	}
+
+	cfg, err := s.configService.GetConfig()
+	if err != nil {
+		return paths, fmt.Errorf("resolvePaths: %w", err)
+	}
+
+	for _, tunnel := range probePath.Tunnels {
+		path := entities.ProbePathInfo{
+			Tunnel: tunnel,
+			VRF:    probePath.VRF,
+		}
+
+		tableID, found := searchTunnelTable(cfg, tunnel)
+		if !found {
+			path.Error = fmt.Sprintf("pony tunnel %s not found", tunnel)
+			paths = append(paths, path)
+			continue
+		}
+
+		path.TableID = tableID
+		paths = append(paths, s.resolveTablePath(path, destination))
+	}
+
+	return paths, nil
+}
+
+// resolveTablePath resolves egress interface and source address for destination in routing table.
+func (s *Service) resolveTablePath(path entities.ProbePathInfo, destination string) entities.ProbePathInfo {
+	destIP, err := lookupIPv4(destination)
+	if err != nil {
+		path.Error = err.Error()
+		return path
+	}
+
+	output, err := s.shellService.ExecOutput(
+		shellcmd.New("ip", "-j", "route", "get", destIP, "table", strconv.Itoa(path.TableID)),
+	)
+	if err != nil {
+		path.Error = fmt.Sprintf("route lookup in table %d failed: %s", path.TableID, err)
+		return path
+	}
+
+	var routes []struct {
+		Dev     string `json:"dev"`
+		PrefSrc string `json:"prefsrc"`
+	}
+	if err = json.Unmarshal(output, &routes); err != nil || len(routes) == 0 {
+		path.Error = fmt.Sprintf("route to %s not found in table %d", destIP, path.TableID)
+		return path
+	}
+
+	path.Interface = routes[0].Dev
+	path.Source = routes[0].PrefSrc
+
+	return path
+}
+
+// exec executes probe command, wraps it with "ip vrf exec" for VRF path.
+func (s *Service) exec(path entities.ProbePathInfo, args []string) (output []byte, err error) {
+	if path.Error != "" {
+		return nil, fmt.Errorf("exec: %s", path.Error)
+	}
+
+	if path.VRF != "" {
+		args = append([]string{"ip", "vrf", "exec", path.VRF}, args...)
+	}
+
+	output, err = s.shellService.ExecOutput(shellcmd.New(args[0], args[1:]...))
+	if len(output) > probeCommandOutputLimit {
+		output = output[:probeCommandOutputLimit]
+	}
+
+	return output, err
+}
+
+func (s *Service) forEachPath(paths []entities.ProbePathInfo, fn func(i int, path entities.ProbePathInfo)) {
+	p := pool.New().WithMaxGoroutines(maxParallelProbePaths)
+	for i, path := range paths {
+		p.Go(func() {
+			fn(i, path)
+		})
+	}
+	p.Wait()
+}
+
+func searchTunnelTable(cfg config.Config, tunnel string) (tableID int, found bool) {
+	if cfg.Pony == nil {
+		return 0, false
+	}
+
+	for _, cluster := range cfg.Pony.Clusters {
+		if uplink, err := cluster.GetUplinkConfigByAddr(tunnel); err == nil {
+			return uplink.TableID, true
+		}
+	}
+
+	return 0, false
+}
+
+func lookupIPv4(host string) (string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return "", fmt.Errorf("lookupIPv4: %w", err)
+	}
+
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("lookupIPv4: ipv4 address for %s not found", host)
+}
+
+func valueOrDefault(value, defaultValue int) int {
+	if value > 0 {
+		return value
+	}
+
+	return defaultValue
+}
+
+// formatSeconds formats milliseconds as seconds for ping/traceroute arguments.
+func formatSeconds(ms int) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+}
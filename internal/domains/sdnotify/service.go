@@ -0,0 +1,216 @@
+// Package sdnotify implements systemd notify protocol: readiness, status and watchdog pings.
+package sdnotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	statusInterval = 5 * time.Second
+	// bootExtendFactor start timeout is extended by several ping intervals, so a late tick does not fail the start
+	bootExtendFactor = 3
+)
+
+type (
+	IAppStateService interface {
+		Booted() <-chan struct{}
+		ActiveState() entities.AppState
+	}
+
+	IProgressProbe interface {
+		CheckProgress() (err error)
+	}
+
+	Service struct {
+		appStateService  IAppStateService
+		bootProbe        IProgressProbe
+		probes           map[string]IProgressProbe
+		socketAddr       string
+		watchdogInterval time.Duration
+	}
+)
+
+// NewService creates service, notify socket and watchdog interval are taken from env set by systemd.
+// Without NOTIFY_SOCKET (agent is not started by systemd) the service does nothing.
+// Boot probe checks progress of boot transitions, probes check loops after boot.
+func NewService(appStateService IAppStateService, bootProbe IProgressProbe, probes map[string]IProgressProbe) *Service {
+	return &Service{
+		appStateService:  appStateService,
+		bootProbe:        bootProbe,
+		probes:           probes,
+		socketAddr:       os.Getenv("NOTIFY_SOCKET"),
+		watchdogInterval: watchdogInterval(),
+	}
+}
+
+// Start sends READY=1 when boot transitions (including post-upgrade health gate) are done. Boot may last longer
+// than start timeout of the unit, so while it is making progress the timeout is extended and watchdog is pinged,
+// stuck boot is stopped by systemd. After boot it updates status and pings watchdog while all monitored loops
+// are making progress.
+func (s *Service) Start(ctx context.Context) {
+	if lo.IsEmpty(s.socketAddr) {
+		log.Debug().Msg("Start: notify socket is not set, systemd notifications disabled")
+		return
+	}
+
+	interval := statusInterval
+	if s.watchdogInterval > 0 {
+		// ping twice per watchdog timeout
+		interval = min(interval, s.watchdogInterval/2)
+	}
+
+	status := s.status()
+	if err := s.notify(status); err != nil {
+		log.Error().Err(err).Msg("Start")
+	}
+	s.keepBooting(interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	booted := s.appStateService.Booted()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-booted:
+			booted = nil
+			status = s.status()
+			if err := s.notify("READY=1", status); err != nil {
+				log.Error().Err(err).Msg("Start")
+			}
+
+		case <-ticker.C:
+			if newStatus := s.status(); newStatus != status {
+				status = newStatus
+				if err := s.notify(status); err != nil {
+					log.Error().Err(err).Msg("Start")
+				}
+			}
+
+			if booted != nil {
+				s.keepBooting(interval)
+				break
+			}
+
+			if s.watchdogInterval == 0 {
+				break
+			}
+
+			if err := s.checkProgress(); err != nil {
+				log.Warn().Err(err).Msg("Start: watchdog ping skipped")
+				break
+			}
+
+			if err := s.notify("WATCHDOG=1"); err != nil {
+				log.Error().Err(err).Msg("Start")
+			}
+		}
+	}
+}
+
+// Stopping tells systemd that agent is shutting down.
+func (s *Service) Stopping() {
+	if lo.IsEmpty(s.socketAddr) {
+		return
+	}
+
+	if err := s.notify("STOPPING=1", "STATUS=stopping"); err != nil {
+		log.Error().Err(err).Msg("Stopping")
+	}
+}
+
+func (s *Service) status() string {
+	if !s.booted() {
+		return fmt.Sprintf("STATUS=booting, app state: %s", s.appStateService.ActiveState())
+	}
+
+	return fmt.Sprintf("STATUS=app state: %s", s.appStateService.ActiveState())
+}
+
+func (s *Service) booted() bool {
+	select {
+	case <-s.appStateService.Booted():
+		return true
+	default:
+		return false
+	}
+}
+
+// keepBooting extends start timeout of the unit and pings watchdog if boot transitions are making progress.
+func (s *Service) keepBooting(interval time.Duration) {
+	if err := s.bootProbe.CheckProgress(); err != nil {
+		log.Warn().Err(err).Msg("keepBooting: start timeout is not extended")
+		return
+	}
+
+	assignments := []string{"EXTEND_TIMEOUT_USEC=" + strconv.FormatInt((bootExtendFactor*interval).Microseconds(), 10)}
+	if s.watchdogInterval > 0 {
+		assignments = append(assignments, "WATCHDOG=1")
+	}
+
+	if err := s.notify(assignments...); err != nil {
+		log.Error().Err(err).Msg("keepBooting")
+	}
+}
+
+func (s *Service) checkProgress() (err error) {
+	names := lo.Keys(s.probes)
+	slices.Sort(names)
+
+	var probeErrs []error
+	for _, name := range names {
+		if pErr := s.probes[name].CheckProgress(); pErr != nil {
+			probeErrs = append(probeErrs, fmt.Errorf("%s: %w", name, pErr))
+		}
+	}
+
+	if err = errors.Join(probeErrs...); err != nil {
+		return fmt.Errorf("checkProgress: %w", err)
+	}
+
+	return nil
+}
+
+// notify sends newline separated assignments to notify socket, "@" prefix selects abstract socket.
+func (s *Service) notify(assignments ...string) (err error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.socketAddr, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(strings.Join(assignments, "\n"))); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+
+	return nil
+}
+
+// watchdogInterval returns watchdog timeout configured by WatchdogSec, zero if watchdog is disabled.
+func watchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); !lo.IsEmpty(pid) && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
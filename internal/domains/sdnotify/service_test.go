@@ -0,0 +1,154 @@
+package sdnotify_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/sdnotify"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/domains/sdnotify/sdnotify_mocks"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+type serviceFields struct {
+	appStateService *sdnotify_mocks.MockIAppStateService
+	stateProbe      *sdnotify_mocks.MockIProgressProbe
+	websocketProbe  *sdnotify_mocks.MockIProgressProbe
+
+	booted      chan struct{}
+	stalled     atomic.Bool // websocket loop
+	bootStalled atomic.Bool // state loop
+}
+
+func newServiceFields(t *testing.T) *serviceFields {
+	f := &serviceFields{
+		appStateService: sdnotify_mocks.NewMockIAppStateService(t),
+		stateProbe:      sdnotify_mocks.NewMockIProgressProbe(t),
+		websocketProbe:  sdnotify_mocks.NewMockIProgressProbe(t),
+
+		booted: make(chan struct{}),
+	}
+
+	f.appStateService.EXPECT().
+		Booted().
+		RunAndReturn(func() <-chan struct{} {
+			return f.booted
+		}).
+		Maybe()
+	f.appStateService.EXPECT().
+		ActiveState().
+		Return(entities.AppStateActive).
+		Maybe()
+	f.stateProbe.EXPECT().
+		CheckProgress().
+		RunAndReturn(func() error {
+			if f.bootStalled.Load() {
+				return errs.ErrLoopStalled
+			}
+			return nil
+		}).
+		Maybe()
+	f.websocketProbe.EXPECT().
+		CheckProgress().
+		RunAndReturn(func() error {
+			if f.stalled.Load() {
+				return errs.ErrLoopStalled
+			}
+			return nil
+		}).
+		Maybe()
+
+	return f
+}
+
+func listenNotify(t *testing.T) (read func(timeout time.Duration) (msg string, ok bool)) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+	t.Setenv("WATCHDOG_USEC", "100000")
+
+	return func(timeout time.Duration) (string, bool) {
+		buf := make([]byte, 256)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(timeout)))
+		n, rErr := conn.Read(buf)
+		if rErr != nil {
+			return "", false
+		}
+		return string(buf[:n]), true
+	}
+}
+
+func TestService_Start(t *testing.T) {
+	var (
+		read    = listenNotify(t)
+		f       = newServiceFields(t)
+		service = sdnotify.NewService(f.appStateService, f.stateProbe, map[string]sdnotify.IProgressProbe{
+			"state loop":     f.stateProbe,
+			"websocket loop": f.websocketProbe,
+		})
+	)
+
+	// waits for the next message matching expected one, skipping the others
+	waitFor := func(expected string) {
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if msg, ok := read(time.Second); ok && msg == expected {
+				return
+			}
+		}
+		require.Failf(t, "notification is not sent", "expected %q", expected)
+	}
+
+	// skips messages sent before the last change, then reads messages until silence,
+	// none of them may contain unexpected one
+	requireNone := func(unexpected string) {
+		time.Sleep(100 * time.Millisecond)
+		for {
+			if _, ok := read(10 * time.Millisecond); !ok {
+				break
+			}
+		}
+
+		for {
+			msg, ok := read(300 * time.Millisecond)
+			if !ok {
+				return
+			}
+			require.NotContains(t, msg, unexpected)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.Start(ctx)
+
+	// booting: ready is not sent, start timeout is extended and watchdog is pinged while boot is making progress
+	f.stalled.Store(true)
+	msg, ok := read(time.Second)
+	require.True(t, ok)
+	require.Equal(t, "STATUS=booting, app state: active", msg)
+	waitFor("EXTEND_TIMEOUT_USEC=150000\nWATCHDOG=1")
+
+	// stuck boot is neither extended nor pinged
+	f.bootStalled.Store(true)
+	requireNone("EXTEND_TIMEOUT_USEC")
+	f.bootStalled.Store(false)
+	waitFor("EXTEND_TIMEOUT_USEC=150000\nWATCHDOG=1")
+
+	// booted: ready is sent, stalled loop stops watchdog pings
+	close(f.booted)
+	waitFor("READY=1\nSTATUS=app state: active")
+	requireNone("WATCHDOG=1")
+
+	f.stalled.Store(false)
+	waitFor("WATCHDOG=1")
+}
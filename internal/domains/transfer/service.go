@@ -0,0 +1,92 @@
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const (
+	DefaultChunkSize = 256 << 10 // 256KB
+	chunkSendTimeout = 10 * time.Second
+)
+
+type (
+	IMessagePublisher interface {
+		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
+	}
+
+	// Service sends large payloads (pcap, profiles) to orchestrator in chunks,
+	// every chunk waits for orchestrator acknowledge.
+	Service struct {
+		messagePublisher IMessagePublisher
+		chunkSize        int
+	}
+)
+
+func NewService(messagePublisher IMessagePublisher, chunkSize int) *Service {
+	return &Service{
+		messagePublisher: messagePublisher,
+		chunkSize:        chunkSize,
+	}
+}
+
+// Send sends payload to orchestrator split into chunks.
+func (s *Service) Send(transferID, kind string, data []byte) (err error) {
+	checksum := sha256.Sum256(data)
+	chunks := SplitChunks(data, s.chunkSize)
+
+	for i, chunk := range chunks {
+		resp, err := s.messagePublisher.PublishRequest(constants.MethodTransferChunk, constants.OrchestratorWSID,
+			entities.TransferChunk{
+				TransferID: transferID,
+				Kind:       kind,
+				Index:      i,
+				Total:      len(chunks),
+				Size:       len(data),
+				SHA256:     hex.EncodeToString(checksum[:]),
+				Data:       chunk,
+			},
+			wschat.RequestOptions{
+				Timeout: lo.ToPtr(chunkSendTimeout),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("Send: chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+
+		if resp.IsErrorResponse() {
+			return fmt.Errorf("Send: chunk %d/%d: %w", i+1, len(chunks), resp.Error())
+		}
+	}
+
+	log.Debug().
+		Str("transfer id", transferID).
+		Str("kind", kind).
+		Int("size", len(data)).
+		Int("chunks", len(chunks)).
+		Msg("Send: transfer finished")
+
+	return nil
+}
+
+// SplitChunks splits data into chunks of chunkSize, empty data produces single empty chunk.
+func SplitChunks(data []byte, chunkSize int) (chunks [][]byte) {
+	if len(data) == 0 || chunkSize <= 0 {
+		return [][]byte{data}
+	}
+
+	for start := 0; start < len(data); start += chunkSize {
+		chunks = append(chunks, data[start:min(start+chunkSize, len(data))])
+	}
+
+	return chunks
+}
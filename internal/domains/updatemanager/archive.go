@@ -0,0 +1,71 @@
+package updatemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const archAll = "all"
+
+var (
+	// debian policy: package names (5.6.1) and versions (5.6.12, epoch is optional), no path separators
+	packageNameRegexp    = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+$`)
+	packageVersionRegexp = regexp.MustCompile(`^([0-9]+:)?[0-9][A-Za-z0-9.+~-]*$`)
+)
+
+// goArchToDpkg maps go architecture to debian one, used if dpkg is not available.
+var goArchToDpkg = map[string]string{
+	"amd64": "amd64",
+	"arm64": "arm64",
+	"arm":   "armhf",
+	"386":   "i386",
+}
+
+// DpkgArchitecture returns debian architecture of device.
+func DpkgArchitecture() (arch string, err error) {
+	output, err := exec.Command("dpkg", "--print-architecture").Output()
+	if err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	arch, found := goArchToDpkg[runtime.GOARCH]
+	if !found {
+		return "", fmt.Errorf("DpkgArchitecture: unknown architecture %s: %w", runtime.GOARCH, err)
+	}
+
+	return arch, nil
+}
+
+// ValidatePackage checks package name and version against debian charsets.
+func ValidatePackage(name, version string) (err error) {
+	if !packageNameRegexp.MatchString(name) || !packageVersionRegexp.MatchString(version) {
+		return fmt.Errorf("ValidatePackage: %w: %q %q", errs.ErrInvalidPackage, name, version)
+	}
+
+	return nil
+}
+
+// FindArchive returns path of cached package archive for architecture (or architecture independent one).
+func FindArchive(archivesDir, name, version, arch string) (path string, err error) {
+	if err = ValidatePackage(name, version); err != nil {
+		return "", fmt.Errorf("FindArchive: %w", err)
+	}
+
+	// apt escapes epoch colon in archive file names
+	escapedVersion := strings.ReplaceAll(version, ":", "%3a")
+	for _, archiveArch := range []string{arch, archAll} {
+		path = filepath.Join(archivesDir, fmt.Sprintf("%s_%s_%s.deb", name, escapedVersion, archiveArch))
+		if _, err = os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("FindArchive: %w: %s %s (%s)", errs.ErrPackageArchiveNotFound, name, version, arch)
+}
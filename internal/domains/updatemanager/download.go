@@ -0,0 +1,286 @@
+package updatemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	downloadJobRequestTimeout = 5 * time.Second
+	publishProgressTimeout    = 5 * time.Second
+	progressRelayInterval     = 2 * time.Second // progress of the same package is relayed not more often than interval
+	maxDownloadJobs           = 20
+)
+
+// Download creates download job and makes download request to update manager in background.
+func (s *Service) Download(request entities.DownloadPackageRequest) (job entities.DownloadJob, err error) {
+	now := time.Now()
+	newJob := &entities.DownloadJob{
+		ID:        uuid.NewString(),
+		State:     entities.DownloadStateQueued,
+		Packages:  request.PackagesToDownload,
+		Progress:  make([]entities.DownloadProgress, 0, len(request.PackagesToDownload)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.jobsMx.Lock()
+	s.jobs = append(s.jobs, newJob)
+	if len(s.jobs) > maxDownloadJobs {
+		s.evictJobs(s.jobs[:len(s.jobs)-maxDownloadJobs])
+		s.jobs = s.jobs[len(s.jobs)-maxDownloadJobs:]
+	}
+	job = copyJob(newJob)
+	s.jobsMx.Unlock()
+
+	request.JobID = job.ID
+	go func() {
+		// update manager only accepts or rejects the job, download outcome is reported by progress
+		err := s.requestUpdateManager(constants.MQUpdateManagerDownload, request, downloadJobRequestTimeout)
+		switch {
+		case errors.Is(err, nats.ErrTimeout):
+			log.Warn().
+				Err(err).
+				Str("job", request.JobID).
+				Msg("Download: download request is not acknowledged, waiting for progress")
+
+		case err != nil:
+			log.Error().
+				Err(err).
+				Str("job", request.JobID).
+				Msg("Download: download request error")
+
+			s.updateJob(request.JobID, func(job *entities.DownloadJob) {
+				job.State = entities.DownloadStateFailed
+				job.Error = err.Error()
+			})
+		}
+	}()
+
+	return job, nil
+}
+
+// CancelDownload cancels download job, partially downloaded archives are kept for resume.
+func (s *Service) CancelDownload(jobID string) (err error) {
+	if _, err = s.GetDownloadJob(jobID); err != nil {
+		return fmt.Errorf("CancelDownload: %w", err)
+	}
+
+	if err = s.requestUpdateManager(constants.MQUpdateManagerDownloadCancel, entities.DownloadJobRequest{JobID: jobID},
+		downloadJobRequestTimeout); err != nil {
+		return fmt.Errorf("CancelDownload: %w", err)
+	}
+
+	s.updateJob(jobID, func(job *entities.DownloadJob) {
+		setUnfinishedProgress(job, entities.DownloadStateCanceled)
+		job.State = jobState(job)
+		if job.State != entities.DownloadStateCompleted {
+			job.State = entities.DownloadStateCanceled
+		}
+	})
+
+	return nil
+}
+
+// ResumeDownload resumes canceled or failed download job.
+func (s *Service) ResumeDownload(jobID string) (err error) {
+	if _, err = s.GetDownloadJob(jobID); err != nil {
+		return fmt.Errorf("ResumeDownload: %w", err)
+	}
+
+	if err = s.requestUpdateManager(constants.MQUpdateManagerDownloadResume, entities.DownloadJobRequest{JobID: jobID},
+		downloadJobRequestTimeout); err != nil {
+		return fmt.Errorf("ResumeDownload: %w", err)
+	}
+
+	s.updateJob(jobID, func(job *entities.DownloadJob) {
+		setUnfinishedProgress(job, entities.DownloadStateQueued)
+		job.State = jobState(job)
+		job.Error = ""
+	})
+
+	return nil
+}
+
+// GetDownloadJob returns download job by id.
+func (s *Service) GetDownloadJob(jobID string) (job entities.DownloadJob, err error) {
+	s.jobsMx.Lock()
+	defer s.jobsMx.Unlock()
+
+	found, exists := lo.Find(s.jobs, func(item *entities.DownloadJob) bool {
+		return item.ID == jobID
+	})
+	if !exists {
+		return job, fmt.Errorf("GetDownloadJob: %w: %s", errs.ErrDownloadJobNotFound, jobID)
+	}
+
+	return copyJob(found), nil
+}
+
+// GetDownloadJobs returns recent download jobs.
+func (s *Service) GetDownloadJobs() (jobs []entities.DownloadJob) {
+	s.jobsMx.Lock()
+	defer s.jobsMx.Unlock()
+
+	return lo.Map(s.jobs, func(item *entities.DownloadJob, _ int) entities.DownloadJob {
+		return copyJob(item)
+	})
+}
+
+// ReportProgress updates job with package progress from update manager and relays it to orchestrator.
+func (s *Service) ReportProgress(progress entities.DownloadProgress) (err error) {
+	s.jobsMx.Lock()
+	job, exists := lo.Find(s.jobs, func(item *entities.DownloadJob) bool {
+		return item.ID == progress.JobID
+	})
+	if !exists {
+		s.jobsMx.Unlock()
+		return fmt.Errorf("ReportProgress: %w: %s", errs.ErrDownloadJobNotFound, progress.JobID)
+	}
+
+	var stateChanged bool
+	if _, index, found := lo.FindIndexOf(job.Progress, func(item entities.DownloadProgress) bool {
+		return item.Package == progress.Package
+	}); found {
+		stateChanged = job.Progress[index].State != progress.State
+		job.Progress[index] = progress
+	} else {
+		stateChanged = true
+		job.Progress = append(job.Progress, progress)
+	}
+
+	job.State = jobState(job)
+	if job.State != entities.DownloadStateFailed {
+		job.Error = ""
+	}
+	job.UpdatedAt = time.Now()
+
+	// relay state changes at once, intermediate progress is throttled
+	relayKey := progress.JobID + "/" + progress.Package
+	relay := stateChanged || time.Since(s.lastProgress[relayKey]) >= progressRelayInterval
+	if relay {
+		s.lastProgress[relayKey] = job.UpdatedAt
+	}
+	if progress.State != entities.DownloadStateDownloading && progress.State != entities.DownloadStateQueued {
+		delete(s.lastProgress, relayKey)
+	}
+	s.jobsMx.Unlock()
+
+	if relay {
+		go s.publishProgress(progress)
+	}
+
+	return nil
+}
+
+func (s *Service) publishProgress(progress entities.DownloadProgress) {
+	if !s.progressPublisher.IsActive() {
+		return
+	}
+
+	resp, err := s.progressPublisher.PublishRequest(constants.MethodDownloadPackagesProgress, constants.OrchestratorWSID, progress,
+		wschat.RequestOptions{
+			Timeout: lo.ToPtr(publishProgressTimeout),
+		},
+	)
+	if err == nil && resp.IsErrorResponse() {
+		err = resp.Error()
+	}
+
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Str("job", progress.JobID).
+			Msg("publishProgress: publish download progress error")
+	}
+}
+
+func (s *Service) updateJob(jobID string, update func(job *entities.DownloadJob)) {
+	s.jobsMx.Lock()
+	defer s.jobsMx.Unlock()
+
+	if job, exists := lo.Find(s.jobs, func(item *entities.DownloadJob) bool {
+		return item.ID == jobID
+	}); exists {
+		update(job)
+		job.UpdatedAt = time.Now()
+	}
+}
+
+// evictJobs forgets relayed progress of evicted jobs, must be called under jobsMx.
+func (s *Service) evictJobs(jobs []*entities.DownloadJob) {
+	for _, job := range jobs {
+		for _, progress := range job.Progress {
+			delete(s.lastProgress, job.ID+"/"+progress.Package)
+		}
+	}
+}
+
+func (s *Service) requestUpdateManager(subject string, request any, timeout time.Duration) (err error) {
+	resp, err := s.mqService.Request(subject, request, timeout)
+	if err != nil {
+		return fmt.Errorf("requestUpdateManager: %w", err)
+	}
+
+	var mqResp mq.Response
+	if err = json.Unmarshal(resp.Data, &mqResp); err != nil {
+		return fmt.Errorf("requestUpdateManager: %w", err)
+	}
+
+	if mqResp.IsError() {
+		return fmt.Errorf("requestUpdateManager: %w", mqResp.Error())
+	}
+
+	return nil
+}
+
+// jobState returns job state by its packages: failed or canceled if any package is, completed if all are.
+func jobState(job *entities.DownloadJob) string {
+	states := lo.Map(job.Progress, func(item entities.DownloadProgress, _ int) string {
+		return item.State
+	})
+
+	switch {
+	case lo.Contains(states, entities.DownloadStateFailed):
+		return entities.DownloadStateFailed
+	case lo.Contains(states, entities.DownloadStateCanceled):
+		return entities.DownloadStateCanceled
+	case len(states) >= len(job.Packages) && lo.EveryBy(states, func(state string) bool {
+		return state == entities.DownloadStateCompleted
+	}):
+		return entities.DownloadStateCompleted
+	case lo.Contains(states, entities.DownloadStateDownloading) || lo.Contains(states, entities.DownloadStateCompleted):
+		return entities.DownloadStateDownloading
+	}
+
+	return entities.DownloadStateQueued
+}
+
+// setUnfinishedProgress sets state of not completed packages.
+func setUnfinishedProgress(job *entities.DownloadJob, state string) {
+	for i := range job.Progress {
+		if job.Progress[i].State != entities.DownloadStateCompleted {
+			job.Progress[i].State = state
+		}
+	}
+}
+
+func copyJob(job *entities.DownloadJob) entities.DownloadJob {
+	result := *job
+	result.Progress = append(make([]entities.DownloadProgress, 0, len(job.Progress)), job.Progress...)
+
+	return result
+}
@@ -0,0 +1,137 @@
+package updatemanager
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+func TestJobState(t *testing.T) {
+	packages := entities.PackageItems{
+		{Name: "sdwan-agent", Version: "1.2.0"},
+		{Name: "sdwan-pony", Version: "1.1.0"},
+	}
+
+	progress := func(states ...string) []entities.DownloadProgress {
+		result := make([]entities.DownloadProgress, 0, len(states))
+		for i, state := range states {
+			result = append(result, entities.DownloadProgress{Package: packages[i].Name, State: state})
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		progress []entities.DownloadProgress
+		want     string
+	}{
+		{
+			name: "no progress",
+			want: entities.DownloadStateQueued,
+		},
+		{
+			name:     "one package downloading",
+			progress: progress(entities.DownloadStateDownloading),
+			want:     entities.DownloadStateDownloading,
+		},
+		{
+			name:     "one of two packages completed",
+			progress: progress(entities.DownloadStateCompleted),
+			want:     entities.DownloadStateDownloading,
+		},
+		{
+			name:     "all packages completed",
+			progress: progress(entities.DownloadStateCompleted, entities.DownloadStateCompleted),
+			want:     entities.DownloadStateCompleted,
+		},
+		{
+			name:     "package canceled",
+			progress: progress(entities.DownloadStateCompleted, entities.DownloadStateCanceled),
+			want:     entities.DownloadStateCanceled,
+		},
+		{
+			name:     "package failed",
+			progress: progress(entities.DownloadStateCanceled, entities.DownloadStateFailed),
+			want:     entities.DownloadStateFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &entities.DownloadJob{Packages: packages, Progress: tt.progress}
+			require.Equal(t, tt.want, jobState(job))
+		})
+	}
+}
+
+type mqStub struct{}
+
+func (mqStub) Request(string, any, time.Duration, ...mq.RequestOption) (*nats.Msg, error) {
+	data, err := json.Marshal(mq.NewOkResponse())
+	return &nats.Msg{Data: data}, err
+}
+
+type publisherStub struct{}
+
+func (publisherStub) IsActive() bool { return false }
+
+func (publisherStub) PublishRequest(string, string, any, ...wschat.RequestOptions) (wschat.WebsocketMessage, error) {
+	return wschat.WebsocketMessage{}, nil
+}
+
+func TestService_CancelResumeDownload(t *testing.T) {
+	s := NewService(nil, mqStub{}, publisherStub{}, "")
+	job := &entities.DownloadJob{
+		ID:       "job",
+		State:    entities.DownloadStateFailed,
+		Packages: entities.PackageItems{{Name: "sdwan-agent"}, {Name: "sdwan-pony"}},
+		Progress: []entities.DownloadProgress{
+			{JobID: "job", Package: "sdwan-agent", State: entities.DownloadStateCompleted},
+			{JobID: "job", Package: "sdwan-pony", State: entities.DownloadStateFailed},
+		},
+		Error: "connection reset",
+	}
+	s.jobs = append(s.jobs, job)
+
+	require.NoError(t, s.ResumeDownload(job.ID))
+	resumed, err := s.GetDownloadJob(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, entities.DownloadStateDownloading, resumed.State)
+	require.Equal(t, entities.DownloadStateQueued, resumed.Progress[1].State)
+	require.Empty(t, resumed.Error)
+
+	require.NoError(t, s.CancelDownload(job.ID))
+	canceled, err := s.GetDownloadJob(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, entities.DownloadStateCanceled, canceled.State)
+	require.Equal(t, entities.DownloadStateCompleted, canceled.Progress[0].State)
+	require.Equal(t, entities.DownloadStateCanceled, canceled.Progress[1].State)
+}
+
+func TestService_Download_evictsProgress(t *testing.T) {
+	s := NewService(nil, mqStub{}, publisherStub{}, "")
+	first, err := s.Download(entities.DownloadPackageRequest{PackagesToDownload: entities.PackageItems{{Name: "sdwan-agent"}}})
+	require.NoError(t, err)
+	require.NoError(t, s.ReportProgress(entities.DownloadProgress{
+		JobID: first.ID, Package: "sdwan-agent", State: entities.DownloadStateDownloading,
+	}))
+	require.Len(t, s.lastProgress, 1)
+
+	for range maxDownloadJobs {
+		_, err = s.Download(entities.DownloadPackageRequest{})
+		require.NoError(t, err)
+	}
+
+	_, err = s.GetDownloadJob(first.ID)
+	require.ErrorIs(t, err, errs.ErrDownloadJobNotFound)
+	require.Empty(t, s.lastProgress)
+}
@@ -15,7 +15,10 @@ import (
 
 type (
 	IService interface {
-		Download(request entities.DownloadPackageRequest) (err error)
+		Download(request entities.DownloadPackageRequest) (job entities.DownloadJob, err error)
+		CancelDownload(jobID string) (err error)
+		ResumeDownload(jobID string) (err error)
+		GetDownloadJobs() (jobs []entities.DownloadJob)
 		Install(request entities.InstallPackageRequest) (err error)
 		GetVersions() (versions entities.ActualPackageVersions, err error)
 	}
@@ -26,16 +29,22 @@ type (
 		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
 	}
 
+	IVerifier interface {
+		VerifyPackages(packages entities.PackageItems) (err error)
+	}
+
 	Handler struct {
 		service   IService
 		publisher IMessagePublisher
+		verifier  IVerifier
 	}
 )
 
-func NewHandler(service IService, publisher IMessagePublisher) *Handler {
+func NewHandler(service IService, publisher IMessagePublisher, verifier IVerifier) *Handler {
 	return &Handler{
 		service:   service,
 		publisher: publisher,
+		verifier:  verifier,
 	}
 }
 
@@ -55,17 +64,81 @@ func (h *Handler) DownloadDevicePackages(message wschat.WebsocketMessage) (err e
 
 	log.Debug().Any("request", request).Msg("DownloadDevicePackages")
 
-	if err = h.service.Download(request); err != nil {
+	// download runs in background, progress is sent by job id
+	job, err := h.service.Download(request)
+	if err != nil {
 		return fmt.Errorf("DownloadDevicePackages: %w", err)
 	}
 
-	if err = h.publisher.PublishResponse(message, []byte{}); err != nil {
+	if err = h.publisher.PublishResponse(message, job); err != nil {
 		return fmt.Errorf("DownloadDevicePackages: %w", err)
 	}
 
 	return nil
 }
 
+func (h *Handler) CancelDownloadPackages(message wschat.WebsocketMessage) (err error) {
+	defer func() {
+		err = h.handleErrorForPublisher(message, err)
+	}()
+
+	var request entities.DownloadJobRequest
+	if err = json.Unmarshal(message.Body, &request); err != nil {
+		return fmt.Errorf("CancelDownloadPackages: %w", err)
+	}
+
+	if err = validator.Validator.Struct(request); err != nil {
+		return fmt.Errorf("CancelDownloadPackages: %w", err)
+	}
+
+	if err = h.service.CancelDownload(request.JobID); err != nil {
+		return fmt.Errorf("CancelDownloadPackages: %w", err)
+	}
+
+	if err = h.publisher.PublishResponse(message, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("CancelDownloadPackages: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) ResumeDownloadPackages(message wschat.WebsocketMessage) (err error) {
+	defer func() {
+		err = h.handleErrorForPublisher(message, err)
+	}()
+
+	var request entities.DownloadJobRequest
+	if err = json.Unmarshal(message.Body, &request); err != nil {
+		return fmt.Errorf("ResumeDownloadPackages: %w", err)
+	}
+
+	if err = validator.Validator.Struct(request); err != nil {
+		return fmt.Errorf("ResumeDownloadPackages: %w", err)
+	}
+
+	if err = h.service.ResumeDownload(request.JobID); err != nil {
+		return fmt.Errorf("ResumeDownloadPackages: %w", err)
+	}
+
+	if err = h.publisher.PublishResponse(message, wschat.EmptyBody); err != nil {
+		return fmt.Errorf("ResumeDownloadPackages: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) GetDownloadJobs(message wschat.WebsocketMessage) (err error) {
+	defer func() {
+		err = h.handleErrorForPublisher(message, err)
+	}()
+
+	if err = h.publisher.PublishResponse(message, h.service.GetDownloadJobs()); err != nil {
+		return fmt.Errorf("GetDownloadJobs: %w", err)
+	}
+
+	return nil
+}
+
 func (h *Handler) InstallDevicePackages(message wschat.WebsocketMessage) (err error) {
 	defer func() {
 		err = h.handleErrorForPublisher(message, err)
@@ -82,6 +155,11 @@ func (h *Handler) InstallDevicePackages(message wschat.WebsocketMessage) (err er
 
 	log.Debug().Any("request", request).Msg("InstallDevicePackages")
 
+	// reject tampered archives before device goes to maintenance
+	if err = h.verifier.VerifyPackages(request.PackagesToInstall); err != nil {
+		return fmt.Errorf("InstallDevicePackages: %w", err)
+	}
+
 	if err = h.publisher.PublishResponse(message, wschat.EmptyBody); err != nil {
 		return fmt.Errorf("InstallDevicePackages: %w", err)
 	}
@@ -0,0 +1,49 @@
+package updatemanager
+
+import (
+	"encoding/json"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/go-playground/validator/v10"
+	"github.com/nats-io/nats.go"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+type (
+	IProgressReporter interface {
+		ReportProgress(progress entities.DownloadProgress) (err error)
+	}
+
+	MQHandler struct {
+		progressReporter IProgressReporter
+
+		validate *validator.Validate
+	}
+)
+
+func NewMQHandler(progressReporter IProgressReporter) *MQHandler {
+	return &MQHandler{
+		progressReporter: progressReporter,
+
+		validate: validator.New(),
+	}
+}
+
+// DownloadProgress accepts package download progress from update manager.
+func (h *MQHandler) DownloadProgress(message *nats.Msg) (resp any) {
+	var progress entities.DownloadProgress
+	if err := json.Unmarshal(message.Data, &progress); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.validate.Struct(progress); err != nil {
+		return mq.NewBadRequestResponse(err.Error())
+	}
+
+	if err := h.progressReporter.ReportProgress(progress); err != nil {
+		return mq.NewInternalErrorResponse(err.Error())
+	}
+
+	return mq.NewOkResponse()
+}
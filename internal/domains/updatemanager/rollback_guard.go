@@ -0,0 +1,137 @@
+package updatemanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/shellcmd"
+)
+
+const (
+	AgentRollbackUnitName = "sdwan-agent-rollback"
+
+	unitStateActivating = "activating"
+)
+
+type (
+	IShellService interface {
+		Exec(command shell.ICommand) (err error)
+		ExecOutput(command shell.ICommand) (output []byte, err error)
+	}
+
+	// RollbackGuard schedules install of the previous agent package in transient systemd timer before agent
+	// self upgrade. The timer belongs to systemd, so previous agent is restored even if the new one crashes
+	// before its health gate or can not run the gate at all. The new agent disarms the timer when the gate
+	// is finished. The timer does not survive device reboot.
+	RollbackGuard struct {
+		shellService IShellService
+		archivesDir  string
+		unitName     string
+	}
+)
+
+func NewRollbackGuard(shellService IShellService, archivesDir, unitName string) *RollbackGuard {
+	return &RollbackGuard{
+		shellService: shellService,
+		archivesDir:  archivesDir,
+		unitName:     unitName,
+	}
+}
+
+// Arm schedules install of previous agent version after specified delay, previously armed timer is replaced.
+func (g *RollbackGuard) Arm(previousVersion string, after time.Duration) (err error) {
+	arch, err := DpkgArchitecture()
+	if err != nil {
+		return fmt.Errorf("Arm: %w", err)
+	}
+
+	archive, err := FindArchive(g.archivesDir, constants.SdwanAgentPackageName, previousVersion, arch)
+	if err != nil {
+		return fmt.Errorf("Arm: %w", err)
+	}
+
+	if err = g.Disarm(); err != nil {
+		return fmt.Errorf("Arm: %w", err)
+	}
+
+	// oneshot service stays active after successful install, so the restored agent can tell it was rolled back
+	if err = g.shellService.Exec(shellcmd.New("systemd-run",
+		"--unit="+g.unitName,
+		"--on-active="+strconv.Itoa(int(after.Seconds()))+"s",
+		"--timer-property=AccuracySec=1s",
+		"--property=Type=oneshot",
+		"--property=RemainAfterExit=yes",
+		"--",
+		"dpkg", "-i", "--force-confold", archive,
+	)); err != nil {
+		return fmt.Errorf("Arm: %w", err)
+	}
+
+	log.Info().
+		Str("archive", archive).
+		Dur("after", after).
+		Msg("Arm: agent rollback timer is armed")
+
+	return nil
+}
+
+// Fired returns true if the timer has started install of previous agent version.
+func (g *RollbackGuard) Fired() (fired bool, err error) {
+	state, err := g.unitState(g.unitName + ".service")
+	if err != nil {
+		return false, fmt.Errorf("Fired: %w", err)
+	}
+
+	return state == unitStateActivating || state == unitStateActive || state == unitStateFailed, nil
+}
+
+// Disarm cancels rollback timer and clears the result of fired one. Running install is not interrupted.
+func (g *RollbackGuard) Disarm() (err error) {
+	var (
+		timerUnit   = g.unitName + ".timer"
+		serviceUnit = g.unitName + ".service"
+	)
+	timerState, err := g.unitState(timerUnit)
+	if err != nil {
+		return fmt.Errorf("Disarm: %w", err)
+	}
+
+	if timerState == unitStateActive {
+		if err = g.shellService.Exec(shellcmd.New("systemctl", "stop", timerUnit)); err != nil {
+			return fmt.Errorf("Disarm: %w", err)
+		}
+	}
+
+	serviceState, err := g.unitState(serviceUnit)
+	if err != nil {
+		return fmt.Errorf("Disarm: %w", err)
+	}
+
+	switch serviceState {
+	case unitStateActive:
+		err = g.shellService.Exec(shellcmd.New("systemctl", "stop", serviceUnit))
+	case unitStateFailed:
+		err = g.shellService.Exec(shellcmd.New("systemctl", "reset-failed", serviceUnit))
+	}
+	if err != nil {
+		return fmt.Errorf("Disarm: %w", err)
+	}
+
+	return nil
+}
+
+// unitState returns active state of unit, not loaded unit is inactive.
+func (g *RollbackGuard) unitState(unit string) (state string, err error) {
+	output, err := g.shellService.ExecOutput(shellcmd.New("systemctl", "show", "--property=ActiveState", "--value", unit))
+	if err != nil {
+		return "", fmt.Errorf("unitState: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,76 @@
+package updatemanager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/shell"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+)
+
+// fakeShellService records commands and returns unit states for systemctl show.
+type fakeShellService struct {
+	states   map[string]string
+	commands []string
+}
+
+func (s *fakeShellService) Exec(command shell.ICommand) (err error) {
+	_, err = s.ExecOutput(command)
+	return err
+}
+
+func (s *fakeShellService) ExecOutput(command shell.ICommand) (output []byte, err error) {
+	args := command.Args()
+	if command.Name() == "systemctl" && args[0] == "show" {
+		return []byte(s.states[args[len(args)-1]] + "\n"), nil
+	}
+
+	s.commands = append(s.commands, strings.Join(append([]string{command.Name()}, args...), " "))
+
+	return nil, nil
+}
+
+func TestRollbackGuard(t *testing.T) {
+	archivesDir := t.TempDir()
+
+	arch, err := DpkgArchitecture()
+	require.NoError(t, err)
+
+	archive := filepath.Join(archivesDir, constants.SdwanAgentPackageName+"_1.1.0_"+arch+".deb")
+	require.NoError(t, os.WriteFile(archive, nil, 0o600))
+
+	var (
+		shellService = &fakeShellService{states: map[string]string{"sdwan-agent-rollback.timer": "active"}}
+		guard        = NewRollbackGuard(shellService, archivesDir, AgentRollbackUnitName)
+	)
+
+	// stale timer is replaced
+	require.NoError(t, guard.Arm("1.1.0", 20*time.Minute))
+	require.Equal(t, []string{
+		"systemctl stop sdwan-agent-rollback.timer",
+		"systemd-run --unit=sdwan-agent-rollback --on-active=1200s --timer-property=AccuracySec=1s " +
+			"--property=Type=oneshot --property=RemainAfterExit=yes -- dpkg -i --force-confold " + archive,
+	}, shellService.commands)
+
+	fired, err := guard.Fired()
+	require.NoError(t, err)
+	require.False(t, fired)
+
+	// fired timer: previous agent is installed, result is cleared by disarm
+	shellService.commands = nil
+	shellService.states = map[string]string{"sdwan-agent-rollback.service": "active"}
+	fired, err = guard.Fired()
+	require.NoError(t, err)
+	require.True(t, fired)
+
+	require.NoError(t, guard.Disarm())
+	require.Equal(t, []string{"systemctl stop sdwan-agent-rollback.service"}, shellService.commands)
+
+	// archive of previous version is required
+	require.Error(t, guard.Arm("1.0.0", time.Minute))
+}
@@ -0,0 +1,193 @@
+package updatemanager
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+)
+
+const (
+	DefaultUnitStartTimeout = time.Minute
+	defaultUnitPollInterval = time.Second
+	defaultUnitSettleTime   = 5 * time.Second // unit must stay active to not miss crash loops
+	unitStateActive         = "active"
+	unitStateFailed         = "failed"
+)
+
+const (
+	SelfUpgradeStageArchive = "archive"
+	SelfUpgradeStageInstall = "install"
+	SelfUpgradeStageStart   = "start"
+)
+
+type (
+	// SelfUpgradeError failed update manager upgrade with captured dpkg output.
+	SelfUpgradeError struct {
+		Version         string
+		PreviousVersion string
+		Stage           string
+		UnitState       string
+		DpkgOutput      string
+		RolledBack      bool
+		RollbackError   error
+		Err             error
+	}
+
+	// SelfUpgrader installs new update manager package, which can not install itself, and restores
+	// the previous version if unit does not come up.
+	SelfUpgrader struct {
+		archivesDir  string
+		unitName     string
+		startTimeout time.Duration
+		pollInterval time.Duration
+		settleTime   time.Duration
+
+		run func(name string, args ...string) (output []byte, err error)
+	}
+)
+
+func (e *SelfUpgradeError) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "update manager %s %s failed", e.Version, e.Stage)
+	if !lo.IsEmpty(e.UnitState) {
+		fmt.Fprintf(&builder, " (unit state %s)", e.UnitState)
+	}
+	fmt.Fprintf(&builder, ": %v", e.Err)
+
+	switch {
+	case e.RolledBack:
+		fmt.Fprintf(&builder, "; restored %s", e.PreviousVersion)
+	case e.RollbackError != nil:
+		fmt.Fprintf(&builder, "; restore %s failed: %v", e.PreviousVersion, e.RollbackError)
+	}
+
+	if !lo.IsEmpty(e.DpkgOutput) {
+		fmt.Fprintf(&builder, "; dpkg output: %s", e.DpkgOutput)
+	}
+
+	return builder.String()
+}
+
+func (e *SelfUpgradeError) Unwrap() error {
+	return e.Err
+}
+
+func NewSelfUpgrader(archivesDir, unitName string, startTimeout time.Duration) *SelfUpgrader {
+	return &SelfUpgrader{
+		archivesDir:  archivesDir,
+		unitName:     unitName,
+		startTimeout: startTimeout,
+		pollInterval: defaultUnitPollInterval,
+		settleTime:   defaultUnitSettleTime,
+
+		run: func(name string, args ...string) ([]byte, error) {
+			return exec.Command(name, args...).CombinedOutput()
+		},
+	}
+}
+
+// Upgrade installs update manager version and waits for unit is active, restores previous version on failure.
+func (u *SelfUpgrader) Upgrade(version, previousVersion string) (err error) {
+	upgradeErr := &SelfUpgradeError{
+		Version:         version,
+		PreviousVersion: previousVersion,
+	}
+
+	arch, err := DpkgArchitecture()
+	if err != nil {
+		upgradeErr.Stage, upgradeErr.Err = SelfUpgradeStageArchive, err
+		return fmt.Errorf("Upgrade: %w", upgradeErr)
+	}
+
+	archive, err := FindArchive(u.archivesDir, constants.SdwanUpdateManagerPackageName, version, arch)
+	if err != nil {
+		upgradeErr.Stage, upgradeErr.Err = SelfUpgradeStageArchive, err
+		return fmt.Errorf("Upgrade: %w", upgradeErr)
+	}
+
+	log.Info().
+		Str("archive", archive).
+		Msg("Upgrade: install update manager")
+
+	output, err := u.run("dpkg", "-i", "--force-confold", archive)
+	upgradeErr.DpkgOutput = strings.TrimSpace(string(output))
+	if err != nil {
+		upgradeErr.Stage, upgradeErr.Err = SelfUpgradeStageInstall, err
+		u.restore(upgradeErr, arch)
+		return fmt.Errorf("Upgrade: %w", upgradeErr)
+	}
+
+	if upgradeErr.UnitState, err = u.waitUnitActive(); err != nil {
+		upgradeErr.Stage, upgradeErr.Err = SelfUpgradeStageStart, err
+		u.restore(upgradeErr, arch)
+		return fmt.Errorf("Upgrade: %w", upgradeErr)
+	}
+
+	return nil
+}
+
+func (u *SelfUpgrader) restore(upgradeErr *SelfUpgradeError, arch string) {
+	if lo.IsEmpty(upgradeErr.PreviousVersion) {
+		return
+	}
+
+	archive, err := FindArchive(u.archivesDir, constants.SdwanUpdateManagerPackageName, upgradeErr.PreviousVersion, arch)
+	if err != nil {
+		upgradeErr.RollbackError = err
+		return
+	}
+
+	output, err := u.run("dpkg", "-i", "--force-confold", archive)
+	if err != nil {
+		upgradeErr.RollbackError = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+
+	if _, err = u.waitUnitActive(); err != nil {
+		upgradeErr.RollbackError = err
+		return
+	}
+
+	upgradeErr.RolledBack = true
+}
+
+// waitUnitActive polls unit state until it stays active for settle time, returns the last seen state.
+func (u *SelfUpgrader) waitUnitActive() (state string, err error) {
+	var (
+		deadline    = time.Now().Add(u.startTimeout)
+		activeSince time.Time
+	)
+	for {
+		output, err := u.run("systemctl", "show", "--property=ActiveState", "--value", u.unitName)
+		if err != nil {
+			return state, fmt.Errorf("waitUnitActive: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+
+		state = strings.TrimSpace(string(output))
+		switch {
+		case state != unitStateActive:
+			activeSince = time.Time{}
+		case activeSince.IsZero():
+			activeSince = time.Now()
+		case time.Since(activeSince) >= u.settleTime:
+			return state, nil
+		}
+
+		if time.Now().After(deadline) {
+			if state == unitStateFailed {
+				return state, errors.New("waitUnitActive: unit failed")
+			}
+
+			return state, fmt.Errorf("waitUnitActive: unit is not active after %s", u.startTimeout)
+		}
+
+		time.Sleep(u.pollInterval)
+	}
+}
@@ -0,0 +1,73 @@
+package updatemanager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+)
+
+func TestSelfUpgrader_Upgrade(t *testing.T) {
+	archivesDir := t.TempDir()
+
+	arch, err := DpkgArchitecture()
+	require.NoError(t, err)
+
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		name := constants.SdwanUpdateManagerPackageName + "_" + version + "_" + arch + ".deb"
+		require.NoError(t, os.WriteFile(filepath.Join(archivesDir, name), nil, 0o600))
+	}
+
+	newUpgrader := func(brokenVersion string) (*SelfUpgrader, *[]string) {
+		var (
+			installed string
+			commands  []string
+			upgrader  = NewSelfUpgrader(archivesDir, constants.UpdateManagerServiceName, 50*time.Millisecond)
+		)
+		upgrader.pollInterval = time.Millisecond
+		upgrader.settleTime = 5 * time.Millisecond
+		upgrader.run = func(name string, args ...string) ([]byte, error) {
+			commands = append(commands, name)
+			if name == "dpkg" {
+				installed = args[len(args)-1]
+				return []byte("Setting up " + filepath.Base(installed)), nil
+			}
+
+			if strings.Contains(installed, brokenVersion) {
+				return []byte(unitStateFailed), nil
+			}
+
+			return []byte(unitStateActive), nil
+		}
+
+		return upgrader, &commands
+	}
+
+	// unit is active
+	upgrader, _ := newUpgrader("none")
+	require.NoError(t, upgrader.Upgrade("1.1.0", "1.0.0"))
+
+	// unit does not come up, previous version is restored
+	upgrader, commands := newUpgrader("1.2.0")
+	err = upgrader.Upgrade("1.2.0", "1.1.0")
+
+	var upgradeErr *SelfUpgradeError
+	require.True(t, errors.As(err, &upgradeErr))
+	require.Equal(t, SelfUpgradeStageStart, upgradeErr.Stage)
+	require.Equal(t, unitStateFailed, upgradeErr.UnitState)
+	require.True(t, upgradeErr.RolledBack)
+	require.Contains(t, upgradeErr.DpkgOutput, "1.2.0")
+	require.Equal(t, 2, strings.Count(strings.Join(*commands, " "), "dpkg"))
+
+	// archive is missing
+	upgrader, _ = newUpgrader("none")
+	err = upgrader.Upgrade("2.0.0", "1.0.0")
+	require.True(t, errors.As(err, &upgradeErr))
+	require.Equal(t, SelfUpgradeStageArchive, upgradeErr.Stage)
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sync"
 	"time"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/mq"
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
 	"github.com/nats-io/nats.go"
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
@@ -15,9 +17,8 @@ import (
 )
 
 const (
-	downloadDevicePackagesTimeout = time.Second * 30
-	getDevicePackagesTimeout      = time.Minute
-	getDevicePackagesRetryAmount  = 5
+	getDevicePackagesTimeout     = time.Minute
+	getDevicePackagesRetryAmount = 5
 )
 
 type (
@@ -29,18 +30,32 @@ type (
 		Request(subject string, message any, timeout time.Duration, optionFuncs ...mq.RequestOption) (response *nats.Msg, err error)
 	}
 
+	IProgressPublisher interface {
+		IsActive() bool
+		PublishRequest(method, to string, body any, options ...wschat.RequestOptions) (response wschat.WebsocketMessage, err error)
+	}
+
 	Service struct {
-		appStateService  IAppStateService
-		mqService        IMQService
-		cliExtExecutable string
+		appStateService   IAppStateService
+		mqService         IMQService
+		progressPublisher IProgressPublisher
+		cliExtExecutable  string
+
+		jobsMx       sync.Mutex
+		jobs         []*entities.DownloadJob
+		lastProgress map[string]time.Time // last relayed progress by job and package
 	}
 )
 
-func NewService(appStateService IAppStateService, mqService IMQService, cliExtExecutable string) *Service {
+func NewService(appStateService IAppStateService, mqService IMQService, progressPublisher IProgressPublisher,
+	cliExtExecutable string) *Service {
 	return &Service{
-		appStateService:  appStateService,
-		mqService:        mqService,
-		cliExtExecutable: cliExtExecutable,
+		appStateService:   appStateService,
+		mqService:         mqService,
+		progressPublisher: progressPublisher,
+		cliExtExecutable:  cliExtExecutable,
+
+		lastProgress: make(map[string]time.Time),
 	}
 }
 
@@ -53,25 +68,6 @@ func (s *Service) SetAptSource(aptSource string) (err error) {
 	return nil
 }
 
-// Download makes download request to update manager.
-func (s *Service) Download(request entities.DownloadPackageRequest) (err error) {
-	resp, err := s.mqService.Request(constants.MQUpdateManagerDownload, request, downloadDevicePackagesTimeout)
-	if err != nil {
-		return fmt.Errorf("Download: %w", err)
-	}
-
-	var mqResp mq.Response
-	if err = json.Unmarshal(resp.Data, &mqResp); err != nil {
-		return fmt.Errorf("Download: %w", err)
-	}
-
-	if mqResp.IsError() {
-		return fmt.Errorf("Download: %w", mqResp.Error())
-	}
-
-	return nil
-}
-
 // Install changes state to maintenance and installs new packages.
 func (s *Service) Install(request entities.InstallPackageRequest) (err error) {
 	if err = s.appStateService.Perform(entities.NewOnUpdateDevice(request)); err != nil {
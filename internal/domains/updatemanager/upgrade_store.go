@@ -0,0 +1,81 @@
+package updatemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const pendingUpgradeKey = "maintenance:pending_upgrade"
+
+type (
+	IBadgerDB interface {
+		Update(fn func(txn *badger.Txn) error) error
+		View(fn func(txn *badger.Txn) error) error
+	}
+
+	// UpgradeStore keeps install request in badger until post-upgrade health gate is passed.
+	UpgradeStore struct {
+		db IBadgerDB
+	}
+)
+
+func NewUpgradeStore(db IBadgerDB) *UpgradeStore {
+	return &UpgradeStore{
+		db: db,
+	}
+}
+
+// GetPending returns pending upgrade, found is false if there is no pending upgrade.
+func (s *UpgradeStore) GetPending() (pending entities.PendingUpgrade, found bool, err error) {
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(pendingUpgradeKey))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(value []byte) error {
+			return json.Unmarshal(value, &pending)
+		})
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return pending, false, nil
+	}
+
+	if err != nil {
+		return pending, false, fmt.Errorf("GetPending: %w", err)
+	}
+
+	return pending, true, nil
+}
+
+// SavePending saves pending upgrade.
+func (s *UpgradeStore) SavePending(pending entities.PendingUpgrade) (err error) {
+	value, err := json.Marshal(pending)
+	if err != nil {
+		return fmt.Errorf("SavePending: %w", err)
+	}
+
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(pendingUpgradeKey), value)
+	}); err != nil {
+		return fmt.Errorf("SavePending: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePending deletes pending upgrade.
+func (s *UpgradeStore) DeletePending() (err error) {
+	if err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(pendingUpgradeKey))
+	}); err != nil {
+		return fmt.Errorf("DeletePending: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package updatemanager
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+// Verifier checks cached package archives against digests and signatures of install request.
+type Verifier struct {
+	archivesDir    string
+	trustedKeysDir string
+	requireDigest  bool
+}
+
+func NewVerifier(archivesDir, trustedKeysDir string, requireDigest bool) *Verifier {
+	return &Verifier{
+		archivesDir:    archivesDir,
+		trustedKeysDir: trustedKeysDir,
+		requireDigest:  requireDigest,
+	}
+}
+
+// VerifyPackages verifies archives of packages with digest or signature. Verification is enabled by
+// requireDigest or by any package with digest, then every package must have digest.
+func (v *Verifier) VerifyPackages(packages entities.PackageItems) (err error) {
+	for _, pkg := range packages {
+		if err = ValidatePackage(pkg.Name, pkg.Version); err != nil {
+			return fmt.Errorf("VerifyPackages: %w", err)
+		}
+	}
+
+	if !v.requireDigest && !lo.ContainsBy(packages, func(pkg entities.PackageItem) bool {
+		return !lo.IsEmpty(pkg.SHA256) || !lo.IsEmpty(pkg.Signature)
+	}) {
+		return nil
+	}
+
+	arch, err := DpkgArchitecture()
+	if err != nil {
+		return fmt.Errorf("VerifyPackages: %w", err)
+	}
+
+	var keys []ed25519.PublicKey
+	for _, pkg := range packages {
+		if lo.IsEmpty(pkg.SHA256) && lo.IsEmpty(pkg.Signature) {
+			return fmt.Errorf("VerifyPackages: %w: %s %s", errs.ErrPackageDigestMissing, pkg.Name, pkg.Version)
+		}
+
+		path, err := FindArchive(v.archivesDir, pkg.Name, pkg.Version, arch)
+		if err != nil {
+			return fmt.Errorf("VerifyPackages: %w", err)
+		}
+
+		digest, err := fileDigest(path)
+		if err != nil {
+			return fmt.Errorf("VerifyPackages: %w", err)
+		}
+
+		if !lo.IsEmpty(pkg.SHA256) && !strings.EqualFold(hex.EncodeToString(digest), pkg.SHA256) {
+			return fmt.Errorf("VerifyPackages: %w: %s: expected %s, got %x",
+				errs.ErrPackageDigestMismatch, filepath.Base(path), pkg.SHA256, digest)
+		}
+
+		if lo.IsEmpty(pkg.Signature) {
+			continue
+		}
+
+		if keys == nil {
+			if keys, err = LoadTrustedKeys(v.trustedKeysDir); err != nil {
+				return fmt.Errorf("VerifyPackages: %w", err)
+			}
+		}
+
+		if err = VerifySignature(keys, digest, pkg.Signature); err != nil {
+			return fmt.Errorf("VerifyPackages: %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	return nil
+}
+
+// VerifySignature checks base64 ed25519 signature of message against trusted keys.
+func VerifySignature(keys []ed25519.PublicKey, message []byte, signature string) (err error) {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("VerifySignature: %w: %w", errs.ErrPackageSignatureInvalid, err)
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key, message, sig) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("VerifySignature: %w", errs.ErrPackageSignatureInvalid)
+}
+
+// LoadTrustedKeys loads ed25519 public keys (PEM or base64 raw key) from *.pub files of directory.
+func LoadTrustedKeys(dir string) (keys []ed25519.PublicKey, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+constants.TrustedPublicKeyFileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("LoadTrustedKeys: %w", err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("LoadTrustedKeys: %w", err)
+		}
+
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("LoadTrustedKeys: %s: %w", filepath.Base(path), err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("LoadTrustedKeys: %w in %s", errs.ErrNoTrustedKeys, dir)
+	}
+
+	return keys, nil
+}
+
+func parsePublicKey(data []byte) (key ed25519.PublicKey, err error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsePublicKey: %w", err)
+		}
+
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("parsePublicKey: not ed25519 key")
+		}
+
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("parsePublicKey: %w", err)
+	}
+
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("parsePublicKey: invalid key size %d", len(raw))
+	}
+
+	return raw, nil
+}
+
+func fileDigest(path string) (digest []byte, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fileDigest: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("fileDigest: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,130 @@
+package updatemanager
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+func TestVerifier_VerifyPackages(t *testing.T) {
+	var (
+		archivesDir = t.TempDir()
+		keysDir     = t.TempDir()
+		content     = []byte("package archive")
+		digest      = sha256.Sum256(content)
+	)
+
+	arch, err := DpkgArchitecture()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(archivesDir, "sdwan-agent_1%3a1.2.0_"+arch+".deb"), content, 0o600))
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(keysDir, "release.pub"),
+		[]byte(base64.StdEncoding.EncodeToString(publicKey)), 0o600))
+
+	var (
+		verifier  = NewVerifier(archivesDir, keysDir, false)
+		signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, digest[:]))
+		pkg       = entities.PackageItem{
+			Name:      "sdwan-agent",
+			Version:   "1:1.2.0",
+			SHA256:    hex.EncodeToString(digest[:]),
+			Signature: signature,
+		}
+	)
+
+	tests := []struct {
+		name    string
+		modify  func(pkg *entities.PackageItem)
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			modify: func(*entities.PackageItem) {},
+		},
+		{
+			name: "without digest",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.SHA256, pkg.Signature = "", ""
+				pkg.Version = "2.0.0"
+			},
+		},
+		{
+			name: "digest mismatch",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+			},
+			wantErr: errs.ErrPackageDigestMismatch,
+		},
+		{
+			name: "invalid signature",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("other")))
+			},
+			wantErr: errs.ErrPackageSignatureInvalid,
+		},
+		{
+			name: "archive not found",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.Version = "1.3.0"
+			},
+			wantErr: errs.ErrPackageArchiveNotFound,
+		},
+		{
+			name: "path in name",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.Name = "../../tmp/sdwan-agent"
+			},
+			wantErr: errs.ErrInvalidPackage,
+		},
+		{
+			name: "path in version",
+			modify: func(pkg *entities.PackageItem) {
+				pkg.Version = "1.2.0/../../x"
+			},
+			wantErr: errs.ErrInvalidPackage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := pkg
+			tt.modify(&item)
+
+			err := verifier.VerifyPackages(entities.PackageItems{item})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifier_VerifyPackages_missingDigest(t *testing.T) {
+	var (
+		withDigest    = entities.PackageItem{Name: "sdwan-agent", Version: "1.2.0", SHA256: hex.EncodeToString(make([]byte, sha256.Size))}
+		withoutDigest = entities.PackageItem{Name: "sdwan-bgpd", Version: "1.0.0"}
+	)
+
+	// verification is enabled by setting
+	err := NewVerifier(t.TempDir(), t.TempDir(), true).VerifyPackages(entities.PackageItems{withoutDigest})
+	require.ErrorIs(t, err, errs.ErrPackageDigestMissing)
+
+	// verification is enabled by package with digest
+	err = NewVerifier(t.TempDir(), t.TempDir(), false).VerifyPackages(entities.PackageItems{withoutDigest, withDigest})
+	require.ErrorIs(t, err, errs.ErrPackageDigestMissing)
+}
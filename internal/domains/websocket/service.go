@@ -3,12 +3,21 @@ package websocket
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/observable"
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/wschat"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/errs"
+)
+
+const (
+	stallPeriods = 3 // ping periods without heartbeat before loop is considered stuck
 )
 
 type (
@@ -32,24 +41,41 @@ type (
 		DumpStats(dumpKey string)
 	}
 
+	IRequestTracker interface {
+		Acquire() bool
+		Release()
+	}
+
 	WsHandler func(request wschat.WebsocketMessage) error
 
 	Service struct {
 		messagePublisher IMessagePublisher
 		dumpStatService  IDumpStatService
+		requestTracker   IRequestTracker
 		pingPeriod       time.Duration
+		heartbeat        *entities.Heartbeat
 
 		routes map[string]WsHandler
+
+		statsMx     sync.Mutex
+		connects    uint64
+		disconnects uint64
+		handlers    map[string]*entities.HandlerStat
 	}
 )
 
-func NewService(messagePublisher IMessagePublisher, dumpStatService IDumpStatService, pingPeriod time.Duration) *Service {
+func NewService(messagePublisher IMessagePublisher, dumpStatService IDumpStatService, requestTracker IRequestTracker,
+	pingPeriod time.Duration,
+) *Service {
 	service := &Service{
 		messagePublisher: messagePublisher,
 		dumpStatService:  dumpStatService,
+		requestTracker:   requestTracker,
 		pingPeriod:       pingPeriod,
+		heartbeat:        entities.NewHeartbeat(),
 
-		routes: map[string]WsHandler{},
+		routes:   map[string]WsHandler{},
+		handlers: make(map[string]*entities.HandlerStat),
 	}
 
 	go service.run()
@@ -64,6 +90,30 @@ func (s *Service) IsStarted() bool {
 	return !s.messagePublisher.IsClosed()
 }
 
+// Stats returns websocket connection and handler statistics.
+func (s *Service) Stats() entities.WebsocketStats {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	stats := entities.WebsocketStats{
+		Connected:   s.messagePublisher.IsActive(),
+		Connects:    s.connects,
+		Disconnects: s.disconnects,
+		Handlers:    make([]entities.HandlerStat, 0, len(s.handlers)),
+	}
+	for _, stat := range s.handlers {
+		handler := *stat
+		handler.Duration = stat.Duration.Copy()
+		stats.Handlers = append(stats.Handlers, handler)
+	}
+
+	sort.Slice(stats.Handlers, func(i, j int) bool {
+		return stats.Handlers[i].Method < stats.Handlers[j].Method
+	})
+
+	return stats
+}
+
 // Start starts message publisher (websocket connection).
 func (s *Service) Start() (err error) {
 	if s.IsStarted() {
@@ -96,6 +146,8 @@ func (s *Service) run() {
 	}()
 
 	for {
+		s.heartbeat.Beat()
+
 		select {
 		case request := <-s.messagePublisher.ListenRequests():
 			log.Trace().
@@ -111,8 +163,22 @@ func (s *Service) run() {
 				break
 			}
 
+			// handlers are drained on shutdown, new requests are refused
+			if !s.requestTracker.Acquire() {
+				if err := s.messagePublisher.PublishErrorResponse(request, http.StatusServiceUnavailable, errs.ErrAgentShuttingDown.Error()); err != nil {
+					log.Error().Err(err).Msg("run")
+				}
+
+				break
+			}
+
 			go func() {
-				if err := handler(request); err != nil {
+				defer s.requestTracker.Release()
+
+				startedAt := time.Now()
+				err := handler(request)
+				s.recordHandler(request.Method, startedAt, err)
+				if err != nil {
 					log.Error().
 						Err(err).
 						Msg("run")
@@ -124,6 +190,8 @@ func (s *Service) run() {
 				Any("new state", newState).
 				Msg("run: connection state changed")
 
+			s.recordConnectionState(newState)
+
 			if newState == wschat.ConnectionStateClosed {
 				s.dumpStatService.DumpStats("websocket connection closed")
 			}
@@ -146,3 +214,43 @@ func (s *Service) run() {
 		}
 	}
 }
+
+// CheckProgress returns error if service loop is stuck, idle loop wakes up at least every ping period.
+func (s *Service) CheckProgress() (err error) {
+	if since := s.heartbeat.Since(); since > stallPeriods*s.pingPeriod {
+		return fmt.Errorf("CheckProgress: %w: no heartbeat for %s", errs.ErrLoopStalled, since.Round(time.Second))
+	}
+
+	return nil
+}
+
+func (s *Service) recordHandler(method string, startedAt time.Time, err error) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	stat, exists := s.handlers[method]
+	if !exists {
+		stat = &entities.HandlerStat{
+			Method:   method,
+			Duration: entities.NewHistogram(entities.DurationBuckets),
+		}
+		s.handlers[method] = stat
+	}
+
+	stat.Duration.Observe(time.Since(startedAt).Seconds())
+	if err != nil {
+		stat.Errors++
+	}
+}
+
+func (s *Service) recordConnectionState(state wschat.ConnectionState) {
+	s.statsMx.Lock()
+	defer s.statsMx.Unlock()
+
+	switch state {
+	case wschat.ConnectionStateActive:
+		s.connects++
+	case wschat.ConnectionStateClosed:
+		s.disconnects++
+	}
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+type (
+	// AgentStatus summary of the running agent for the local CLI.
+	AgentStatus struct {
+		AgentHealth
+		StateSince   time.Time `json:"stateSince"`
+		DeviceType   string    `json:"deviceType"`
+		SerialNumber string    `json:"serialNumber"`
+		Orchestrator string    `json:"orchestrator"`
+	}
+
+	// TransactionInfo not finished activity transaction.
+	TransactionInfo struct {
+		UUID       string `json:"uuid"`
+		Name       string `json:"name"`
+		Status     string `json:"status"`
+		Activities int    `json:"activities"`
+		Error      string `json:"error,omitempty"`
+	}
+
+	AdminError struct {
+		Error string `json:"error"`
+	}
+)
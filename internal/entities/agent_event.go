@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	EventSeverityInfo    = "info"
+	EventSeverityWarning = "warning"
+	EventSeverityError   = "error"
+)
+
+// AgentEvent event reported by agent to orchestrator (recovery actions, limits, etc.).
+type AgentEvent struct {
+	Source    string    `json:"source"`
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	Data      any       `json:"data,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
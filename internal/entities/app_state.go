@@ -124,6 +124,35 @@ func (e *OnUpdateConfig) ToState() AppState {
 	return AppStateUpdateConfig
 }
 
+type OnReapplySections struct {
+	Sections []string
+}
+
+func NewOnReapplySections(sections []string) *OnReapplySections {
+	return &OnReapplySections{
+		Sections: sections,
+	}
+}
+
+func (e *OnReapplySections) ToState() AppState {
+	return AppStateUpdateConfig
+}
+
+// OnPatchWgPeers patch is applied to wireguard config read in update config state, so concurrent updates are not lost.
+type OnPatchWgPeers struct {
+	Patch WgPeersPatch
+}
+
+func NewOnPatchWgPeers(patch WgPeersPatch) *OnPatchWgPeers {
+	return &OnPatchWgPeers{
+		Patch: patch,
+	}
+}
+
+func (e *OnPatchWgPeers) ToState() AppState {
+	return AppStateUpdateConfig
+}
+
 type OnRebuildServices struct{}
 
 func NewOnRebuildServices() *OnRebuildServices {
@@ -0,0 +1,33 @@
+package entities
+
+type (
+	PacketCaptureRequest struct {
+		Port            string `json:"port" validate:"required_without=Tunnel"`
+		Tunnel          string `json:"tunnel" validate:"required_without=Port,omitempty,ipv4"`
+		Filter          string `json:"filter" validate:"max=1024,startsnotwith=-"`
+		DurationSeconds int    `json:"durationSeconds" validate:"min=0"`
+		MaxPackets      int    `json:"maxPackets" validate:"min=0"`
+		MaxBytes        int64  `json:"maxBytes" validate:"min=0"`
+		SnapLen         int    `json:"snapLen" validate:"min=0,max=65535"`
+	}
+
+	StopPacketCaptureRequest struct {
+		CaptureID string `json:"captureId"`
+	}
+
+	PacketCaptureStarted struct {
+		CaptureID       string `json:"captureId"`
+		Interface       string `json:"interface"`
+		DurationSeconds int    `json:"durationSeconds"`
+		MaxPackets      int    `json:"maxPackets"`
+		MaxBytes        int64  `json:"maxBytes"`
+	}
+
+	PacketCaptureFinished struct {
+		CaptureID  string `json:"captureId"`
+		Interface  string `json:"interface"`
+		StopReason string `json:"stopReason"`
+		Size       int    `json:"size"`
+		Error      string `json:"error,omitempty"`
+	}
+)
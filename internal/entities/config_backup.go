@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+)
+
+const (
+	ConfigBackupFormatVersion = 1
+
+	ConfigBackupCipherAES256GCM = "aes-256-gcm"
+	ConfigBackupKDFPBKDF2SHA256 = "pbkdf2-sha256"
+)
+
+type (
+	// ConfigBackup versioned configuration snapshot of device.
+	// Config is set for plain backup, Encryption and Payload for encrypted one.
+	ConfigBackup struct {
+		FormatVersion int                     `json:"formatVersion"`
+		Metadata      ConfigBackupMetadata    `json:"metadata"`
+		Config        *config.Config          `json:"config,omitempty"`
+		Encryption    *ConfigBackupEncryption `json:"encryption,omitempty"`
+		Payload       []byte                  `json:"payload,omitempty"` // encrypted json of config
+		Checksum      string                  `json:"checksum"`          // hex sha256 of metadata and config json
+	}
+
+	ConfigBackupMetadata struct {
+		CreatedAt     time.Time `json:"createdAt"`
+		SerialNumber  string    `json:"serialNumber"`
+		DeviceType    string    `json:"deviceType"`
+		AppState      string    `json:"appState"`
+		ConfigVersion int       `json:"configVersion"`
+		Redacted      bool      `json:"redacted"` // secrets are masked, backup cannot be imported
+	}
+
+	ConfigBackupEncryption struct {
+		Cipher     string `json:"cipher"`
+		KDF        string `json:"kdf"`
+		Iterations int    `json:"iterations"`
+		Salt       []byte `json:"salt"`
+		Nonce      []byte `json:"nonce"`
+	}
+)
+
+type (
+	ExportConfigRequest struct {
+		// Passphrase encrypts backup if set, encrypted backup always includes secrets
+		Passphrase string `json:"passphrase" validate:"omitempty,min=8" redact:"true"`
+		// IncludeSecrets includes secrets to plain backup, otherwise they are masked
+		IncludeSecrets bool `json:"includeSecrets"`
+	}
+
+	ImportConfigRequest struct {
+		Backup     ConfigBackup `json:"backup"`
+		Passphrase string       `json:"passphrase" redact:"true"`
+		// SerialNumber replaces serial number of backup on RMA swap, otherwise backup serial number must match the device
+		SerialNumber string `json:"serialNumber" validate:"omitempty,max=64,printascii,excludesall= .*>"`
+	}
+)
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ConfigViolation reference between config sections which cannot be applied.
+	ConfigViolation struct {
+		Field   string `json:"field"` // path in config, example: pony.clusters[0].uplinks[1].tableId
+		Message string `json:"message"`
+	}
+
+	ConfigViolations []ConfigViolation
+)
+
+func (v ConfigViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+func (v ConfigViolations) Error() string {
+	items := make([]string, 0, len(v))
+	for _, violation := range v {
+		items = append(items, violation.String())
+	}
+
+	return strings.Join(items, "; ")
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+type (
+	// DataUsagePolicy billing cycle and caps of metered port, caps are in bytes sent and received, 0 means no cap.
+	DataUsagePolicy struct {
+		PortName     string `json:"portName" validate:"required"`
+		BillingDay   int    `json:"billingDay" validate:"min=1,max=28"`
+		SoftCapBytes uint64 `json:"softCapBytes"`
+		HardCapBytes uint64 `json:"hardCapBytes" validate:"omitempty,gtefield=SoftCapBytes"`
+	}
+
+	DataUsageSetPoliciesRequest struct {
+		Policies []DataUsagePolicy `json:"policies" validate:"unique=PortName,dive"`
+	}
+
+	// PortDataUsage bytes sent and received by port in the current billing cycle.
+	PortDataUsage struct {
+		PortName       string           `json:"portName"`
+		CycleStart     time.Time        `json:"cycleStart"`
+		RxBytes        uint64           `json:"rxBytes"`
+		TxBytes        uint64           `json:"txBytes"`
+		SoftCapReached bool             `json:"softCapReached"`
+		HardCapReached bool             `json:"hardCapReached"`
+		DisabledByCap  bool             `json:"disabledByCap"` // port is admin down because of hard cap
+		Policy         *DataUsagePolicy `json:"policy,omitempty"`
+		UpdatedAt      time.Time        `json:"updatedAt"`
+	}
+)
+
+// TotalBytes returns bytes sent and received.
+func (u PortDataUsage) TotalBytes() uint64 {
+	return u.RxBytes + u.TxBytes
+}
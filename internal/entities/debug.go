@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	ProfileKindCPU       = "cpu"
+	ProfileKindHeap      = "heap"
+	ProfileKindAllocs    = "allocs"
+	ProfileKindGoroutine = "goroutine"
+	ProfileKindBlock     = "block"
+	ProfileKindMutex     = "mutex"
+)
+
+const (
+	ProfileStateCollecting = "collecting"
+	ProfileStateReady      = "ready"
+	ProfileStateFailed     = "failed"
+)
+
+type (
+	// ProfileRequest requests pprof profile. DurationSeconds bounds cpu profile and
+	// sampling period of block/mutex profiles. Debug > 0 returns goroutine dump in text format.
+	ProfileRequest struct {
+		Kind            string `json:"kind" validate:"required,oneof=cpu heap allocs goroutine block mutex"`
+		DurationSeconds int    `json:"durationSeconds" validate:"min=0,max=120"`
+		Debug           int    `json:"debug" validate:"min=0,max=2"`
+	}
+
+	// ProfileInfo describes collected profile, profile data is fetched by chunks when profile is ready.
+	// Block and mutex profiles are cumulative since the agent start, BaseProfileID refers to the profile
+	// taken before sampling period (diff with `go tool pprof -base`).
+	ProfileInfo struct {
+		ProfileID     string    `json:"profileId"`
+		Kind          string    `json:"kind"`
+		State         string    `json:"state"`
+		Error         string    `json:"error,omitempty"`
+		BaseProfileID string    `json:"baseProfileId,omitempty"`
+		Size          int       `json:"size"`
+		SHA256        string    `json:"sha256"`
+		Chunks        int       `json:"chunks"`
+		ExpiresAt     time.Time `json:"expiresAt"`
+	}
+
+	ProfileStatusRequest struct {
+		ProfileID string `json:"profileId" validate:"required"`
+	}
+
+	ProfileChunkRequest struct {
+		ProfileID string `json:"profileId" validate:"required"`
+		Index     int    `json:"index" validate:"min=0"`
+	}
+
+	RuntimeStats struct {
+		Goroutines  int         `json:"goroutines"`
+		CPUs        int         `json:"cpus"`
+		Memory      MemoryStats `json:"memory"`
+		GC          GCStats     `json:"gc"`
+		Badger      BadgerStats `json:"badger"`
+		CollectedAt time.Time   `json:"collectedAt"`
+	}
+
+	MemoryStats struct {
+		Alloc        uint64 `json:"alloc"`
+		TotalAlloc   uint64 `json:"totalAlloc"`
+		Sys          uint64 `json:"sys"`
+		HeapAlloc    uint64 `json:"heapAlloc"`
+		HeapInuse    uint64 `json:"heapInuse"`
+		HeapIdle     uint64 `json:"heapIdle"`
+		HeapReleased uint64 `json:"heapReleased"`
+		HeapObjects  uint64 `json:"heapObjects"`
+		StackInuse   uint64 `json:"stackInuse"`
+		Mallocs      uint64 `json:"mallocs"`
+		Frees        uint64 `json:"frees"`
+	}
+
+	GCStats struct {
+		NumGC        uint32    `json:"numGc"`
+		PauseTotalNs uint64    `json:"pauseTotalNs"`
+		LastPauseNs  uint64    `json:"lastPauseNs"`
+		LastGC       time.Time `json:"lastGc"`
+		NextGC       uint64    `json:"nextGc"`
+		CPUFraction  float64   `json:"cpuFraction"`
+	}
+
+	BadgerStats struct {
+		LSMSize  int64              `json:"lsmSize"`
+		VlogSize int64              `json:"vlogSize"`
+		Tables   int                `json:"tables"`
+		Levels   []BadgerLevelStats `json:"levels"`
+	}
+
+	BadgerLevelStats struct {
+		Level      int     `json:"level"`
+		Tables     int     `json:"tables"`
+		Size       int64   `json:"size"`
+		TargetSize int64   `json:"targetSize"`
+		Score      float64 `json:"score"`
+	}
+)
@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+const (
+	DownloadStateQueued      = "queued"
+	DownloadStateDownloading = "downloading"
+	DownloadStateCanceled    = "canceled"
+	DownloadStateCompleted   = "completed"
+	DownloadStateFailed      = "failed"
+)
+
+type (
+	// DownloadJob packages download started by orchestrator, progress is reported by update manager.
+	DownloadJob struct {
+		ID        string             `json:"jobId"`
+		State     string             `json:"state"`
+		Packages  PackageItems       `json:"packages"`
+		Progress  []DownloadProgress `json:"progress"`
+		Error     string             `json:"error,omitempty"`
+		CreatedAt time.Time          `json:"createdAt"`
+		UpdatedAt time.Time          `json:"updatedAt"`
+	}
+
+	// DownloadProgress download progress of one package.
+	DownloadProgress struct {
+		JobID      string  `json:"jobId" validate:"required"`
+		Package    string  `json:"package" validate:"required"`
+		Version    string  `json:"version"`
+		State      string  `json:"state" validate:"required,oneof=queued downloading canceled completed failed"`
+		Bytes      int64   `json:"bytes"`
+		TotalBytes int64   `json:"totalBytes"`
+		Rate       float64 `json:"rate"` // bytes per second
+		ETASeconds int64   `json:"etaSeconds"`
+		Error      string  `json:"error,omitempty"`
+	}
+
+	DownloadJobRequest struct {
+		JobID string `json:"jobId" validate:"required"`
+	}
+)
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+const (
+	DriftSectionPort      = "port"
+	DriftSectionIPRule    = "ipRule"
+	DriftSectionLoopback  = "loopback"
+	DriftSectionRouting   = "routing"
+	DriftSectionWireguard = "wireguard"
+	DriftSectionOVS       = "ovs"
+
+	DriftKindMissing    = "missing"
+	DriftKindUnexpected = "unexpected"
+)
+
+type (
+	// DriftItem difference between intended config and live system state.
+	DriftItem struct {
+		Section string `json:"section"`
+		Kind    string `json:"kind"`
+		Object  string `json:"object"` // interface, port or peer the item belongs to
+		Value   string `json:"value"`
+	}
+
+	DriftReport struct {
+		CheckedAt  time.Time         `json:"checkedAt"`
+		Items      []DriftItem       `json:"items"`
+		Remediated []string          `json:"remediated,omitempty"` // sections re-applied after the check
+		Errors     map[string]string `json:"errors,omitempty"`     // sections which live state cannot be read
+	}
+
+	// DriftSettings sections listed in AutoRemediate are re-applied when drift is found.
+	// Port addresses and routing tables are reported only.
+	DriftSettings struct {
+		AutoRemediate []string `json:"autoRemediate" validate:"unique,dive,oneof=ipRule loopback wireguard ovs"`
+	}
+
+	SetDriftSettingsRequest struct {
+		Settings DriftSettings `json:"settings"`
+	}
+)
@@ -1,8 +1,36 @@
 package entities
 
+import (
+	"time"
+)
+
 type HealthcheckStatus struct {
 	CompletedSteps []string `json:"completedSteps"`
 	ExecFinished   bool     `json:"execFinished"`
 	Error          string   `json:"error"`
 	ErrorStep      string   `json:"errorStep"`
 }
+
+type (
+	// AgentHealth local health report of the agent.
+	AgentHealth struct {
+		Healthy              bool              `json:"healthy"`
+		Problems             []string          `json:"problems"`
+		AppState             AppState          `json:"appState"`
+		WebsocketConnected   bool              `json:"websocketConnected"`
+		NATSConnected        bool              `json:"natsConnected"`
+		BadgerWritable       bool              `json:"badgerWritable"`
+		DanglingTransactions int               `json:"danglingTransactions"`
+		Tunnels              []TunnelHealth    `json:"tunnels"`
+		LastApply            HealthcheckStatus `json:"lastApply"`
+		CheckedAt            time.Time         `json:"checkedAt"`
+	}
+
+	TunnelHealth struct {
+		Network  string `json:"network"`
+		Tunnel   string `json:"tunnel"`
+		Up       bool   `json:"up"`
+		RemoteUp bool   `json:"remoteUp"`
+		Active   bool   `json:"active"`
+	}
+)
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat records time of the last progress of a background loop, safe for concurrent use.
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+func NewHeartbeat() *Heartbeat {
+	heartbeat := &Heartbeat{}
+	heartbeat.Beat()
+	return heartbeat
+}
+
+// Beat records loop progress.
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Since returns time elapsed from the last beat.
+func (h *Heartbeat) Since() time.Duration {
+	return time.Since(time.Unix(0, h.last.Load()))
+}
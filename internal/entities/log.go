@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+)
+
+type (
+	SetLogLevelRequest struct {
+		Level      string `json:"level" validate:"required,oneof=trace debug info warn error fatal panic disabled"`
+		Component  string `json:"component"`
+		TTLSeconds int    `json:"ttlSeconds" validate:"min=0"`
+	}
+
+	ResetLogLevelRequest struct {
+		Component string `json:"component"`
+	}
+
+	LogLevel struct {
+		Component string     `json:"component,omitempty"`
+		Level     string     `json:"level"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	}
+
+	LogLevels struct {
+		Default    string     `json:"default"`
+		Global     LogLevel   `json:"global"`
+		Components []LogLevel `json:"components"`
+	}
+)
+
+type (
+	TailLogRequest struct {
+		Lines    int    `json:"lines" validate:"min=0,max=5000"`
+		Level    string `json:"level" validate:"omitempty,oneof=trace debug info warn error fatal panic"`
+		Contains string `json:"contains"`
+		Cursor   int64  `json:"cursor" validate:"min=0"`
+		FileID   uint64 `json:"fileId"` // log file of the cursor, changes on rotation
+		// FollowSeconds streams new lines as tail_log_chunk requests (websocket only)
+		FollowSeconds int `json:"followSeconds" validate:"min=0,max=3600"`
+	}
+
+	LogTail struct {
+		Lines   []string `json:"lines"`
+		Cursor  int64    `json:"cursor"`
+		FileID  uint64   `json:"fileId"`
+		Rotated bool     `json:"rotated"` // log was rotated after the request cursor, lines start from the new file
+	}
+
+	LogTailChunk struct {
+		StreamID string `json:"streamId"`
+		LogTail
+	}
+)
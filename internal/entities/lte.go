@@ -1,6 +1,11 @@
 package entities
 
+import (
+	"time"
+)
+
 type LTEStat struct {
+	ModemID       string            `json:"modemId"`
 	IMEI          string            `json:"imei"`
 	OperatorCode  string            `json:"operatorCode"`
 	OperatorName  string            `json:"operatorName"`
@@ -9,7 +14,9 @@ type LTEStat struct {
 	State         string            `json:"state"`
 	DevicePath    string            `json:"devicePath"`
 	Port          string            `json:"port"`
+	PrimaryPort   string            `json:"primaryPort"`
 	SignalQuality SignalQualityStat `json:"signalQuality"`
+	Radio         LTERadioStat      `json:"radio"`
 	// general
 	Type         string `json:"type"`
 	HWAddr       string `json:"hwAddr"`
@@ -27,4 +34,40 @@ type SignalQualityStat struct {
 	Value  string `json:"value"`
 }
 
+// LTERadioStat extended signal and serving cell info, signal values are nil when not reported by modem.
+type LTERadioStat struct {
+	RSRP              *float64 `json:"rsrp,omitempty"` // dBm
+	RSRQ              *float64 `json:"rsrq,omitempty"` // dB
+	SINR              *float64 `json:"sinr,omitempty"` // dB
+	RSSI              *float64 `json:"rssi,omitempty"` // dBm
+	CellID            string   `json:"cellId"`
+	TAC               string   `json:"tac"`
+	Band              string   `json:"band,omitempty"` // serving band, empty when unknown
+	Bands             []string `json:"bands"`          // bands allowed in modem configuration
+	AccessTechnology  string   `json:"accessTechnology"`
+	RegistrationState string   `json:"registrationState"`
+	Roaming           bool     `json:"roaming"`
+}
+
 type LTEStats []LTEStat
+
+// LTESample periodic modem sample saved to LTE history.
+type LTESample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	IMEI          string    `json:"imei"`
+	State         string    `json:"state"`
+	PowerState    string    `json:"powerState"`
+	OperatorName  string    `json:"operatorName"`
+	SignalQuality int       `json:"signalQuality"`
+	RSRP          *float64  `json:"rsrp,omitempty"`
+	RSRQ          *float64  `json:"rsrq,omitempty"`
+	SINR          *float64  `json:"sinr,omitempty"`
+	CellID        string    `json:"cellId,omitempty"`
+	Port          string    `json:"port"`
+	HasIP         bool      `json:"hasIp"`
+}
+
+type LTEHistoryRequest struct {
+	Since time.Time `json:"since"`
+	Limit int       `json:"limit" validate:"min=0,max=10000"`
+}
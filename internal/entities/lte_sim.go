@@ -0,0 +1,77 @@
+package entities
+
+const (
+	APNIPTypeIPv4   = "ipv4"
+	APNIPTypeIPv6   = "ipv6"
+	APNIPTypeIPv4v6 = "ipv4v6"
+)
+
+type (
+	// LTESettings agent side LTE settings managed by orchestrator.
+	LTESettings struct {
+		APNProfiles   []APNProfile      `json:"apnProfiles"`
+		ActiveProfile string            `json:"activeProfile"`
+		Failover      LTESIMFailover    `json:"failover"`
+		SavedPINs     map[string]string `json:"savedPins,omitempty" redact:"true"` // by sim ICCID, used to unlock sim after modem reset
+	}
+
+	APNProfile struct {
+		Name     string `json:"name" validate:"required,max=64"`
+		APN      string `json:"apn" validate:"required,max=64"`
+		User     string `json:"user" validate:"max=64"`
+		Password string `json:"password" validate:"max=64" redact:"true"`
+		IPType   string `json:"ipType" validate:"omitempty,oneof=ipv4 ipv6 ipv4v6"`
+	}
+
+	// LTESIMFailover switches sim slot when modem is not registered in network for RegistrationTimeoutMinutes.
+	LTESIMFailover struct {
+		Enabled                    bool  `json:"enabled"`
+		RegistrationTimeoutMinutes int   `json:"registrationTimeoutMinutes" validate:"required_if=Enabled true,omitempty,min=1,max=1440"`
+		Slots                      []int `json:"slots" validate:"required_if=Enabled true,omitempty,min=2,unique,dive,min=1"`
+	}
+)
+
+type (
+	LTEUnlockSIMRequest struct {
+		IMEI    string `json:"imei" validate:"required"`
+		PIN     string `json:"pin" validate:"required,numeric,min=4,max=8" redact:"true"`
+		PUK     string `json:"puk" validate:"omitempty,numeric,len=8" redact:"true"`
+		SavePIN bool   `json:"savePin"`
+	}
+
+	LTESelectSIMSlotRequest struct {
+		IMEI string `json:"imei" validate:"required"`
+		Slot int    `json:"slot" validate:"min=1"`
+	}
+
+	LTESetAPNProfilesRequest struct {
+		Profiles      []APNProfile `json:"profiles" validate:"unique=Name,dive"`
+		ActiveProfile string       `json:"activeProfile"`
+		// Connection network manager connection to apply active profile, default is the single gsm connection
+		Connection string `json:"connection"`
+	}
+
+	LTESetSIMFailoverRequest struct {
+		Failover LTESIMFailover `json:"failover"`
+	}
+
+	LTESIMStatus struct {
+		IMEI           string   `json:"imei"`
+		ModemID        string   `json:"modemId"`
+		SIMPath        string   `json:"simPath"`
+		PrimarySlot    int      `json:"primarySlot"`
+		Slots          int      `json:"slots"`
+		ICCID          string   `json:"iccid"`
+		IMSI           string   `json:"imsi"`
+		UnlockRequired string   `json:"unlockRequired"`
+		UnlockRetries  []string `json:"unlockRetries"`
+	}
+
+	// LTESIMSettings LTE settings without secrets.
+	LTESIMSettings struct {
+		APNProfiles   []APNProfile   `json:"apnProfiles"`
+		ActiveProfile string         `json:"activeProfile"`
+		Failover      LTESIMFailover `json:"failover"`
+		Statuses      []LTESIMStatus `json:"statuses"`
+	}
+)
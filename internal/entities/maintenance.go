@@ -1,40 +1,35 @@
 package entities
 
-const InstallUpdateManagerScript = `
-#!/bin/bash
-
-new_version=$1
-current_version=$2
-
-new_deb_path="/var/cache/apt/archives/sdwan-update-manager_${new_version}_amd64.deb"
-current_deb_path="/var/cache/apt/archives/sdwan-update-manager_${current_version}_amd64.deb"
-
-if [ ! -f "$new_deb_path" ]; then
-	echo "File not found: $new_deb_path" >&2
-	exit 1
-fi
-
-# install new version
-dpkg -i --force-confold "$new_deb_path"
-
-sleep 5
-
-# check daemon state
-active_state=$(systemctl show --no-pager sdwan-update-manager | grep "ActiveState" | awk -F= '{print $2}')
-
-if [ "$active_state" != "active" ]; then
-	# install old version
-    dpkg -i --force-confold "$current_deb_path"
-fi
-`
+import "time"
 
 type (
 	InstallPackageRequest struct {
-		PackagesToInstall PackageItems `json:"packages"`
+		PackagesToInstall PackageItems       `json:"packages" validate:"dive"`
+		HealthGate        *UpgradeHealthGate `json:"healthGate,omitempty"`
+	}
+
+	// UpgradeHealthGate checks performed after install, packages are rolled back to previous versions if
+	// device is not healthy within timeout.
+	UpgradeHealthGate struct {
+		Disabled       bool     `json:"disabled"`
+		TimeoutSeconds int      `json:"timeoutSeconds" validate:"omitempty,min=10,max=3600"`
+		SkipWebsocket  bool     `json:"skipWebsocket"`
+		SkipTunnels    bool     `json:"skipTunnels"`
+		Units          []string `json:"units" validate:"dive,required,max=255,startsnotwith=-"` // systemd units to be active, default units of installed daemons
+	}
+
+	// PendingUpgrade install request saved until health gate is passed, survives agent restart on self upgrade.
+	PendingUpgrade struct {
+		Request     InstallPackageRequest `json:"request"`
+		RollingBack bool                  `json:"rollingBack"`
+		GateError   string                `json:"gateError"`
+		StartedAt   time.Time             `json:"startedAt"`
+		Boots       int                   `json:"boots"` // agent boots during health gate
 	}
 
 	DownloadPackageRequest struct {
-		PackagesToDownload PackageItems `json:"packages"`
+		JobID              string       `json:"jobId,omitempty"`
+		PackagesToDownload PackageItems `json:"packages" validate:"dive"`
 	}
 
 	PackageItem struct {
@@ -42,6 +37,12 @@ type (
 		Version         string `json:"version" validate:"required"`
 		PreviousVersion string `json:"previousVersion"`
 		IsNew           bool   `json:"isNew"`
+		// SHA256 expected hex digest of cached archive, checked before install if set
+		SHA256 string `json:"sha256,omitempty" validate:"omitempty,len=64,hexadecimal"`
+		// Signature base64 ed25519 signature of archive SHA-256 digest made by one of trusted keys
+		Signature string `json:"signature,omitempty" validate:"omitempty,base64"`
+		// PreviousSHA256 digest of previous version archive, checked before rollback
+		PreviousSHA256 string `json:"previousSha256,omitempty" validate:"omitempty,len=64,hexadecimal"`
 	}
 
 	PackageItems []PackageItem
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"time"
+)
+
+// DurationBuckets default histogram buckets (seconds) for handler and transition durations.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Histogram is a simple cumulative histogram, not safe for concurrent use.
+type Histogram struct {
+	Buckets []float64 // upper bounds
+	Counts  []uint64  // non-cumulative counts per bucket
+	Count   uint64
+	Sum     float64
+}
+
+func NewHistogram(buckets []float64) Histogram {
+	return Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Observe adds value to histogram.
+func (h *Histogram) Observe(value float64) {
+	h.Count++
+	h.Sum += value
+
+	for i, bound := range h.Buckets {
+		if value <= bound {
+			h.Counts[i]++
+			return
+		}
+	}
+}
+
+// Copy returns deep copy of histogram.
+func (h Histogram) Copy() Histogram {
+	result := h
+	result.Counts = append([]uint64(nil), h.Counts...)
+
+	return result
+}
+
+type (
+	AppStateStats struct {
+		State       AppState
+		StateSince  time.Time
+		Transitions []TransitionStat
+	}
+
+	TransitionStat struct {
+		From     AppState
+		To       AppState
+		Failures uint64
+		Duration Histogram
+	}
+
+	// TransitionRecord single performed state transition.
+	TransitionRecord struct {
+		From      AppState      `json:"from"`
+		To        AppState      `json:"to"`
+		StartedAt time.Time     `json:"startedAt"`
+		Duration  time.Duration `json:"duration"`
+		Error     string        `json:"error,omitempty"`
+	}
+)
+
+type (
+	WebsocketStats struct {
+		Connected   bool
+		Connects    uint64
+		Disconnects uint64
+		Handlers    []HandlerStat
+	}
+
+	HandlerStat struct {
+		Method   string
+		Errors   uint64
+		Duration Histogram
+	}
+)
+
+type (
+	DiscoveryStats struct {
+		CheckedAt time.Time
+		Primary   string
+		Checks    map[string]uint64 // map[result]count
+		Hosts     []DiscoveryHostStat
+	}
+
+	DiscoveryHostStat struct {
+		Host      string
+		IsPrimary bool
+		Failed    bool
+	}
+)
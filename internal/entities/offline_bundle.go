@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+)
+
+const (
+	OfflineBundleFormatVersion = 1
+
+	OfflineBundleKindZTP  = "ztp"
+	OfflineBundleKindInit = "init"
+
+	OfflineBundleStatusApplied  = "applied"
+	OfflineBundleStatusRejected = "rejected"
+	OfflineBundleStatusFailed   = "failed"
+)
+
+type (
+	// SignedOfflineBundle file dropped to incoming directory, signature is base64 ed25519 signature
+	// of sha256 digest of bundle json bytes as written in file, made with one of trusted keys.
+	SignedOfflineBundle struct {
+		Bundle    json.RawMessage `json:"bundle"`
+		Signature string          `json:"signature"`
+	}
+
+	// OfflineBundle configuration applied without orchestrator: ZTP setup or device init.
+	OfflineBundle struct {
+		FormatVersion int       `json:"formatVersion" validate:"eq=1"`
+		Kind          string    `json:"kind" validate:"oneof=ztp init"`
+		CreatedAt     time.Time `json:"createdAt" validate:"required"` // bundle older or not newer than applied one is rejected
+		// SerialNumber device the bundle is made for, required for init bundle
+		SerialNumber string            `json:"serialNumber" validate:"required_if=Kind init"`
+		ZTP          *OfflineZTPConfig `json:"ztp" validate:"required_if=Kind ztp,omitempty"`
+		Init         *InitConfig       `json:"init" validate:"required_if=Kind init,omitempty"`
+	}
+
+	OfflineZTPConfig struct {
+		PortConfigs       []config.PortConfig `json:"portConfigs" validate:"dive"`
+		SerialNumber      string              `json:"serialNumber" validate:"required"`
+		OrchestratorAddrs []string            `json:"orchestratorAddrs" validate:"required"`
+	}
+
+	// OfflineBundleResult written next to the bundle after processing.
+	OfflineBundleResult struct {
+		File        string    `json:"file"`
+		Kind        string    `json:"kind,omitempty"`
+		Status      string    `json:"status"`
+		Error       string    `json:"error,omitempty"`
+		ProcessedAt time.Time `json:"processedAt"`
+	}
+)
@@ -0,0 +1,62 @@
+package entities
+
+import "time"
+
+type (
+	// PortCounters interface counters from /sys/class/net/<port>/statistics.
+	PortCounters struct {
+		RxBytes   uint64 `json:"rxBytes"`
+		TxBytes   uint64 `json:"txBytes"`
+		RxPackets uint64 `json:"rxPackets"`
+		TxPackets uint64 `json:"txPackets"`
+		RxErrors  uint64 `json:"rxErrors"`
+		TxErrors  uint64 `json:"txErrors"`
+		RxDropped uint64 `json:"rxDropped"`
+		TxDropped uint64 `json:"txDropped"`
+	}
+
+	// PortRates per second rates of port counters over sliding window.
+	PortRates struct {
+		RxBytes   float64 `json:"rxBytesPerSec"`
+		TxBytes   float64 `json:"txBytesPerSec"`
+		RxPackets float64 `json:"rxPacketsPerSec"`
+		TxPackets float64 `json:"txPacketsPerSec"`
+		RxErrors  float64 `json:"rxErrorsPerSec"`
+		TxErrors  float64 `json:"txErrorsPerSec"`
+		RxDropped float64 `json:"rxDroppedPerSec"`
+		TxDropped float64 `json:"txDroppedPerSec"`
+	}
+
+	PortStats struct {
+		PortName  string       `json:"portName"`
+		Counters  PortCounters `json:"counters"`
+		Rates     PortRates    `json:"rates"`
+		SampledAt time.Time    `json:"sampledAt"`
+	}
+)
+
+// Less reports whether any counter is lower than in other, counters are reset when interface is recreated.
+func (c PortCounters) Less(other PortCounters) bool {
+	return c.RxBytes < other.RxBytes || c.TxBytes < other.TxBytes ||
+		c.RxPackets < other.RxPackets || c.TxPackets < other.TxPackets ||
+		c.RxErrors < other.RxErrors || c.TxErrors < other.TxErrors ||
+		c.RxDropped < other.RxDropped || c.TxDropped < other.TxDropped
+}
+
+// RatesSince returns per second rates from previous counters, elapsed must be positive.
+func (c PortCounters) RatesSince(prev PortCounters, elapsed time.Duration) PortRates {
+	rate := func(current, previous uint64) float64 {
+		return float64(current-previous) / elapsed.Seconds()
+	}
+
+	return PortRates{
+		RxBytes:   rate(c.RxBytes, prev.RxBytes),
+		TxBytes:   rate(c.TxBytes, prev.TxBytes),
+		RxPackets: rate(c.RxPackets, prev.RxPackets),
+		TxPackets: rate(c.TxPackets, prev.TxPackets),
+		RxErrors:  rate(c.RxErrors, prev.RxErrors),
+		TxErrors:  rate(c.TxErrors, prev.TxErrors),
+		RxDropped: rate(c.RxDropped, prev.RxDropped),
+		TxDropped: rate(c.TxDropped, prev.TxDropped),
+	}
+}
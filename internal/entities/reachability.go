@@ -0,0 +1,101 @@
+package entities
+
+type (
+	// ProbePath describes how probes leave the device. When Tunnels are set,
+	// probes are sent separately through every listed pony tunnel (by monitor address).
+	// SourceInterface and VRF are passed to probe commands as arguments, so they are
+	// limited to valid link names (IFNAMSIZ) and must not look like options.
+	ProbePath struct {
+		SourceInterface string   `json:"sourceInterface" validate:"omitempty,max=15,startsnotwith=-,excludesall= /;&$0x7C"`
+		TableID         int      `json:"tableId" validate:"min=0"`
+		VRF             string   `json:"vrf" validate:"omitempty,max=15,startsnotwith=-,excludesall= /;&$0x7C"`
+		Tunnels         []string `json:"tunnels" validate:"dive,ipv4"`
+	}
+
+	PingRequest struct {
+		ProbePath
+
+		Destination string `json:"destination" validate:"required,ip4_addr|hostname_rfc1123"`
+		Count       int    `json:"count" validate:"min=0,max=100"`
+		IntervalMs  int    `json:"intervalMs" validate:"omitempty,min=200,max=10000"`
+		TimeoutMs   int    `json:"timeoutMs" validate:"omitempty,min=100,max=10000"`
+		PacketSize  int    `json:"packetSize" validate:"min=0,max=65507"`
+	}
+
+	TracerouteRequest struct {
+		ProbePath
+
+		Destination string `json:"destination" validate:"required,ip4_addr|hostname_rfc1123"`
+		MaxHops     int    `json:"maxHops" validate:"min=0,max=64"`
+		Probes      int    `json:"probes" validate:"min=0,max=10"`
+		TimeoutMs   int    `json:"timeoutMs" validate:"omitempty,min=100,max=10000"`
+	}
+
+	PathMTURequest struct {
+		ProbePath
+
+		Destination string `json:"destination" validate:"required,ip4_addr|hostname_rfc1123"`
+		MinMTU      int    `json:"minMtu" validate:"omitempty,min=68,max=9000"`
+		MaxMTU      int    `json:"maxMtu" validate:"omitempty,min=68,max=9000,gtefield=MinMTU"`
+		TimeoutMs   int    `json:"timeoutMs" validate:"omitempty,min=100,max=10000"`
+	}
+)
+
+type (
+	// ProbePathInfo resolved path used for probes.
+	ProbePathInfo struct {
+		Tunnel    string `json:"tunnel,omitempty"`
+		TableID   int    `json:"tableId,omitempty"`
+		VRF       string `json:"vrf,omitempty"`
+		Interface string `json:"interface,omitempty"`
+		Source    string `json:"source,omitempty"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	PingResult struct {
+		Path        ProbePathInfo `json:"path"`
+		Transmitted int           `json:"transmitted"`
+		Received    int           `json:"received"`
+		LossPercent float64       `json:"lossPercent"`
+		MinRTTMs    float64       `json:"minRttMs"`
+		AvgRTTMs    float64       `json:"avgRttMs"`
+		MaxRTTMs    float64       `json:"maxRttMs"`
+		Probes      []PingProbe   `json:"probes"`
+	}
+
+	PingProbe struct {
+		Seq   int     `json:"seq"`
+		Lost  bool    `json:"lost"`
+		RTTMs float64 `json:"rttMs,omitempty"`
+		TTL   int     `json:"ttl,omitempty"`
+	}
+
+	TracerouteResult struct {
+		Path ProbePathInfo `json:"path"`
+		Hops []TraceHop    `json:"hops"`
+	}
+
+	TraceHop struct {
+		Hop         int          `json:"hop"`
+		LossPercent float64      `json:"lossPercent"`
+		AvgRTTMs    float64      `json:"avgRttMs"`
+		Probes      []TraceProbe `json:"probes"`
+	}
+
+	TraceProbe struct {
+		Addr  string  `json:"addr,omitempty"`
+		Lost  bool    `json:"lost"`
+		RTTMs float64 `json:"rttMs,omitempty"`
+	}
+
+	PathMTUResult struct {
+		Path    ProbePathInfo `json:"path"`
+		PathMTU int           `json:"pathMtu"`
+		Probes  []MTUProbe    `json:"probes"`
+	}
+
+	MTUProbe struct {
+		MTU    int  `json:"mtu"`
+		Passed bool `json:"passed"`
+	}
+)
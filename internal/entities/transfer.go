@@ -0,0 +1,11 @@
+package entities
+
+type TransferChunk struct {
+	TransferID string `json:"transferId"`
+	Kind       string `json:"kind"`
+	Index      int    `json:"index"`
+	Total      int    `json:"total"`
+	Size       int    `json:"size"`   // size of whole payload
+	SHA256     string `json:"sha256"` // checksum of whole payload
+	Data       []byte `json:"data"`
+}
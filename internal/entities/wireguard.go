@@ -0,0 +1,15 @@
+package entities
+
+import "github.com/Fivegen-LLC/sdwan-lib/pkg/config"
+
+// WgPeersPatch batch of peer changes for one wireguard interface, applied atomically.
+type WgPeersPatch struct {
+	Interface string          `json:"interface" validate:"required"` // example: wgc-1_0
+	Add       []config.WgPeer `json:"add" validate:"dive"`
+	Update    []config.WgPeer `json:"update" validate:"dive"` // peers are matched by public key
+	Remove    []string        `json:"remove" validate:"dive,required"`
+}
+
+func (p WgPeersPatch) IsEmpty() bool {
+	return len(p.Add) == 0 && len(p.Update) == 0 && len(p.Remove) == 0
+}
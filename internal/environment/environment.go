@@ -1,8 +1,15 @@
 package environment
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Fivegen-LLC/sdwan-lib/pkg/validator"
+	"github.com/nats-io/nats.go"
 	"github.com/samber/lo"
 	"github.com/spf13/viper"
 
@@ -14,41 +21,232 @@ type Environment struct {
 }
 
 type Agent struct {
+	ConfigFile   string // optional yaml or toml file, env vars override its values
 	EndPoint     string
 	DeviceID     string
-	DeviceType   string
+	DeviceType   string `validate:"required"`
 	WgConfigRoot string
-	LogfilePath  string
-	LogLevel     string
+	LogfilePath  string `validate:"required"`
+	LogLevel     string `validate:"oneof=trace debug info warn error fatal panic disabled"`
+	DebugWS      bool   // allow profiling through orchestrator websocket
+
+	// RequirePackageDigest rejects install of packages without SHA-256 digest, applied on start only
+	RequirePackageDigest bool
+
+	// applied on start only
+	NATSURL            string        `validate:"required,url"`
+	WSPingPeriod       time.Duration `validate:"min=1s"`
+	BadgerDir          string        `validate:"required,startswith=/"`
+	BadgerMemTableSize int64         `validate:"min=1048576"`
+	LTEPINKeyFile      string        `validate:"required,startswith=/"`      // encryption key of saved sim PINs and APN passwords
+	IncomingDirs       []string      `validate:"dive,required,startswith=/"` // watched for offline bundles
+	OfflineKeysDir     string        `validate:"required,startswith=/"`      // keys trusted to sign offline bundles
+	CaptureDir         string        `validate:"required,startswith=/"`      // packet capture files
+	LogRotation        LogRotation
+
+	// applied on start and on settings reload
+	Tunables Tunables
+}
+
+type LogRotation struct {
+	MaxSizeMB  int `validate:"min=1"`
+	MaxAgeDays int `validate:"min=0"`
+	MaxBackups int `validate:"min=0"`
+	Compress   bool
+}
+
+// Tunables settings safe to change without agent restart.
+type Tunables struct {
+	DeviceInitTimeout      time.Duration `validate:"min=10s"`
+	InstallPackagesTimeout time.Duration `validate:"min=5s"`
+	ShutdownTimeout        time.Duration `validate:"min=1s"`
+	DiscoveryInterval      time.Duration `validate:"min=1s"`
 }
 
 func New() (e Environment, err error) {
+	configFile := os.Getenv("AGENT_CONFIG_FILE")
+	if lo.IsEmpty(configFile) {
+		configFile = constants.AgentSettingsPath
+	}
+
+	if e, err = Load(configFile); err != nil {
+		return e, fmt.Errorf("New: %w", err)
+	}
+
+	return e, nil
+}
+
+// Load reads settings from config file (skipped if file does not exist) merged with env vars and validates them.
+func Load(configFile string) (e Environment, err error) {
 	v := viper.New()
-	v.AutomaticEnv()
+	setDefaults(v)
+	if err = bindEnvs(v); err != nil {
+		return e, fmt.Errorf("Load: %w", err)
+	}
+
+	if _, statErr := os.Stat(configFile); statErr == nil {
+		v.SetConfigFile(configFile)
+		if err = v.ReadInConfig(); err != nil {
+			return e, fmt.Errorf("Load: %w", err)
+		}
+		e.Agent.ConfigFile = configFile
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return e, fmt.Errorf("Load: %w", statErr)
+	}
 
 	// linux env
-	e.Agent.DeviceType = v.GetString("SDWAN_DEVICE")
+	e.Agent.DeviceType = v.GetString("deviceType")
 	if lo.IsEmpty(e.Agent.DeviceType) {
-		return e, fmt.Errorf("New: device type env is empty")
+		return e, fmt.Errorf("Load: device type env is empty")
 	}
 
 	// agent settings
-	v.SetEnvPrefix("AGENT")
-	e.Agent.EndPoint = v.GetString("ENDPOINT")
-	e.Agent.DeviceID = v.GetString("ID")
-	e.Agent.WgConfigRoot = v.GetString("CFG_ROOT")
-	e.Agent.LogfilePath = v.GetString("LOG_FILE")
-	if lo.IsEmpty(e.Agent.LogfilePath) {
-		e.Agent.LogfilePath = constants.DefaultLogfilePath
+	e.Agent.EndPoint = v.GetString("endpoint")
+	e.Agent.DeviceID = v.GetString("id")
+	e.Agent.WgConfigRoot = v.GetString("cfgRoot")
+	e.Agent.LogfilePath = v.GetString("log.file")
+	e.Agent.LogLevel = v.GetString("log.level")
+	e.Agent.DebugWS = v.GetBool("debugWs")
+	e.Agent.RequirePackageDigest = v.GetBool("requirePackageDigest")
+
+	e.Agent.IncomingDirs = []string{constants.IncomingDirectory}
+	for _, item := range v.GetStringSlice("incomingDirs") {
+		for _, dir := range strings.Split(item, ",") {
+			if dir = strings.TrimSpace(dir); !lo.IsEmpty(dir) && !lo.Contains(e.Agent.IncomingDirs, dir) {
+				e.Agent.IncomingDirs = append(e.Agent.IncomingDirs, dir)
+			}
+		}
+	}
+
+	e.Agent.NATSURL = v.GetString("natsUrl")
+	e.Agent.WSPingPeriod = v.GetDuration("wsPingPeriod")
+	e.Agent.BadgerDir = v.GetString("badgerDir")
+	e.Agent.BadgerMemTableSize = v.GetInt64("badgerMemTableSize")
+	e.Agent.LTEPINKeyFile = v.GetString("ltePinKeyFile")
+	e.Agent.OfflineKeysDir = v.GetString("offlineKeysDir")
+	e.Agent.CaptureDir = v.GetString("captureDir")
+	e.Agent.LogRotation = LogRotation{
+		MaxSizeMB:  v.GetInt("log.maxSizeMb"),
+		MaxAgeDays: v.GetInt("log.maxAgeDays"),
+		MaxBackups: v.GetInt("log.maxBackups"),
+		Compress:   v.GetBool("log.compress"),
+	}
+	e.Agent.Tunables = Tunables{
+		DeviceInitTimeout:      v.GetDuration("timeouts.deviceInit"),
+		InstallPackagesTimeout: v.GetDuration("timeouts.installPackages"),
+		ShutdownTimeout:        v.GetDuration("timeouts.shutdown"),
+		DiscoveryInterval:      v.GetDuration("intervals.discovery"),
 	}
-	e.Agent.LogLevel = v.GetString("LOG_LEVEL")
-	if lo.IsEmpty(e.Agent.LogLevel) {
-		e.Agent.LogLevel = "info"
+
+	if err = validator.Validator.Struct(e.Agent); err != nil {
+		return e, fmt.Errorf("Load: %w", err)
 	}
 
 	return e, nil
 }
 
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("log.file", constants.DefaultLogfilePath)
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.maxSizeMb", 15)
+	v.SetDefault("log.maxAgeDays", 30)
+	v.SetDefault("log.maxBackups", 10)
+	v.SetDefault("log.compress", true)
+
+	v.SetDefault("natsUrl", nats.DefaultURL)
+	v.SetDefault("wsPingPeriod", constants.WSPingPeriod)
+	v.SetDefault("badgerDir", constants.AgentConfigPath)
+	v.SetDefault("badgerMemTableSize", 64<<17) // ~8MB
+	v.SetDefault("ltePinKeyFile", constants.LTEPINKeyPath)
+	v.SetDefault("offlineKeysDir", constants.OfflineBundleKeysDirectory)
+	v.SetDefault("captureDir", constants.PacketCaptureDirectory)
+
+	v.SetDefault("timeouts.deviceInit", 2*time.Minute)
+	v.SetDefault("timeouts.installPackages", 30*time.Second)
+	v.SetDefault("timeouts.shutdown", 30*time.Second)
+	v.SetDefault("intervals.discovery", 20*time.Second)
+}
+
+// bindEnvs binds env vars to settings keys, env vars take precedence over config file.
+func bindEnvs(v *viper.Viper) (err error) {
+	for key, envName := range map[string]string{
+		"deviceType":   "SDWAN_DEVICE",
+		"endpoint":     "AGENT_ENDPOINT",
+		"id":           "AGENT_ID",
+		"cfgRoot":      "AGENT_CFG_ROOT",
+		"log.file":     "AGENT_LOG_FILE",
+		"log.level":    "AGENT_LOG_LEVEL",
+		"debugWs":      "AGENT_DEBUG_WS",
+		"incomingDirs": "AGENT_INCOMING_DIRS",
+		"natsUrl":      "AGENT_NATS_URL",
+
+		"requirePackageDigest": "AGENT_REQUIRE_PACKAGE_DIGEST",
+	} {
+		if err = v.BindEnv(key, envName); err != nil {
+			return fmt.Errorf("bindEnvs: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (e Agent) IsDebug() bool {
 	return e.LogLevel == "debug"
 }
+
+// RestartRequired returns names of changed settings which are applied on agent start only.
+func (e Agent) RestartRequired(newAgent Agent) (settings []string) {
+	if e.EndPoint != newAgent.EndPoint || e.DeviceID != newAgent.DeviceID || e.DeviceType != newAgent.DeviceType {
+		settings = append(settings, "agent identity")
+	}
+
+	if e.WgConfigRoot != newAgent.WgConfigRoot {
+		settings = append(settings, "cfgRoot")
+	}
+
+	if e.LogfilePath != newAgent.LogfilePath || e.LogRotation != newAgent.LogRotation {
+		settings = append(settings, "log file")
+	}
+
+	if e.DebugWS != newAgent.DebugWS {
+		settings = append(settings, "debugWs")
+	}
+
+	if e.RequirePackageDigest != newAgent.RequirePackageDigest {
+		settings = append(settings, "requirePackageDigest")
+	}
+
+	if !slices.Equal(e.IncomingDirs, newAgent.IncomingDirs) {
+		settings = append(settings, "incomingDirs")
+	}
+
+	if e.NATSURL != newAgent.NATSURL {
+		settings = append(settings, "natsUrl")
+	}
+
+	if e.WSPingPeriod != newAgent.WSPingPeriod {
+		settings = append(settings, "wsPingPeriod")
+	}
+
+	if e.BadgerDir != newAgent.BadgerDir {
+		settings = append(settings, "badgerDir")
+	}
+
+	if e.BadgerMemTableSize != newAgent.BadgerMemTableSize {
+		settings = append(settings, "badgerMemTableSize")
+	}
+
+	if e.LTEPINKeyFile != newAgent.LTEPINKeyFile {
+		settings = append(settings, "ltePinKeyFile")
+	}
+
+	if e.OfflineKeysDir != newAgent.OfflineKeysDir {
+		settings = append(settings, "offlineKeysDir")
+	}
+
+	if e.CaptureDir != newAgent.CaptureDir {
+		settings = append(settings, "captureDir")
+	}
+
+	return settings
+}
@@ -0,0 +1,90 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("SDWAN_DEVICE", constants.DeviceTypeCPE)
+
+	t.Run("defaults without config file", func(t *testing.T) {
+		e, err := Load(filepath.Join(t.TempDir(), "agent.yaml"))
+		require.NoError(t, err)
+		require.Empty(t, e.Agent.ConfigFile)
+		require.Equal(t, nats.DefaultURL, e.Agent.NATSURL)
+		require.Equal(t, constants.DefaultLogfilePath, e.Agent.LogfilePath)
+		require.Equal(t, constants.AgentConfigPath, e.Agent.BadgerDir)
+		require.Equal(t, constants.LTEPINKeyPath, e.Agent.LTEPINKeyFile)
+		require.Equal(t, constants.OfflineBundleKeysDirectory, e.Agent.OfflineKeysDir)
+		require.Equal(t, constants.PacketCaptureDirectory, e.Agent.CaptureDir)
+		require.Equal(t, LogRotation{MaxSizeMB: 15, MaxAgeDays: 30, MaxBackups: 10, Compress: true}, e.Agent.LogRotation)
+		require.Equal(t, Tunables{
+			DeviceInitTimeout:      2 * time.Minute,
+			InstallPackagesTimeout: 30 * time.Second,
+			ShutdownTimeout:        30 * time.Second,
+			DiscoveryInterval:      20 * time.Second,
+		}, e.Agent.Tunables)
+	})
+
+	t.Run("config file merged with env", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "agent.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(`
+natsUrl: nats://127.0.0.1:4333
+incomingDirs: [/media/usb]
+badgerDir: /data/sdwan/badger
+log:
+  level: debug
+  maxSizeMb: 50
+timeouts:
+  deviceInit: 5m
+intervals:
+  discovery: 1m
+`), 0o600))
+		t.Setenv("AGENT_LOG_LEVEL", "warn")
+
+		e, err := Load(configFile)
+		require.NoError(t, err)
+		require.Equal(t, configFile, e.Agent.ConfigFile)
+		require.Equal(t, "nats://127.0.0.1:4333", e.Agent.NATSURL)
+		require.Equal(t, "warn", e.Agent.LogLevel)
+		require.Equal(t, 50, e.Agent.LogRotation.MaxSizeMB)
+		require.Equal(t, []string{constants.IncomingDirectory, "/media/usb"}, e.Agent.IncomingDirs)
+		require.Equal(t, "/data/sdwan/badger", e.Agent.BadgerDir)
+		require.Equal(t, 5*time.Minute, e.Agent.Tunables.DeviceInitTimeout)
+		require.Equal(t, time.Minute, e.Agent.Tunables.DiscoveryInterval)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "agent.toml")
+		require.NoError(t, os.WriteFile(configFile, []byte("[intervals]\ndiscovery = \"10ms\"\n"), 0o600))
+
+		_, err := Load(configFile)
+		require.Error(t, err)
+
+		// paths must be absolute
+		require.NoError(t, os.WriteFile(configFile, []byte("captureDir = \"capture\"\n"), 0o600))
+		_, err = Load(configFile)
+		require.Error(t, err)
+	})
+}
+
+func TestAgent_RestartRequired(t *testing.T) {
+	t.Setenv("SDWAN_DEVICE", constants.DeviceTypeCPE)
+
+	e, err := Load(filepath.Join(t.TempDir(), "agent.yaml"))
+	require.NoError(t, err)
+
+	newAgent := e.Agent
+	newAgent.BadgerDir = "/data/badger"
+	newAgent.CaptureDir = "/data/capture"
+	newAgent.Tunables.DiscoveryInterval = time.Minute
+	require.Equal(t, []string{"badgerDir", "captureDir"}, e.Agent.RestartRequired(newAgent))
+}
@@ -0,0 +1,23 @@
+package environment
+
+import "sync/atomic"
+
+// Settings holds tunables replaced on settings reload, services read them on every use.
+type Settings struct {
+	tunables atomic.Pointer[Tunables]
+}
+
+func NewSettings(tunables Tunables) *Settings {
+	s := &Settings{}
+	s.Store(tunables)
+
+	return s
+}
+
+func (s *Settings) Tunables() Tunables {
+	return *s.tunables.Load()
+}
+
+func (s *Settings) Store(tunables Tunables) {
+	s.tunables.Store(&tunables)
+}
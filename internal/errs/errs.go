@@ -23,3 +23,75 @@ var (
 	ErrPrimaryNotFound = fmt.Errorf("primary not found")
 	ErrAPIError        = fmt.Errorf("api error")
 )
+
+var (
+	ErrCaptureInProgress = errors.New("packet capture in progress")
+	ErrCaptureNotFound   = errors.New("packet capture not found")
+	ErrDevicePressure    = errors.New("device is under pressure")
+	ErrInvalidFilter     = errors.New("invalid capture filter")
+)
+
+var (
+	ErrProfilingInProgress = errors.New("profiling in progress")
+	ErrProfileNotFound     = errors.New("profile not found or expired")
+	ErrProfileNotReady     = errors.New("profile is not ready")
+	ErrDebugNotAllowed     = errors.New("debug over websocket is not allowed")
+)
+
+var (
+	ErrModemNotFound      = errors.New("modem not found")
+	ErrAPNProfileNotFound = errors.New("APN profile not found")
+	ErrGSMConnection      = errors.New("gsm connection is ambiguous or not found")
+)
+
+var (
+	ErrHealthGateFailed  = errors.New("post-upgrade health gate failed")
+	ErrUpgradeRolledBack = errors.New("upgrade rolled back to previous versions")
+)
+
+var (
+	ErrPackageArchiveNotFound  = errors.New("package archive not found")
+	ErrPackageDigestMissing    = errors.New("package digest is missing")
+	ErrInvalidPackage          = errors.New("invalid package name or version")
+	ErrPackageDigestMismatch   = errors.New("package archive digest mismatch")
+	ErrPackageSignatureInvalid = errors.New("package archive signature is invalid")
+	ErrNoTrustedKeys           = errors.New("no trusted keys found")
+)
+
+var (
+	ErrDownloadJobNotFound = errors.New("download job not found")
+)
+
+var (
+	ErrUnsupportedBackupVersion = errors.New("unsupported config backup version")
+	ErrBackupChecksumMismatch   = errors.New("config backup checksum mismatch")
+	ErrBackupPassphrase         = errors.New("config backup passphrase is missing or wrong")
+	ErrBackupSerialMismatch     = errors.New("config backup belongs to another device")
+	ErrBackupRedacted           = errors.New("config backup has masked secrets")
+)
+
+var (
+	ErrConfigValidation = errors.New("config validation failed")
+)
+
+var (
+	ErrWgInterfaceNotFound = errors.New("wireguard interface not found")
+	ErrWgPeerNotFound      = errors.New("wireguard peer not found")
+	ErrWgPeerExists        = errors.New("wireguard peer already exists")
+	ErrWgPeerDuplicated    = errors.New("wireguard peer is changed more than once in batch")
+)
+
+var (
+	ErrBadRequest            = errors.New("bad request")
+	ErrConfigSectionNotFound = errors.New("config section not found")
+	ErrTransitionInProgress  = errors.New("state transition in progress")
+)
+
+var (
+	ErrAgentShuttingDown = errors.New("agent is shutting down")
+	ErrLoopStalled       = errors.New("loop is not making progress")
+)
+
+var (
+	ErrTooManyLogStreams = errors.New("too many log streams")
+)
@@ -0,0 +1,58 @@
+// Package inflight tracks running request handlers so that shutdown can wait for them.
+package inflight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Tracker counts running handlers, new handlers are refused after drain started.
+type Tracker struct {
+	mx       sync.Mutex
+	draining bool
+	wg       sync.WaitGroup
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Acquire registers new handler, returns false when agent is shutting down.
+// Release must be called for every successful Acquire.
+func (t *Tracker) Acquire() bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.draining {
+		return false
+	}
+
+	t.wg.Add(1)
+	return true
+}
+
+// Release marks handler as finished.
+func (t *Tracker) Release() {
+	t.wg.Done()
+}
+
+// Drain stops accepting new handlers and waits for running ones until ctx is done.
+func (t *Tracker) Drain(ctx context.Context) (err error) {
+	t.mx.Lock()
+	t.draining = true
+	t.mx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Drain: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,25 @@
+package inflight
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerDrain(t *testing.T) {
+	tracker := NewTracker()
+	require.True(t, tracker.Acquire())
+
+	// running handler blocks drain until deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, tracker.Drain(ctx), context.DeadlineExceeded)
+
+	// new handlers are refused while draining
+	require.False(t, tracker.Acquire())
+
+	tracker.Release()
+	require.NoError(t, tracker.Drain(context.Background()))
+}
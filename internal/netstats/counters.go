@@ -0,0 +1,59 @@
+// Package netstats reads network interface counters from sysfs.
+package netstats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+)
+
+const SysClassNetPath = "/sys/class/net"
+
+const (
+	CounterRxBytes   = "rx_bytes"
+	CounterTxBytes   = "tx_bytes"
+	CounterRxPackets = "rx_packets"
+	CounterTxPackets = "tx_packets"
+	CounterRxErrors  = "rx_errors"
+	CounterTxErrors  = "tx_errors"
+	CounterRxDropped = "rx_dropped"
+	CounterTxDropped = "tx_dropped"
+)
+
+// ReadCounters reads all counters of network interface.
+func ReadCounters(sysClassNetPath, netdev string) (counters entities.PortCounters, err error) {
+	for name, counter := range map[string]*uint64{
+		CounterRxBytes:   &counters.RxBytes,
+		CounterTxBytes:   &counters.TxBytes,
+		CounterRxPackets: &counters.RxPackets,
+		CounterTxPackets: &counters.TxPackets,
+		CounterRxErrors:  &counters.RxErrors,
+		CounterTxErrors:  &counters.TxErrors,
+		CounterRxDropped: &counters.RxDropped,
+		CounterTxDropped: &counters.TxDropped,
+	} {
+		if *counter, err = ReadCounter(sysClassNetPath, netdev, name); err != nil {
+			return counters, fmt.Errorf("ReadCounters: %w", err)
+		}
+	}
+
+	return counters, nil
+}
+
+// ReadCounter reads single counter (e.g. rx_bytes) of network interface.
+func ReadCounter(sysClassNetPath, netdev, name string) (value uint64, err error) {
+	data, err := os.ReadFile(filepath.Join(sysClassNetPath, netdev, "statistics", name))
+	if err != nil {
+		return 0, fmt.Errorf("ReadCounter: %w", err)
+	}
+
+	if value, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+		return 0, fmt.Errorf("ReadCounter: %w", err)
+	}
+
+	return value, nil
+}
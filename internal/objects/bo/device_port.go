@@ -8,6 +8,7 @@ import (
 	"github.com/Fivegen-LLC/sdwan-lib/pkg/netutils"
 
 	"github.com/Fivegen-LLC/sdwan-agent/internal/constants"
+	"github.com/Fivegen-LLC/sdwan-agent/internal/entities"
 	"github.com/Fivegen-LLC/sdwan-agent/internal/objects/dto"
 )
 
@@ -78,6 +79,7 @@ type DevicePort struct {
 	SubnetMask string
 	Gateway    string
 	DNS        []string
+	Stats      *entities.PortStats // nil until counters are sampled
 }
 
 type DevicePorts []DevicePort
@@ -86,7 +88,7 @@ func (p DevicePort) ToDto() dto.DevicePort {
 	ips := make([]string, 0, len(p.IPs))
 	ips = append(ips, p.IPs...)
 
-	return dto.DevicePort{
+	port := dto.DevicePort{
 		Name:       p.Name,
 		State:      p.State,
 		Mac:        p.Mac,
@@ -96,6 +98,12 @@ func (p DevicePort) ToDto() dto.DevicePort {
 		Gateway:    p.Gateway,
 		DNS:        slices.Clone(p.DNS),
 	}
+	if p.Stats != nil {
+		port.Counters = &p.Stats.Counters
+		port.Rates = &p.Stats.Rates
+	}
+
+	return port
 }
 
 func (p DevicePorts) ToDto() dto.DevicePorts {
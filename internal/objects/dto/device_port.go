@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/Fivegen-LLC/sdwan-agent/internal/entities"
+
 type DevicePortConfig struct {
 	Name   string         `json:"name"`
 	Type   string         `json:"type"`
@@ -40,6 +42,9 @@ type DevicePort struct {
 	SubnetMask string   `json:"subnetMask"`
 	Gateway    string   `json:"gateway"`
 	DNS        []string `json:"dns"`
+
+	Counters *entities.PortCounters `json:"counters,omitempty"`
+	Rates    *entities.PortRates    `json:"rates,omitempty"`
 }
 
 type DevicePorts []DevicePort
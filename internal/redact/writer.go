@@ -0,0 +1,23 @@
+package redact
+
+import "io"
+
+// Writer masks secrets in written json log lines.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w: w,
+	}
+}
+
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if _, err = w.w.Write(Bytes(p)); err != nil {
+		return 0, err
+	}
+
+	// length of original line is reported, masked line may be shorter or longer
+	return len(p), nil
+}
@@ -0,0 +1,23 @@
+package shellcmd
+
+// Cmd shell command with explicit argv. Unlike commands.CustomCmd it is not split
+// on spaces, so every argument reaches the process as a single element.
+type Cmd struct {
+	name string
+	args []string
+}
+
+func New(name string, args ...string) *Cmd {
+	return &Cmd{
+		name: name,
+		args: args,
+	}
+}
+
+func (c *Cmd) Name() string {
+	return c.name
+}
+
+func (c *Cmd) Args() []string {
+	return c.args
+}